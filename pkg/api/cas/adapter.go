@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cas
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// pingContent is the sentinel content that is written to (and read from) CAS by default ping implementation.
+// nolint:gochecknoglobals
+var pingContent = []byte("sidetree-cas-ping")
+
+// NewContextClient returns context-aware CAS client for the given client. If the given client doesn't
+// implement ContextClient, blocking reads and writes are abandoned (but not cancelled) once context is done.
+func NewContextClient(client Client) ContextClient {
+	if c, ok := client.(ContextClient); ok {
+		return c
+	}
+
+	return &contextAdapter{client: client}
+}
+
+type contextAdapter struct {
+	client Client
+}
+
+type result struct {
+	address string
+	content []byte
+	err     error
+}
+
+// WriteContext writes the given content to CAS; it returns as soon as context is done.
+func (a *contextAdapter) WriteContext(ctx context.Context, content []byte) (string, error) {
+	r := run(ctx, func() result {
+		address, err := a.client.Write(content)
+
+		return result{address: address, err: err}
+	})
+
+	return r.address, r.err
+}
+
+// ReadContext reads the content of the given address in CAS; it returns as soon as context is done.
+func (a *contextAdapter) ReadContext(ctx context.Context, address string) ([]byte, error) {
+	r := run(ctx, func() result {
+		content, err := a.client.Read(address)
+
+		return result{content: content, err: err}
+	})
+
+	return r.content, r.err
+}
+
+// NewSizeClient returns size-aware CAS client for the given client. If the given client doesn't
+// implement SizeClient, size is determined by reading the content.
+func NewSizeClient(client Client) SizeClient {
+	if c, ok := client.(SizeClient); ok {
+		return c
+	}
+
+	return &sizeAdapter{client: client}
+}
+
+type sizeAdapter struct {
+	client Client
+}
+
+// Size reads the content of the given address and returns its size.
+func (a *sizeAdapter) Size(address string) (int, error) {
+	content, err := a.client.Read(address)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(content), nil
+}
+
+// NewPingClient returns ping-capable CAS client for the given client. If the given client doesn't
+// implement PingClient, ping writes a tiny sentinel content to CAS and reads it back.
+func NewPingClient(client Client) PingClient {
+	if c, ok := client.(PingClient); ok {
+		return c
+	}
+
+	return &pingAdapter{client: NewContextClient(client)}
+}
+
+type pingAdapter struct {
+	client ContextClient
+}
+
+// Ping writes sentinel content to CAS and reads it back; it returns as soon as context is done.
+func (a *pingAdapter) Ping(ctx context.Context) error {
+	address, err := a.client.WriteContext(ctx, pingContent)
+	if err != nil {
+		return fmt.Errorf("ping CAS: write sentinel content: %w", err)
+	}
+
+	content, err := a.client.ReadContext(ctx, address)
+	if err != nil {
+		return fmt.Errorf("ping CAS: read sentinel content at address[%s]: %w", address, err)
+	}
+
+	if !bytes.Equal(content, pingContent) {
+		return fmt.Errorf("ping CAS: unexpected sentinel content at address[%s]", address)
+	}
+
+	return nil
+}
+
+func run(ctx context.Context, fnc func() result) result {
+	if err := ctx.Err(); err != nil {
+		return result{err: err}
+	}
+
+	// context can never be done (e.g. background context)
+	if ctx.Done() == nil {
+		return fnc()
+	}
+
+	// buffered so that goroutine can exit if nobody is waiting for the result
+	resultChan := make(chan result, 1)
+
+	go func() {
+		resultChan <- fnc()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return result{err: ctx.Err()}
+	case r := <-resultChan:
+		return r
+	}
+}
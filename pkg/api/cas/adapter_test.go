@@ -0,0 +1,216 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cas
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContextClient(t *testing.T) {
+	t.Run("success - context-aware client is returned as is", func(t *testing.T) {
+		client := &mockContextClient{}
+
+		require.Equal(t, client, NewContextClient(client))
+	})
+
+	t.Run("success - write and read", func(t *testing.T) {
+		c := NewContextClient(&mockClient{})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		address, err := c.WriteContext(ctx, []byte("content"))
+		require.NoError(t, err)
+		require.Equal(t, "address", address)
+
+		content, err := c.ReadContext(context.Background(), address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+	})
+
+	t.Run("error - client error", func(t *testing.T) {
+		c := NewContextClient(&mockClient{err: errors.New("client error")})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		address, err := c.WriteContext(ctx, []byte("content"))
+		require.EqualError(t, err, "client error")
+		require.Empty(t, address)
+
+		content, err := c.ReadContext(ctx, "address")
+		require.EqualError(t, err, "client error")
+		require.Nil(t, content)
+	})
+
+	t.Run("error - context is cancelled", func(t *testing.T) {
+		c := NewContextClient(&mockClient{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		address, err := c.WriteContext(ctx, []byte("content"))
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Empty(t, address)
+
+		content, err := c.ReadContext(ctx, "address")
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Nil(t, content)
+	})
+
+	t.Run("error - blocking call is abandoned", func(t *testing.T) {
+		c := NewContextClient(&mockClient{delay: time.Second})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		content, err := c.ReadContext(ctx, "address")
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Nil(t, content)
+		require.True(t, time.Since(start) < time.Second)
+	})
+}
+
+func TestNewSizeClient(t *testing.T) {
+	t.Run("success - size-aware client is returned as is", func(t *testing.T) {
+		client := &mockSizeClient{}
+
+		require.Equal(t, client, NewSizeClient(client))
+	})
+
+	t.Run("success - size is determined by reading content", func(t *testing.T) {
+		size, err := NewSizeClient(&mockClient{}).Size("address")
+		require.NoError(t, err)
+		require.Equal(t, len("content"), size)
+	})
+
+	t.Run("error - client error", func(t *testing.T) {
+		size, err := NewSizeClient(&mockClient{err: errors.New("client error")}).Size("address")
+		require.EqualError(t, err, "client error")
+		require.Zero(t, size)
+	})
+}
+
+func TestNewPingClient(t *testing.T) {
+	t.Run("success - ping-capable client is returned as is", func(t *testing.T) {
+		client := &mockPingClient{}
+
+		require.Equal(t, client, NewPingClient(client))
+	})
+
+	t.Run("success - sentinel content is written and read back", func(t *testing.T) {
+		client := &memoryClient{content: make(map[string][]byte)}
+
+		require.NoError(t, NewPingClient(client).Ping(context.Background()))
+		require.Len(t, client.content, 1)
+	})
+
+	t.Run("error - write error", func(t *testing.T) {
+		err := NewPingClient(&mockClient{err: errors.New("client error")}).Ping(context.Background())
+		require.EqualError(t, err, "ping CAS: write sentinel content: client error")
+	})
+
+	t.Run("error - read error", func(t *testing.T) {
+		client := &memoryClient{content: make(map[string][]byte), readErr: errors.New("read error")}
+
+		err := NewPingClient(client).Ping(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ping CAS: read sentinel content at address")
+		require.Contains(t, err.Error(), "read error")
+	})
+
+	t.Run("error - unexpected content", func(t *testing.T) {
+		err := NewPingClient(&mockClient{}).Ping(context.Background())
+		require.EqualError(t, err, "ping CAS: unexpected sentinel content at address[address]")
+	})
+
+	t.Run("error - context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := NewPingClient(&mockClient{}).Ping(ctx)
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+type mockClient struct {
+	delay time.Duration
+	err   error
+}
+
+func (m *mockClient) Write([]byte) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+
+	return "address", nil
+}
+
+func (m *mockClient) Read(string) ([]byte, error) {
+	time.Sleep(m.delay)
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return []byte("content"), nil
+}
+
+type mockContextClient struct {
+	mockClient
+}
+
+func (m *mockContextClient) WriteContext(context.Context, []byte) (string, error) {
+	return m.Write(nil)
+}
+
+func (m *mockContextClient) ReadContext(_ context.Context, address string) ([]byte, error) {
+	return m.Read(address)
+}
+
+type mockSizeClient struct {
+	mockClient
+}
+
+func (m *mockSizeClient) Size(string) (int, error) {
+	return 0, nil
+}
+
+type mockPingClient struct {
+	mockClient
+}
+
+func (m *mockPingClient) Ping(context.Context) error {
+	return nil
+}
+
+type memoryClient struct {
+	content map[string][]byte
+	readErr error
+}
+
+func (m *memoryClient) Write(content []byte) (string, error) {
+	address := string(content)
+	m.content[address] = content
+
+	return address, nil
+}
+
+func (m *memoryClient) Read(address string) ([]byte, error) {
+	if m.readErr != nil {
+		return nil, m.readErr
+	}
+
+	return m.content[address], nil
+}
@@ -6,12 +6,51 @@ SPDX-License-Identifier: Apache-2.0
 
 package cas
 
+import (
+	"context"
+	"errors"
+)
+
+// ErrContentNotFound is returned (wrapped) by CAS client if there is no content for the requested address.
+var ErrContentNotFound = errors.New("content not found")
+
 // Client defines interface for accessing the underlying content addressable storage.
 type Client interface {
 	// Write writes the given content to CASClient.
 	Write(content []byte) (string, error)
 
 	// Read reads the content of the given address in CASClient.
-	// returns the content of the given address.
+	// returns the content of the given address; error wrapping ErrContentNotFound is returned if there is no content.
 	Read(address string) ([]byte, error)
 }
+
+// ContextClient defines context-aware interface for accessing the underlying content addressable storage.
+type ContextClient interface {
+	// WriteContext writes the given content to CASClient; it returns as soon as context is done.
+	WriteContext(ctx context.Context, content []byte) (string, error)
+
+	// ReadContext reads the content of the given address in CASClient; it returns as soon as context is done.
+	// returns the content of the given address.
+	ReadContext(ctx context.Context, address string) ([]byte, error)
+}
+
+// SizeClient defines interface for retrieving size of the content in the underlying content addressable storage
+// without transferring the content.
+type SizeClient interface {
+	// Size returns the size (in bytes) of the content of the given address in CASClient; error wrapping
+	// ErrContentNotFound is returned if there is no content.
+	Size(address string) (int, error)
+}
+
+// AddressClient defines interface for calculating the address of the content in the underlying content
+// addressable storage without storing the content.
+type AddressClient interface {
+	// Address returns the address that CASClient would store the given content at.
+	Address(content []byte) (string, error)
+}
+
+// PingClient defines interface for checking whether the underlying content addressable storage is reachable.
+type PingClient interface {
+	// Ping returns an error if CASClient is not reachable; it returns as soon as context is done.
+	Ping(ctx context.Context) error
+}
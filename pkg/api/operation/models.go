@@ -6,6 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 
 package operation
 
+import "errors"
+
+// ErrNotFound is returned (wrapped) by operation store if there are no operations for the requested unique suffix.
+var ErrNotFound = errors.New("uniqueSuffix not found in the store")
+
 // Operation holds minimum information required for parsing/validating client request.
 type Operation struct {
 
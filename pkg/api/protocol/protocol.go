@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package protocol
 
 import (
+	"context"
+	"errors"
+
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
@@ -46,12 +49,24 @@ type Protocol struct {
 	// MaxDeltaSize is maximum size of operation's delta property.
 	MaxDeltaSize uint `json:"maxDeltaSize"`
 
+	// MaxPatchesPerDelta is maximum number of patches in operation's delta property (zero means no limit).
+	MaxPatchesPerDelta uint `json:"maxPatchesPerDelta"`
+
+	// MaxDocumentSize is maximum size (in bytes) of the document after operation patches have been applied
+	// (zero means no limit). Operation whose patches would exceed it is treated as operation with invalid patches.
+	MaxDocumentSize uint `json:"maxDocumentSize"`
+
 	// MaxCasUriLength is maximum length of CAS URI in batch files.
 	MaxCasURILength uint `json:"maxCasUriLength"`
 
 	// CompressionAlgorithm is file compression algorithm.
 	CompressionAlgorithm string `json:"compressionAlgorithm"`
 
+	// ProofFileCompressionAlgorithm is compression algorithm for (core and provisional) proof files; CompressionAlgorithm
+	// is used if not set and NoCompression means that proof files are not compressed. Readers and writers of batch files
+	// have to use the same protocol parameters since algorithm is not encoded in batch files.
+	ProofFileCompressionAlgorithm string `json:"proofFileCompressionAlgorithm,omitempty"`
+
 	// MaxCoreIndexFileSize is maximum allowed size (in bytes) of core index file stored in CAS.
 	MaxCoreIndexFileSize uint `json:"maxCoreIndexFileSize"`
 
@@ -83,6 +98,18 @@ type Protocol struct {
 	MaxMemoryDecompressionFactor uint `json:"maxMemoryDecompressionFactor"`
 }
 
+// NoCompression is compression algorithm value for files that are not compressed.
+const NoCompression = "NONE"
+
+// GetProofFileCompressionAlgorithm returns compression algorithm for proof files.
+func (p Protocol) GetProofFileCompressionAlgorithm() string {
+	if p.ProofFileCompressionAlgorithm == "" {
+		return p.CompressionAlgorithm
+	}
+
+	return p.ProofFileCompressionAlgorithm
+}
+
 // TxnProcessor defines the functions for processing a Sidetree transaction.
 type TxnProcessor interface {
 	Process(sidetreeTxn txn.SidetreeTxn, suffixes ...string) error
@@ -108,6 +135,10 @@ type ResolutionModel struct {
 	AnchorOrigin                     interface{}
 	EquivalentReferences             []string
 	CanonicalReference               string
+	// CreatedTime is transaction time of the create operation
+	CreatedTime uint64
+	// UpdatedTime is transaction time of the last applied operation after create operation (zero if none)
+	UpdatedTime uint64
 }
 
 // OperationApplier applies the given operation to the document.
@@ -148,8 +179,13 @@ type OperationHandler interface {
 // OperationProvider retrieves the anchored operations for the given Sidetree transaction.
 type OperationProvider interface {
 	GetTxnOperations(sidetreeTxn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)
+	GetTxnOperationsContext(ctx context.Context, sidetreeTxn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)
 }
 
+// ErrUnresolvableTxn is returned (wrapped) by operation provider if transaction batch files cannot be
+// retrieved and retrying will not help (e.g. content has been removed from CAS).
+var ErrUnresolvableTxn = errors.New("unresolvable transaction")
+
 // DocumentValidator is an interface for validating document operations.
 type DocumentValidator interface {
 	IsValidOriginalDocument(payload []byte) error
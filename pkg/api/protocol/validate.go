@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+// CompressionRegistry checks whether compression algorithm is registered (e.g. compression.Registry).
+type CompressionRegistry interface {
+	IsSupported(alg string) bool
+}
+
+// Validate validates protocol parameters: size limits have to be positive, multihash algorithms
+// have to be supported and compression algorithm has to be registered with the given compression
+// registry (compression algorithm checks are skipped if registry is nil).
+// All invalid parameters are reported in the returned error.
+func (p Protocol) Validate(registry CompressionRegistry) error {
+	var errs []string
+
+	limits := []struct {
+		name  string
+		value uint
+	}{
+		{"maxOperationCount", p.MaxOperationCount},
+		{"maxOperationSize", p.MaxOperationSize},
+		{"maxOperationHashLength", p.MaxOperationHashLength},
+		{"maxDeltaSize", p.MaxDeltaSize},
+		{"maxCasUriLength", p.MaxCasURILength},
+		{"maxCoreIndexFileSize", p.MaxCoreIndexFileSize},
+		{"maxProofFileSize", p.MaxProofFileSize},
+		{"maxProvisionalIndexFileSize", p.MaxProvisionalIndexFileSize},
+		{"maxChunkFileSize", p.MaxChunkFileSize},
+		{"maxMemoryDecompressionFactor", p.MaxMemoryDecompressionFactor},
+	}
+
+	for _, limit := range limits {
+		if limit.value == 0 {
+			errs = append(errs, fmt.Sprintf("%s must be positive", limit.name))
+		}
+	}
+
+	if len(p.MultihashAlgorithms) == 0 {
+		errs = append(errs, "missing multihash algorithms")
+	}
+
+	for _, alg := range p.MultihashAlgorithms {
+		if _, err := hashing.GetHashFromMultihash(alg); err != nil {
+			errs = append(errs, fmt.Sprintf("multihash algorithm[%d] is not supported", alg))
+		}
+	}
+
+	if p.CompressionAlgorithm == "" {
+		errs = append(errs, "missing compression algorithm")
+	} else if registry != nil && !registry.IsSupported(p.CompressionAlgorithm) {
+		errs = append(errs, fmt.Sprintf("compression algorithm[%s] is not registered", p.CompressionAlgorithm))
+	}
+
+	proofAlg := p.ProofFileCompressionAlgorithm
+	if proofAlg != "" && proofAlg != NoCompression && registry != nil && !registry.IsSupported(proofAlg) {
+		errs = append(errs, fmt.Sprintf("proof file compression algorithm[%s] is not registered", proofAlg))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid protocol parameters: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+)
+
+const sha2_256 = 18
+
+func TestProtocol_Validate(t *testing.T) {
+	registry := compression.New(compression.WithDefaultAlgorithms())
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, newProtocol().Validate(registry))
+	})
+
+	t.Run("success - compression registry not provided", func(t *testing.T) {
+		p := newProtocol()
+		p.CompressionAlgorithm = "other"
+
+		require.NoError(t, p.Validate(nil))
+	})
+
+	t.Run("error - invalid size limit", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			modify func(p *Protocol)
+		}{
+			{"maxOperationCount", func(p *Protocol) { p.MaxOperationCount = 0 }},
+			{"maxOperationSize", func(p *Protocol) { p.MaxOperationSize = 0 }},
+			{"maxOperationHashLength", func(p *Protocol) { p.MaxOperationHashLength = 0 }},
+			{"maxDeltaSize", func(p *Protocol) { p.MaxDeltaSize = 0 }},
+			{"maxCasUriLength", func(p *Protocol) { p.MaxCasURILength = 0 }},
+			{"maxCoreIndexFileSize", func(p *Protocol) { p.MaxCoreIndexFileSize = 0 }},
+			{"maxProofFileSize", func(p *Protocol) { p.MaxProofFileSize = 0 }},
+			{"maxProvisionalIndexFileSize", func(p *Protocol) { p.MaxProvisionalIndexFileSize = 0 }},
+			{"maxChunkFileSize", func(p *Protocol) { p.MaxChunkFileSize = 0 }},
+			{"maxMemoryDecompressionFactor", func(p *Protocol) { p.MaxMemoryDecompressionFactor = 0 }},
+		}
+
+		for _, tc := range tests {
+			p := newProtocol()
+			tc.modify(&p)
+
+			err := p.Validate(registry)
+			require.Error(t, err, tc.name)
+			require.EqualError(t, err, "invalid protocol parameters: "+tc.name+" must be positive")
+		}
+	})
+
+	t.Run("error - missing multihash algorithms", func(t *testing.T) {
+		p := newProtocol()
+		p.MultihashAlgorithms = nil
+
+		err := p.Validate(registry)
+		require.EqualError(t, err, "invalid protocol parameters: missing multihash algorithms")
+	})
+
+	t.Run("error - multihash algorithm not supported", func(t *testing.T) {
+		p := newProtocol()
+		p.MultihashAlgorithms = []uint{sha2_256, 55}
+
+		err := p.Validate(registry)
+		require.EqualError(t, err, "invalid protocol parameters: multihash algorithm[55] is not supported")
+	})
+
+	t.Run("error - missing compression algorithm", func(t *testing.T) {
+		p := newProtocol()
+		p.CompressionAlgorithm = ""
+
+		err := p.Validate(registry)
+		require.EqualError(t, err, "invalid protocol parameters: missing compression algorithm")
+	})
+
+	t.Run("error - compression algorithm not registered", func(t *testing.T) {
+		p := newProtocol()
+		p.CompressionAlgorithm = "other"
+
+		err := p.Validate(registry)
+		require.EqualError(t, err, "invalid protocol parameters: compression algorithm[other] is not registered")
+	})
+
+	t.Run("success - proof file compression algorithm", func(t *testing.T) {
+		for _, alg := range []string{"", NoCompression, "GZIP"} {
+			p := newProtocol()
+			p.ProofFileCompressionAlgorithm = alg
+
+			require.NoError(t, p.Validate(registry))
+		}
+	})
+
+	t.Run("error - proof file compression algorithm not registered", func(t *testing.T) {
+		p := newProtocol()
+		p.ProofFileCompressionAlgorithm = "other"
+
+		err := p.Validate(registry)
+		require.EqualError(t, err, "invalid protocol parameters: proof file compression algorithm[other] is not registered")
+	})
+
+	t.Run("error - multiple invalid parameters are aggregated", func(t *testing.T) {
+		p := newProtocol()
+		p.MaxChunkFileSize = 0
+		p.CompressionAlgorithm = "other"
+
+		err := p.Validate(registry)
+		require.EqualError(t, err, "invalid protocol parameters: maxChunkFileSize must be positive; "+
+			"compression algorithm[other] is not registered")
+	})
+}
+
+func newProtocol() Protocol {
+	return Protocol{
+		MultihashAlgorithms:          []uint{sha2_256},
+		MaxOperationCount:            10,
+		MaxOperationSize:             2000,
+		MaxOperationHashLength:       100,
+		MaxDeltaSize:                 1000,
+		MaxCasURILength:              100,
+		CompressionAlgorithm:         "GZIP",
+		MaxChunkFileSize:             20000,
+		MaxProvisionalIndexFileSize:  20000,
+		MaxCoreIndexFileSize:         20000,
+		MaxProofFileSize:             20000,
+		MaxMemoryDecompressionFactor: 3,
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package batch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+// dedupCache remembers recently added operations for a configured window so that
+// identical operations submitted again within the window can be rejected.
+type dedupCache struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	expiry  map[string]time.Time
+	entries []dedupEntry
+	now     func() time.Time
+}
+
+type dedupEntry struct {
+	key     string
+	expires time.Time
+}
+
+func newDedupCache(window time.Duration) *dedupCache {
+	return &dedupCache{
+		window: window,
+		expiry: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// reserve records the operation key; returns false if an identical operation
+// has already been recorded within the de-duplication window.
+func (c *dedupCache) reserve(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+
+	c.prune(now)
+
+	if _, ok := c.expiry[key]; ok {
+		return false
+	}
+
+	expires := now.Add(c.window)
+
+	c.expiry[key] = expires
+	c.entries = append(c.entries, dedupEntry{key: key, expires: expires})
+
+	return true
+}
+
+// release removes the operation key (e.g. operation couldn't be added to the queue).
+func (c *dedupCache) release(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.expiry, key)
+}
+
+// prune removes expired entries; entries are kept in the order they were added
+// so only the head of the list has to be examined.
+func (c *dedupCache) prune(now time.Time) {
+	i := 0
+	for ; i < len(c.entries) && !now.Before(c.entries[i].expires); i++ {
+		entry := c.entries[i]
+
+		// key may have been released and reserved again with a later expiry
+		if expires, ok := c.expiry[entry.key]; ok && expires.Equal(entry.expires) {
+			delete(c.expiry, entry.key)
+		}
+	}
+
+	c.entries = c.entries[i:]
+}
+
+func dedupKey(op *operation.QueuedOperation) string {
+	hash := sha256.Sum256(op.OperationBuffer)
+
+	return op.Namespace + "/" + op.UniqueSuffix + "/" + hex.EncodeToString(hash[:])
+}
@@ -37,6 +37,10 @@ const (
 	defaultSendChannelSize = 100
 )
 
+// ErrDuplicateOperation is returned by Add if an identical operation (same namespace, unique suffix
+// and operation data) has already been added within the configured de-duplication window.
+var ErrDuplicateOperation = errors.New("duplicate operation")
+
 // Option defines Writer options such as batch timeout.
 type Option func(opts *Options) error
 
@@ -61,6 +65,7 @@ type Writer struct {
 	batchTimeout time.Duration
 	stopped      uint32
 	protocol     protocol.Client
+	dedup        *dedupCache
 }
 
 // Context contains batch writer context.
@@ -103,6 +108,11 @@ func New(namespace string, context Context, options ...Option) (*Writer, error)
 		batchTimeout = rOpts.BatchTimeout
 	}
 
+	var dedup *dedupCache
+	if rOpts.DuplicateOperationWindow > 0 {
+		dedup = newDedupCache(rOpts.DuplicateOperationWindow)
+	}
+
 	return &Writer{
 		namespace:    namespace,
 		batchCutter:  cutter.New(context.Protocol(), context.OperationQueue()),
@@ -111,6 +121,7 @@ func New(namespace string, context Context, options ...Option) (*Writer, error)
 		batchTimeout: batchTimeout,
 		context:      context,
 		protocol:     context.Protocol(),
+		dedup:        dedup,
 	}, nil
 }
 
@@ -145,7 +156,7 @@ func (r *Writer) Add(op *operation.QueuedOperation, protocolGenesisTime uint64)
 		return errors.New("writer is stopped")
 	}
 
-	_, err := r.batchCutter.Add(op, protocolGenesisTime)
+	err := r.addToCutter(op, protocolGenesisTime)
 	if err != nil {
 		return err
 	}
@@ -161,6 +172,31 @@ func (r *Writer) Add(op *operation.QueuedOperation, protocolGenesisTime uint64)
 	}
 }
 
+// addToCutter adds the operation to the batch cutter; if de-duplication is enabled, operations
+// identical to the ones added within the de-duplication window are rejected.
+func (r *Writer) addToCutter(op *operation.QueuedOperation, protocolGenesisTime uint64) error {
+	if r.dedup == nil {
+		_, err := r.batchCutter.Add(op, protocolGenesisTime)
+
+		return err
+	}
+
+	key := dedupKey(op)
+
+	if !r.dedup.reserve(key) {
+		return errors.Wrapf(ErrDuplicateOperation, "[%s] operation already added within %s", op.UniqueSuffix, r.dedup.window)
+	}
+
+	_, err := r.batchCutter.Add(op, protocolGenesisTime)
+	if err != nil {
+		r.dedup.release(key)
+
+		return err
+	}
+
+	return nil
+}
+
 func (r *Writer) main() {
 	// On startup, there may be operations in the queue. Send a notification
 	// so that any pending items in the queue may be immediately processed.
@@ -290,9 +326,24 @@ func WithBatchTimeout(batchTimeout time.Duration) Option {
 	}
 }
 
+// WithDuplicateOperationWindow enables rejection of operations that are identical (same namespace,
+// unique suffix and operation data) to an operation added within the given window. Zero disables the check.
+func WithDuplicateOperationWindow(window time.Duration) Option {
+	return func(o *Options) error {
+		if window < 0 {
+			return fmt.Errorf("duplicate operation window must not be negative")
+		}
+
+		o.DuplicateOperationWindow = window
+
+		return nil
+	}
+}
+
 // Options allows the user to specify more advanced options.
 type Options struct {
-	BatchTimeout time.Duration
+	BatchTimeout             time.Duration
+	DuplicateOperationWindow time.Duration
 }
 
 // prepareOptsFromOptions reads options.
@@ -328,6 +328,92 @@ func TestAddError(t *testing.T) {
 	require.EqualError(t, writer.Add(&operation.QueuedOperation{}, 0), errExpected.Error())
 }
 
+func TestDuplicateOperationWindow(t *testing.T) {
+	t.Run("success - de-duplication is disabled by default", func(t *testing.T) {
+		writer, err := New(namespace, newMockContext())
+		require.NoError(t, err)
+
+		op, err := generateOperation(1)
+		require.NoError(t, err)
+
+		require.NoError(t, writer.Add(op, 0))
+		require.NoError(t, writer.Add(op, 0))
+	})
+
+	t.Run("error - identical operation within window is rejected", func(t *testing.T) {
+		writer, err := New(namespace, newMockContext(), WithDuplicateOperationWindow(time.Minute))
+		require.NoError(t, err)
+
+		op, err := generateOperation(1)
+		require.NoError(t, err)
+
+		require.NoError(t, writer.Add(op, 0))
+
+		err = writer.Add(op, 0)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDuplicateOperation))
+		require.Contains(t, err.Error(), "[1] operation already added within 1m0s: duplicate operation")
+	})
+
+	t.Run("success - distinct data for the same suffix is accepted", func(t *testing.T) {
+		writer, err := New(namespace, newMockContext(), WithDuplicateOperationWindow(time.Minute))
+		require.NoError(t, err)
+
+		op, err := generateOperation(1)
+		require.NoError(t, err)
+
+		other, err := generateOperation(2)
+		require.NoError(t, err)
+
+		other.UniqueSuffix = op.UniqueSuffix
+
+		require.NoError(t, writer.Add(op, 0))
+		require.NoError(t, writer.Add(other, 0))
+	})
+
+	t.Run("success - identical operation is accepted after window expires", func(t *testing.T) {
+		writer, err := New(namespace, newMockContext(), WithDuplicateOperationWindow(time.Minute))
+		require.NoError(t, err)
+
+		now := time.Now()
+		writer.dedup.now = func() time.Time { return now }
+
+		op, err := generateOperation(1)
+		require.NoError(t, err)
+
+		require.NoError(t, writer.Add(op, 0))
+
+		now = now.Add(time.Minute)
+
+		require.NoError(t, writer.Add(op, 0))
+		require.True(t, errors.Is(writer.Add(op, 0), ErrDuplicateOperation))
+	})
+
+	t.Run("success - operation rejected by queue is not remembered", func(t *testing.T) {
+		q := &mocks.OperationQueue{}
+		q.AddReturnsOnCall(0, 0, errors.New("injected operation queue error"))
+
+		ctx := newMockContext()
+		ctx.OpQueue = q
+
+		writer, err := New(namespace, ctx, WithDuplicateOperationWindow(time.Minute))
+		require.NoError(t, err)
+
+		op, err := generateOperation(1)
+		require.NoError(t, err)
+
+		require.EqualError(t, writer.Add(op, 0), "injected operation queue error")
+		require.NoError(t, writer.Add(op, 0))
+	})
+
+	t.Run("error - negative window", func(t *testing.T) {
+		writer, err := New(namespace, newMockContext(), WithDuplicateOperationWindow(-time.Second))
+		require.Error(t, err)
+		require.Nil(t, writer)
+		require.Contains(t, err.Error(), "duplicate operation window must not be negative")
+	})
+}
+
 func TestStartWithExistingItems(t *testing.T) {
 	const numOperations = 23
 	const maxOperationsPerBatch = 4
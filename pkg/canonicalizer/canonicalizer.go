@@ -25,5 +25,16 @@ func MarshalCanonical(value interface{}) ([]byte, error) {
 		}
 	}
 
-	return jsoncanonicalizer.Transform(valueBytes)
+	return Canonicalize(valueBytes)
+}
+
+// Canonicalize transforms JSON document into canonical form using JCS RFC canonicalization
+// (e.g. object properties are sorted) so that semantically equal documents have the same bytes.
+func Canonicalize(jsonBytes []byte) ([]byte, error) {
+	canonical, err := jsoncanonicalizer.Transform(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return canonical, nil
 }
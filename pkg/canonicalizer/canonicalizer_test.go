@@ -41,3 +41,27 @@ func TestMarshalCanonical(t *testing.T) {
 		require.Contains(t, err.Error(), "json: unsupported type: chan int")
 	})
 }
+
+func TestCanonicalize(t *testing.T) {
+	t.Run("success - object properties are sorted", func(t *testing.T) {
+		result, err := Canonicalize([]byte(`{ "beta": "beta", "alpha": {"d": 1.0, "c": [2, 1]} }`))
+		require.NoError(t, err)
+		require.Equal(t, `{"alpha":{"c":[2,1],"d":1},"beta":"beta"}`, string(result))
+	})
+
+	t.Run("success - semantically equal documents are equal", func(t *testing.T) {
+		first, err := Canonicalize([]byte(`{"a":"1","b":"2"}`))
+		require.NoError(t, err)
+
+		second, err := Canonicalize([]byte(`{"b":"2","a":"1"}`))
+		require.NoError(t, err)
+
+		require.Equal(t, first, second)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		result, err := Canonicalize([]byte("invalid"))
+		require.Error(t, err)
+		require.Empty(t, result)
+	})
+}
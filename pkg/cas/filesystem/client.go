@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filesystem
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+const (
+	sha2_256 = 18
+
+	dirPermissions  = 0700
+	filePermissions = 0600
+)
+
+// Client implements CAS client that stores content in files (named by content hash) under base directory.
+// It is intended for local development and testing.
+type Client struct {
+	baseDir string
+}
+
+// New creates filesystem CAS client; base directory is created if it doesn't exist.
+func New(baseDir string) (*Client, error) {
+	if baseDir == "" {
+		return nil, fmt.Errorf("missing base directory")
+	}
+
+	err := os.MkdirAll(baseDir, dirPermissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create base directory[%s]: %s", baseDir, err.Error())
+	}
+
+	return &Client{baseDir: baseDir}, nil
+}
+
+// Write writes the given content to a file named by content hash.
+// returns the SHA256 hash in base64url encoding which represents the address of the content.
+func (c *Client) Write(content []byte) (string, error) {
+	hash, err := hashing.ComputeMultihash(sha2_256, content)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute content hash: %s", err.Error())
+	}
+
+	address := encoder.EncodeToString(hash)
+
+	err = ioutil.WriteFile(filepath.Join(c.baseDir, address), content, filePermissions)
+	if err != nil {
+		return "", fmt.Errorf("failed to write content[%s]: %s", address, err.Error())
+	}
+
+	return address, nil
+}
+
+// Size returns the size of the content of the given address without reading the content.
+func (c *Client) Size(address string) (int, error) {
+	if err := validateAddress(address); err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(filepath.Join(c.baseDir, address))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("%w: address[%s]", cas.ErrContentNotFound, address)
+		}
+
+		return 0, fmt.Errorf("failed to get size of content[%s]: %s", address, err.Error())
+	}
+
+	return int(info.Size()), nil
+}
+
+// Read reads the content of the given address from the file named by address.
+// returns the content of the given address.
+func (c *Client) Read(address string) ([]byte, error) {
+	if err := validateAddress(address); err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(c.baseDir, address))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: address[%s]", cas.ErrContentNotFound, address)
+		}
+
+		return nil, fmt.Errorf("failed to read content[%s]: %s", address, err.Error())
+	}
+
+	return content, nil
+}
+
+// address is used as file name; reject addresses that could reference files outside of base directory.
+func validateAddress(address string) error {
+	if address == "" || address == "." || address == ".." || strings.ContainsAny(address, `/\`) {
+		return fmt.Errorf("invalid address[%s]", address)
+	}
+
+	return nil
+}
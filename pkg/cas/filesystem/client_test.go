@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filesystem
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success - base directory is created", func(t *testing.T) {
+		dir := tempDir(t)
+		defer removeDir(t, dir)
+
+		baseDir := filepath.Join(dir, "cas")
+
+		var c cas.Client
+		c, err := New(baseDir)
+		require.NoError(t, err)
+		require.NotNil(t, c)
+
+		info, err := os.Stat(baseDir)
+		require.NoError(t, err)
+		require.True(t, info.IsDir())
+	})
+
+	t.Run("error - missing base directory", func(t *testing.T) {
+		c, err := New("")
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "missing base directory")
+	})
+
+	t.Run("error - base directory is a file", func(t *testing.T) {
+		dir := tempDir(t)
+		defer removeDir(t, dir)
+
+		file := filepath.Join(dir, "file")
+		require.NoError(t, ioutil.WriteFile(file, []byte("content"), filePermissions))
+
+		c, err := New(filepath.Join(file, "cas"))
+		require.Error(t, err)
+		require.Nil(t, c)
+		require.Contains(t, err.Error(), "failed to create base directory")
+	})
+}
+
+func TestWriteRead(t *testing.T) {
+	baseDir := tempDir(t)
+	defer removeDir(t, baseDir)
+
+	c, err := New(baseDir)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		content := []byte("content")
+
+		address, err := c.Write(content)
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(baseDir, address))
+
+		read, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, content, read)
+
+		// same content results in the same address
+		address2, err := c.Write(content)
+		require.NoError(t, err)
+		require.Equal(t, address, address2)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		read, err := c.Read("address")
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+		require.Contains(t, err.Error(), "content not found: address[address]")
+	})
+
+	t.Run("error - invalid address", func(t *testing.T) {
+		for _, address := range []string{"", ".", "..", "../address", `dir\address`} {
+			read, err := c.Read(address)
+			require.Error(t, err)
+			require.Nil(t, read)
+			require.Contains(t, err.Error(), "invalid address")
+		}
+	})
+
+	t.Run("error - read directory", func(t *testing.T) {
+		require.NoError(t, os.Mkdir(filepath.Join(baseDir, "dir"), dirPermissions))
+
+		read, err := c.Read("dir")
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.Contains(t, err.Error(), "failed to read content[dir]")
+	})
+
+	t.Run("success - size", func(t *testing.T) {
+		address, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		size, err := c.Size(address)
+		require.NoError(t, err)
+		require.Equal(t, len("content"), size)
+	})
+
+	t.Run("error - size not found", func(t *testing.T) {
+		size, err := c.Size("address")
+		require.Error(t, err)
+		require.Zero(t, size)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+		require.Contains(t, err.Error(), "content not found: address[address]")
+	})
+
+	t.Run("error - size invalid address", func(t *testing.T) {
+		size, err := c.Size("../address")
+		require.Error(t, err)
+		require.Zero(t, size)
+		require.Contains(t, err.Error(), "invalid address")
+	})
+
+	t.Run("error - not found is wrapped by operation provider", func(t *testing.T) {
+		p := protocol.Protocol{
+			MaxCoreIndexFileSize:         1000,
+			CompressionAlgorithm:         "GZIP",
+			MaxMemoryDecompressionFactor: 3,
+		}
+
+		provider := txnprovider.NewOperationProvider(p, operationparser.New(p), c,
+			compression.New(compression.WithDefaultAlgorithms()))
+
+		ops, err := provider.GetTxnOperations(&txn.SidetreeTxn{AnchorString: "1.address"})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+		require.True(t, errors.Is(err, protocol.ErrUnresolvableTxn))
+		require.Contains(t, err.Error(), "retrieve CAS content at uri[address]: content not found: address[address]")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("error - base directory removed", func(t *testing.T) {
+		dir := tempDir(t)
+		defer removeDir(t, dir)
+
+		baseDir := filepath.Join(dir, "cas")
+
+		c, err := New(baseDir)
+		require.NoError(t, err)
+
+		require.NoError(t, os.RemoveAll(baseDir))
+
+		address, err := c.Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, address)
+		require.Contains(t, err.Error(), "failed to write content")
+	})
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "cas")
+	require.NoError(t, err)
+
+	return dir
+}
+
+func removeDir(t *testing.T, dir string) {
+	t.Helper()
+
+	require.NoError(t, os.RemoveAll(dir))
+}
@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("sidetree-core-cas-ipfs")
+
+const defaultTimeout = 20 * time.Second
+
+// Client implements CAS client using IPFS HTTP API.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// Option is an IPFS client instance option.
+type Option func(opts *Client)
+
+// WithHTTPClient sets optional HTTP client used for IPFS API requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(opts *Client) {
+		if httpClient != nil {
+			opts.httpClient = httpClient
+		}
+	}
+}
+
+// New creates IPFS CAS client for the given IPFS API endpoint (e.g. http://localhost:5001).
+func New(endpoint string, opts ...Option) *Client {
+	client := &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+type addResponse struct {
+	Name string
+	Hash string
+	Size string
+}
+
+// Write adds the given content to IPFS.
+// returns the CID which represents the address of the content.
+func (c *Client) Write(content []byte) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", "file")
+	if err != nil {
+		return "", fmt.Errorf("failed to create ipfs add request: %s", err.Error())
+	}
+
+	_, err = part.Write(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to create ipfs add request: %s", err.Error())
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to create ipfs add request: %s", err.Error())
+	}
+
+	respBytes, err := c.post("add", url.Values{"pin": []string{"true"}}, writer.FormDataContentType(), body)
+	if err != nil {
+		return "", fmt.Errorf("failed to add content to ipfs: %s", err.Error())
+	}
+
+	resp := &addResponse{}
+	err = json.Unmarshal(respBytes, resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal ipfs add response: %s", err.Error())
+	}
+
+	if resp.Hash == "" {
+		return "", fmt.Errorf("ipfs add response is missing hash")
+	}
+
+	return resp.Hash, nil
+}
+
+// Read reads the content for the given CID from IPFS.
+// returns the content of the given address.
+func (c *Client) Read(address string) ([]byte, error) {
+	if address == "" {
+		return nil, fmt.Errorf("missing address")
+	}
+
+	content, err := c.post("cat", url.Values{"arg": []string{address}}, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content[%s] from ipfs: %s", address, err.Error())
+	}
+
+	return content, nil
+}
+
+func (c *Client) post(command string, params url.Values, contentType string, body io.Reader) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s/api/v0/%s?%s", c.endpoint, command, params.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("failed to close response body: %s", e.Error())
+		}
+	}()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %s", err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status[%d]: %s", resp.StatusCode, strings.TrimSpace(string(respBytes)))
+	}
+
+	return respBytes, nil
+}
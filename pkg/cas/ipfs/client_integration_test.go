@@ -0,0 +1,49 @@
+// +build ipfs
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Integration tests require running IPFS node; run with: go test -tags ipfs ./pkg/cas/ipfs/...
+// IPFS API endpoint can be set using IPFS_URL environment variable (default http://localhost:5001).
+func TestIPFSIntegration(t *testing.T) {
+	endpoint := os.Getenv("IPFS_URL")
+	if endpoint == "" {
+		endpoint = "http://localhost:5001"
+	}
+
+	c := New(endpoint)
+
+	t.Run("success - write and read", func(t *testing.T) {
+		content := []byte("sidetree content")
+
+		cid, err := c.Write(content)
+		require.NoError(t, err)
+		require.NotEmpty(t, cid)
+
+		read, err := c.Read(cid)
+		require.NoError(t, err)
+		require.Equal(t, content, read)
+	})
+
+	t.Run("success - same content results in same CID", func(t *testing.T) {
+		cid1, err := c.Write([]byte("same content"))
+		require.NoError(t, err)
+
+		cid2, err := c.Write([]byte("same content"))
+		require.NoError(t, err)
+
+		require.Equal(t, cid1, cid2)
+	})
+}
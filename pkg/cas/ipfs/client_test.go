@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+)
+
+const testCID = "QmWATWQ7fVPP2EFGu71UkfnqhYXDYH566qy47CnJDgvs8u"
+
+func TestNew(t *testing.T) {
+	t.Run("success - default http client", func(t *testing.T) {
+		c := New("http://localhost:5001/")
+		require.NotNil(t, c)
+		require.Equal(t, "http://localhost:5001", c.endpoint)
+		require.NotNil(t, c.httpClient)
+	})
+
+	t.Run("success - with http client", func(t *testing.T) {
+		httpClient := &http.Client{}
+
+		c := New("http://localhost:5001", WithHTTPClient(httpClient))
+		require.Equal(t, httpClient, c.httpClient)
+	})
+
+	t.Run("success - implements CAS client", func(t *testing.T) {
+		var c cas.Client = New("http://localhost:5001")
+		require.NotNil(t, c)
+	})
+}
+
+func TestWriteRead(t *testing.T) {
+	ipfs := newMockIPFS()
+
+	server := httptest.NewServer(ipfs)
+	defer server.Close()
+
+	c := New(server.URL)
+
+	t.Run("success", func(t *testing.T) {
+		content := []byte("content")
+
+		cid, err := c.Write(content)
+		require.NoError(t, err)
+		require.Equal(t, testCID, cid)
+
+		read, err := c.Read(cid)
+		require.NoError(t, err)
+		require.Equal(t, content, read)
+	})
+
+	t.Run("error - read not found", func(t *testing.T) {
+		read, err := c.Read("invalid")
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.Contains(t, err.Error(), "failed to read content[invalid] from ipfs: status[500]: not found")
+	})
+
+	t.Run("error - read missing address", func(t *testing.T) {
+		read, err := c.Read("")
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.Contains(t, err.Error(), "missing address")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("error - add failed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "add error", http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		cid, err := New(server.URL).Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, cid)
+		require.Contains(t, err.Error(), "failed to add content to ipfs: status[500]: add error")
+	})
+
+	t.Run("error - invalid add response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("invalid"))
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		cid, err := New(server.URL).Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, cid)
+		require.Contains(t, err.Error(), "failed to unmarshal ipfs add response")
+	})
+
+	t.Run("error - missing hash in add response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(`{"Name":"file"}`))
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		cid, err := New(server.URL).Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, cid)
+		require.Contains(t, err.Error(), "ipfs add response is missing hash")
+	})
+
+	t.Run("error - server not available", func(t *testing.T) {
+		cid, err := New("http://localhost:0").Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, cid)
+		require.Contains(t, err.Error(), "failed to add content to ipfs")
+	})
+}
+
+// mockIPFS mocks IPFS HTTP API (add and cat commands).
+type mockIPFS struct {
+	sync.RWMutex
+	m map[string][]byte
+}
+
+func newMockIPFS() *mockIPFS {
+	return &mockIPFS{m: make(map[string][]byte)}
+}
+
+func (m *mockIPFS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api/v0/add":
+		m.add(w, r)
+	case "/api/v0/cat":
+		m.cat(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *mockIPFS) add(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	m.Lock()
+	m.m[testCID] = content
+	m.Unlock()
+
+	fmt.Fprintf(w, `{"Name":"file","Hash":"%s","Size":"%d"}`, testCID, len(content))
+}
+
+func (m *mockIPFS) cat(w http.ResponseWriter, r *http.Request) {
+	m.RLock()
+	content, ok := m.m[r.URL.Query().Get("arg")]
+	m.RUnlock()
+
+	if !ok {
+		http.Error(w, "not found", http.StatusInternalServerError)
+
+		return
+	}
+
+	_, err := w.Write(content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package s3
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+const sha2_256 = 18
+
+// ErrObjectNotFound should be returned by S3 API implementation if object doesn't exist in the bucket.
+var ErrObjectNotFound = errors.New("object not found")
+
+// API defines S3 operations used by CAS client. It is expected to be implemented
+// by a thin adapter around S3 SDK client (or a fake for testing purposes).
+type API interface {
+	PutObject(bucket, key string, content []byte) error
+	GetObject(bucket, key string) ([]byte, error)
+}
+
+// Client implements CAS client backed by S3 bucket.
+type Client struct {
+	bucket string
+	api    API
+}
+
+// New creates S3 CAS client for the given bucket.
+func New(bucket string, api API) *Client {
+	return &Client{
+		bucket: bucket,
+		api:    api,
+	}
+}
+
+// Write writes the given content to S3 bucket.
+// returns the SHA256 hash in base64url encoding which represents the address (object key) of the content.
+func (c *Client) Write(content []byte) (string, error) {
+	hash, err := hashing.ComputeMultihash(sha2_256, content)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute content hash: %s", err.Error())
+	}
+
+	key := encoder.EncodeToString(hash)
+
+	err = c.api.PutObject(c.bucket, key, content)
+	if err != nil {
+		return "", fmt.Errorf("failed to put object[%s] to bucket[%s]: %s", key, c.bucket, err.Error())
+	}
+
+	return key, nil
+}
+
+// Read reads the content of the given address (object key) from S3 bucket.
+// returns the content of the given address.
+func (c *Client) Read(address string) ([]byte, error) {
+	content, err := c.api.GetObject(c.bucket, address)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, fmt.Errorf("%w: address[%s] in bucket[%s]", cas.ErrContentNotFound, address, c.bucket)
+		}
+
+		return nil, fmt.Errorf("failed to get object[%s] from bucket[%s]: %s", address, c.bucket, err.Error())
+	}
+
+	return content, nil
+}
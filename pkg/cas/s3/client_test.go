@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+)
+
+const bucket = "sidetree"
+
+func TestNew(t *testing.T) {
+	var c cas.Client = New(bucket, newMockS3())
+	require.NotNil(t, c)
+}
+
+func TestWriteRead(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		api := newMockS3()
+		c := New(bucket, api)
+
+		content := []byte("content")
+
+		address, err := c.Write(content)
+		require.NoError(t, err)
+
+		hash, err := hashing.ComputeMultihash(sha2_256, content)
+		require.NoError(t, err)
+		require.Equal(t, encoder.EncodeToString(hash), address)
+		require.Equal(t, content, api.objects[bucket+"/"+address])
+
+		read, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, content, read)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		c := New(bucket, newMockS3())
+
+		read, err := c.Read("address")
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.True(t, errors.Is(err, cas.ErrContentNotFound))
+		require.Contains(t, err.Error(), "content not found: address[address] in bucket[sidetree]")
+	})
+
+	t.Run("error - get object error", func(t *testing.T) {
+		api := newMockS3()
+		api.getErr = errors.New("get error")
+
+		read, err := New(bucket, api).Read("address")
+		require.Error(t, err)
+		require.Nil(t, read)
+		require.Contains(t, err.Error(), "failed to get object[address] from bucket[sidetree]: get error")
+	})
+
+	t.Run("error - put object error", func(t *testing.T) {
+		api := newMockS3()
+		api.putErr = errors.New("put error")
+
+		address, err := New(bucket, api).Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, address)
+		require.Contains(t, err.Error(), "to bucket[sidetree]: put error")
+	})
+}
+
+type mockS3 struct {
+	sync.RWMutex
+	objects map[string][]byte
+	putErr  error
+	getErr  error
+}
+
+func newMockS3() *mockS3 {
+	return &mockS3{objects: make(map[string][]byte)}
+}
+
+func (m *mockS3) PutObject(bucket, key string, content []byte) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.objects[bucket+"/"+key] = content
+
+	return nil
+}
+
+func (m *mockS3) GetObject(bucket, key string) ([]byte, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	content, ok := m.objects[bucket+"/"+key]
+	if !ok {
+		return nil, fmt.Errorf("get object %s/%s: %w", bucket, key, ErrObjectNotFound)
+	}
+
+	return content, nil
+}
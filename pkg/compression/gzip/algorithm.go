@@ -10,32 +10,37 @@ import (
 	"bytes"
 	"compress/gzip"
 	"fmt"
-	"io/ioutil"
+	"io"
 )
 
 const algName = "GZIP"
 
 // Algorithm implements gzip compression/decompression.
 type Algorithm struct {
+	level int
 }
 
-// New creates new gzip algorithm instance.
+// New creates new gzip algorithm instance with default compression level.
 func New() *Algorithm {
-	return &Algorithm{}
+	return &Algorithm{level: gzip.DefaultCompression}
+}
+
+// NewWithLevel creates new gzip algorithm instance with the given compression level
+// (e.g. gzip.BestSpeed, gzip.BestCompression).
+func NewWithLevel(level int) (*Algorithm, error) {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return nil, fmt.Errorf("invalid gzip compression level: %d", level)
+	}
+
+	return &Algorithm{level: level}, nil
 }
 
 // Compress will compress data using gzip.
 func (a *Algorithm) Compress(data []byte) ([]byte, error) {
 	var buf bytes.Buffer
-	zw := gzip.NewWriter(&buf)
-
-	_, err := zw.Write(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write data: %s", err.Error())
-	}
 
-	if err := zw.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close writer: %s", err.Error())
+	if err := a.CompressStream(bytes.NewReader(data), &buf); err != nil {
+		return nil, err
 	}
 
 	return buf.Bytes(), nil
@@ -43,23 +48,51 @@ func (a *Algorithm) Compress(data []byte) ([]byte, error) {
 
 // Decompress will decompress compressed data.
 func (a *Algorithm) Decompress(data []byte) ([]byte, error) {
-	buf := bytes.NewBuffer(data)
+	var buf bytes.Buffer
+
+	if err := a.DecompressStream(bytes.NewReader(data), &buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
 
-	zr, err := gzip.NewReader(buf)
+// CompressStream will compress data from reader using gzip and write it to writer.
+func (a *Algorithm) CompressStream(r io.Reader, w io.Writer) error {
+	zw, err := gzip.NewWriterLevel(w, a.level)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new reader: %s", err.Error())
+		return fmt.Errorf("failed to create writer: %s", err.Error())
 	}
 
-	bytes, err := ioutil.ReadAll(zr)
+	_, err = io.Copy(zw, r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read compressed data: %s", err.Error())
+		return fmt.Errorf("failed to write data: %s", err.Error())
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to close writer: %s", err.Error())
+	}
+
+	return nil
+}
+
+// DecompressStream will decompress compressed data from reader and write it to writer.
+func (a *Algorithm) DecompressStream(r io.Reader, w io.Writer) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to create new reader: %s", err.Error())
+	}
+
+	_, err = io.Copy(w, zr) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to read compressed data: %s", err.Error())
 	}
 
 	if err := zr.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close reader: %s", err.Error())
+		return fmt.Errorf("failed to close reader: %s", err.Error())
 	}
 
-	return bytes, nil
+	return nil
 }
 
 // Accept algorithm.
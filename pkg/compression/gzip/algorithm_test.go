@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package gzip
 
 import (
+	"bytes"
+	"compress/gzip"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -44,6 +46,35 @@ func TestAlgorithm_Compress(t *testing.T) {
 	})
 }
 
+func TestNewWithLevel(t *testing.T) {
+	test := bytes.Repeat([]byte("hello world "), 100)
+
+	t.Run("success - compression levels", func(t *testing.T) {
+		for _, level := range []int{gzip.BestSpeed, gzip.BestCompression, gzip.DefaultCompression, gzip.HuffmanOnly} {
+			alg, err := NewWithLevel(level)
+			require.NoError(t, err)
+
+			compressed, err := alg.Compress(test)
+			require.NoError(t, err)
+			require.NotEmpty(t, compressed)
+
+			// data compressed with any level can be decompressed
+			data, err := New().Decompress(compressed)
+			require.NoError(t, err)
+			require.Equal(t, test, data)
+		}
+	})
+
+	t.Run("error - invalid compression level", func(t *testing.T) {
+		for _, level := range []int{gzip.HuffmanOnly - 1, gzip.BestCompression + 1} {
+			alg, err := NewWithLevel(level)
+			require.Error(t, err)
+			require.Nil(t, alg)
+			require.Contains(t, err.Error(), "invalid gzip compression level")
+		}
+	})
+}
+
 func TestAlgorithm_Decompress(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		alg := New()
@@ -69,6 +100,28 @@ func TestAlgorithm_Decompress(t *testing.T) {
 	})
 }
 
+func TestAlgorithm_Stream(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		alg := New()
+
+		test := bytes.Repeat([]byte("hello world "), 100000)
+
+		var compressed bytes.Buffer
+		require.NoError(t, alg.CompressStream(bytes.NewReader(test), &compressed))
+
+		var data bytes.Buffer
+		require.NoError(t, alg.DecompressStream(&compressed, &data))
+		require.Equal(t, test, data.Bytes())
+	})
+	t.Run("error - data not compressed", func(t *testing.T) {
+		alg := New()
+
+		var data bytes.Buffer
+		err := alg.DecompressStream(bytes.NewReader([]byte("test data")), &data)
+		require.Error(t, err)
+	})
+}
+
 func TestAlgorithm_Close(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		alg := New()
@@ -8,8 +8,11 @@ package compression
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/compression/gzip"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression/zstd"
 )
 
 // Option is a registry instance option.
@@ -18,6 +21,7 @@ type Option func(opts *Registry)
 // Registry contains compression algorithms.
 type Registry struct {
 	algorithms []Algorithm
+	err        error
 }
 
 // Algorithm defines compression/decompression algorithm functionality.
@@ -28,7 +32,23 @@ type Algorithm interface {
 	Close() error
 }
 
+// StreamAlgorithm defines streaming compression/decompression functionality. Algorithms that implement it
+// don't have to buffer whole payload in memory when used with registry stream functions.
+type StreamAlgorithm interface {
+	CompressStream(r io.Reader, w io.Writer) error
+	DecompressStream(r io.Reader, w io.Writer) error
+}
+
+// Compressor defines minimal compression/decompression functionality that can be registered
+// under an algorithm name using WithCompressor option.
+type Compressor interface {
+	Compress(value []byte) ([]byte, error)
+	Decompress(value []byte) ([]byte, error)
+}
+
 // New return new instance of compression algorithm registry.
+// If an option is invalid (e.g. invalid gzip compression level) all registry operations fail;
+// use NewWithOptions in order to handle invalid options when registry is created.
 func New(opts ...Option) *Registry {
 	registry := &Registry{}
 
@@ -40,6 +60,16 @@ func New(opts ...Option) *Registry {
 	return registry
 }
 
+// NewWithOptions returns new instance of compression algorithm registry or an error if an option is invalid.
+func NewWithOptions(opts ...Option) (*Registry, error) {
+	registry := New(opts...)
+	if registry.err != nil {
+		return nil, fmt.Errorf("invalid compression registry option: %w", registry.err)
+	}
+
+	return registry, nil
+}
+
 // Compress data using specified algorithm.
 func (r *Registry) Compress(alg string, data []byte) ([]byte, error) {
 	// resolve compression algorithm
@@ -74,6 +104,51 @@ func (r *Registry) Decompress(alg string, data []byte) ([]byte, error) {
 	return result, nil
 }
 
+// CompressStream compresses data read from reader and writes compressed data to writer using specified algorithm.
+func (r *Registry) CompressStream(alg string, reader io.Reader, writer io.Writer) error {
+	algorithm, err := r.resolveAlgorithm(alg)
+	if err != nil {
+		return err
+	}
+
+	if sa, ok := algorithm.(StreamAlgorithm); ok {
+		if err := sa.CompressStream(reader, writer); err != nil {
+			return fmt.Errorf("compression failed for algorithm[%s]: %s", alg, err.Error())
+		}
+
+		return nil
+	}
+
+	// algorithm doesn't support streaming so buffer data
+	return transform(reader, writer, algorithm.Compress, "compression failed for algorithm[%s]: %s", alg)
+}
+
+// DecompressStream decompresses data read from reader and writes decompressed data to writer using specified algorithm.
+func (r *Registry) DecompressStream(alg string, reader io.Reader, writer io.Writer) error {
+	algorithm, err := r.resolveAlgorithm(alg)
+	if err != nil {
+		return err
+	}
+
+	if sa, ok := algorithm.(StreamAlgorithm); ok {
+		if err := sa.DecompressStream(reader, writer); err != nil {
+			return fmt.Errorf("decompression failed for alg[%s]: %s", alg, err.Error())
+		}
+
+		return nil
+	}
+
+	// algorithm doesn't support streaming so buffer data
+	return transform(reader, writer, algorithm.Decompress, "decompression failed for alg[%s]: %s", alg)
+}
+
+// IsSupported returns true if compression algorithm with the given name has been registered.
+func (r *Registry) IsSupported(alg string) bool {
+	_, err := r.resolveAlgorithm(alg)
+
+	return err == nil
+}
+
 // Close frees resources being maintained by compression algorithm.
 func (r *Registry) Close() error {
 	for _, v := range r.algorithms {
@@ -86,6 +161,10 @@ func (r *Registry) Close() error {
 }
 
 func (r *Registry) resolveAlgorithm(alg string) (Algorithm, error) {
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid compression registry: %s", r.err.Error())
+	}
+
 	for _, v := range r.algorithms {
 		if v.Accept(alg) {
 			return v, nil
@@ -102,9 +181,70 @@ func WithAlgorithm(alg Algorithm) Option {
 	}
 }
 
+// WithCompressor registers custom compressor under the given algorithm name.
+func WithCompressor(name string, c Compressor) Option {
+	return WithAlgorithm(&namedCompressor{Compressor: c, name: name})
+}
+
+// WithGzipLevel adds gzip compression algorithm with the given compression level (e.g. gzip.BestSpeed).
+// This algorithm takes precedence over previously added gzip algorithm (e.g. from default algorithms).
+// Invalid compression level is reported by NewWithOptions.
+func WithGzipLevel(level int) Option {
+	return func(opts *Registry) {
+		alg, err := gzip.NewWithLevel(level)
+		if err != nil {
+			if opts.err == nil {
+				opts.err = err
+			}
+
+			return
+		}
+
+		opts.algorithms = append([]Algorithm{alg}, opts.algorithms...)
+	}
+}
+
 // WithDefaultAlgorithms adds default compression algorithms to the list of available algorithms.
 func WithDefaultAlgorithms() Option {
 	return func(opts *Registry) {
-		opts.algorithms = append(opts.algorithms, gzip.New())
+		opts.algorithms = append(opts.algorithms, gzip.New(), zstd.New())
+	}
+}
+
+func transform(reader io.Reader, writer io.Writer, fnc func([]byte) ([]byte, error), errFormat, alg string) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf(errFormat, alg, err.Error())
 	}
+
+	result, err := fnc(data)
+	if err != nil {
+		return fmt.Errorf(errFormat, alg, err.Error())
+	}
+
+	if _, err := writer.Write(result); err != nil {
+		return fmt.Errorf(errFormat, alg, err.Error())
+	}
+
+	return nil
+}
+
+// namedCompressor adapts compressor to compression algorithm interface.
+type namedCompressor struct {
+	Compressor
+	name string
+}
+
+// Accept algorithm.
+func (c *namedCompressor) Accept(alg string) bool {
+	return alg == c.name
+}
+
+// Close closes compressor if it holds resources.
+func (c *namedCompressor) Close() error {
+	if closer, ok := c.Compressor.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+
+	return nil
 }
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package compression
 
 import (
+	"bytes"
+	stdgzip "compress/gzip"
 	"errors"
 	"testing"
 
@@ -15,7 +17,10 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/compression/gzip"
 )
 
-const algGZIP = "GZIP"
+const (
+	algGZIP = "GZIP"
+	algZSTD = "ZSTD"
+)
 
 func TestNew(t *testing.T) {
 	t.Run("test new success", func(t *testing.T) {
@@ -47,6 +52,25 @@ func TestRegistry_Compress(t *testing.T) {
 		require.Equal(t, data, test)
 	})
 
+	t.Run("success - default algorithms", func(t *testing.T) {
+		registry := New(WithDefaultAlgorithms())
+		defer func() {
+			require.NoError(t, registry.Close())
+		}()
+
+		test := []byte("hello world")
+
+		for _, alg := range []string{algGZIP, algZSTD} {
+			compressed, err := registry.Compress(alg, test)
+			require.NoError(t, err)
+			require.NotEmpty(t, compressed)
+
+			data, err := registry.Decompress(alg, compressed)
+			require.NoError(t, err)
+			require.Equal(t, test, data)
+		}
+	})
+
 	t.Run("error - algorithm not supported", func(t *testing.T) {
 		registry := New()
 
@@ -104,6 +128,184 @@ func TestRegistry_Decompress(t *testing.T) {
 	})
 }
 
+func TestRegistry_WithCompressor(t *testing.T) {
+	const algReverse = "REVERSE"
+
+	t.Run("success", func(t *testing.T) {
+		registry := New(WithDefaultAlgorithms(), WithCompressor(algReverse, &reverseCompressor{}))
+
+		test := []byte("hello world")
+		compressed, err := registry.Compress(algReverse, test)
+		require.NoError(t, err)
+		require.Equal(t, []byte("dlrow olleh"), compressed)
+
+		data, err := registry.Decompress(algReverse, compressed)
+		require.NoError(t, err)
+		require.Equal(t, test, data)
+
+		// default algorithms are still available
+		compressed, err = registry.Compress(algGZIP, test)
+		require.NoError(t, err)
+		require.NotEmpty(t, compressed)
+
+		require.NoError(t, registry.Close())
+	})
+
+	t.Run("error - algorithm name doesn't match", func(t *testing.T) {
+		registry := New(WithCompressor(algReverse, &reverseCompressor{}))
+
+		compressed, err := registry.Compress(algGZIP, []byte("test data"))
+		require.Error(t, err)
+		require.Empty(t, compressed)
+		require.Contains(t, err.Error(), "compression algorithm 'GZIP' not supported")
+	})
+
+	t.Run("error - close error", func(t *testing.T) {
+		registry := New(WithCompressor("mock", &mockAlgorithm{CloseErr: errors.New("close error")}))
+		require.Error(t, registry.Close())
+	})
+}
+
+func TestRegistry_WithGzipLevel(t *testing.T) {
+	test := bytes.Repeat([]byte("hello world "), 100)
+
+	t.Run("success", func(t *testing.T) {
+		fast := New(WithDefaultAlgorithms(), WithGzipLevel(stdgzip.BestSpeed))
+		best := New(WithDefaultAlgorithms(), WithGzipLevel(stdgzip.BestCompression))
+
+		fastCompressed, err := fast.Compress(algGZIP, test)
+		require.NoError(t, err)
+
+		bestCompressed, err := best.Compress(algGZIP, test)
+		require.NoError(t, err)
+
+		require.True(t, len(bestCompressed) <= len(fastCompressed))
+
+		// decompression works across levels
+		data, err := best.Decompress(algGZIP, fastCompressed)
+		require.NoError(t, err)
+		require.Equal(t, test, data)
+
+		data, err = New(WithDefaultAlgorithms()).Decompress(algGZIP, bestCompressed)
+		require.NoError(t, err)
+		require.Equal(t, test, data)
+	})
+
+	t.Run("success - valid compression levels with error-returning constructor", func(t *testing.T) {
+		registry, err := NewWithOptions(WithGzipLevel(stdgzip.HuffmanOnly), WithGzipLevel(stdgzip.BestCompression))
+		require.NoError(t, err)
+		require.True(t, registry.IsSupported(algGZIP))
+	})
+
+	t.Run("error - invalid compression level fails registry creation", func(t *testing.T) {
+		registry, err := NewWithOptions(WithDefaultAlgorithms(), WithGzipLevel(100))
+		require.Error(t, err)
+		require.Nil(t, registry)
+		require.Contains(t, err.Error(), "invalid compression registry option: invalid gzip compression level: 100")
+
+		registry, err = NewWithOptions(WithGzipLevel(-3))
+		require.Error(t, err)
+		require.Nil(t, registry)
+		require.Contains(t, err.Error(), "invalid gzip compression level: -3")
+	})
+
+	t.Run("error - invalid compression level fails registry operations", func(t *testing.T) {
+		registry := New(WithDefaultAlgorithms(), WithGzipLevel(100))
+		require.False(t, registry.IsSupported(algGZIP))
+
+		_, err := registry.Compress(algGZIP, test)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid compression registry: invalid gzip compression level: 100")
+	})
+}
+
+func TestRegistry_Stream(t *testing.T) {
+	// multi-megabyte payload
+	payload := bytes.Repeat([]byte(`{"patches":[{"action":"replace","document":{"publicKeys":[]}}]}`), 50000)
+
+	registry := New(WithDefaultAlgorithms(), WithCompressor("REVERSE", &reverseCompressor{}))
+	defer func() {
+		require.NoError(t, registry.Close())
+	}()
+
+	t.Run("success - stream output matches buffered output", func(t *testing.T) {
+		for _, alg := range []string{algGZIP, algZSTD, "REVERSE"} {
+			var compressed bytes.Buffer
+
+			err := registry.CompressStream(alg, bytes.NewReader(payload), &compressed)
+			require.NoError(t, err)
+			require.NotEmpty(t, compressed.Bytes())
+
+			// stream compressed data can be decompressed using buffered path
+			data, err := registry.Decompress(alg, compressed.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, payload, data)
+
+			// buffered compressed data can be decompressed using stream path
+			buffered, err := registry.Compress(alg, payload)
+			require.NoError(t, err)
+
+			var decompressed bytes.Buffer
+
+			err = registry.DecompressStream(alg, bytes.NewReader(buffered), &decompressed)
+			require.NoError(t, err)
+			require.Equal(t, payload, decompressed.Bytes())
+		}
+	})
+
+	t.Run("error - algorithm not supported", func(t *testing.T) {
+		err := registry.CompressStream("alg", bytes.NewReader(payload), &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "compression algorithm 'alg' not supported")
+
+		err = registry.DecompressStream("alg", bytes.NewReader(payload), &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "compression algorithm 'alg' not supported")
+	})
+
+	t.Run("error - data not compressed", func(t *testing.T) {
+		err := registry.DecompressStream(algGZIP, bytes.NewReader([]byte("test data")), &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decompression failed for alg[GZIP]")
+	})
+
+	t.Run("error - algorithm error", func(t *testing.T) {
+		mockRegistry := New(WithAlgorithm(&mockAlgorithm{
+			CompressErr:   errors.New("compress error"),
+			DecompressErr: errors.New("decompress error"),
+		}))
+
+		err := mockRegistry.CompressStream("mock", bytes.NewReader(payload), &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "compression failed for algorithm[mock]: compress error")
+
+		err = mockRegistry.DecompressStream("mock", bytes.NewReader(payload), &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decompression failed for alg[mock]: decompress error")
+	})
+}
+
+func TestRegistry_IsSupported(t *testing.T) {
+	t.Run("success - default algorithms", func(t *testing.T) {
+		registry := New(WithDefaultAlgorithms())
+
+		require.True(t, registry.IsSupported(algGZIP))
+		require.True(t, registry.IsSupported(algZSTD))
+		require.False(t, registry.IsSupported("invalid"))
+	})
+
+	t.Run("success - custom compressor", func(t *testing.T) {
+		registry := New(WithCompressor("REVERSE", &reverseCompressor{}))
+
+		require.True(t, registry.IsSupported("REVERSE"))
+		require.False(t, registry.IsSupported(algGZIP))
+	})
+
+	t.Run("success - empty registry", func(t *testing.T) {
+		require.False(t, New().IsSupported(algGZIP))
+	})
+}
+
 func TestRegistry_Close(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		registry := New(WithAlgorithm(gzip.New()), WithAlgorithm(&mockAlgorithm{}))
@@ -149,3 +351,22 @@ func (m *mockAlgorithm) Accept(alg string) bool {
 func (m *mockAlgorithm) Close() error {
 	return m.CloseErr
 }
+
+type reverseCompressor struct{}
+
+func (c *reverseCompressor) Compress(data []byte) ([]byte, error) {
+	return reverse(data), nil
+}
+
+func (c *reverseCompressor) Decompress(data []byte) ([]byte, error) {
+	return reverse(data), nil
+}
+
+func reverse(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[len(data)-1-i] = b
+	}
+
+	return result
+}
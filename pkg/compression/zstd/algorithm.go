@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package zstd
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const algName = "ZSTD"
+
+// Algorithm implements zstd compression/decompression.
+type Algorithm struct {
+	once    sync.Once
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	initErr error
+}
+
+// New creates new zstd algorithm instance.
+func New() *Algorithm {
+	return &Algorithm{}
+}
+
+// init lazily creates encoder and decoder so that registering the algorithm is cheap.
+func (a *Algorithm) init() error {
+	a.once.Do(func() {
+		a.encoder, a.initErr = zstd.NewWriter(nil)
+		if a.initErr != nil {
+			return
+		}
+
+		a.decoder, a.initErr = zstd.NewReader(nil)
+	})
+
+	return a.initErr
+}
+
+// Compress will compress data using zstd.
+func (a *Algorithm) Compress(data []byte) ([]byte, error) {
+	if err := a.init(); err != nil {
+		return nil, fmt.Errorf("failed to create encoder: %s", err.Error())
+	}
+
+	return a.encoder.EncodeAll(data, nil), nil
+}
+
+// Decompress will decompress compressed data.
+func (a *Algorithm) Decompress(data []byte) ([]byte, error) {
+	if err := a.init(); err != nil {
+		return nil, fmt.Errorf("failed to create decoder: %s", err.Error())
+	}
+
+	bytes, err := a.decoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed data: %s", err.Error())
+	}
+
+	return bytes, nil
+}
+
+// CompressStream will compress data from reader using zstd and write it to writer.
+func (a *Algorithm) CompressStream(r io.Reader, w io.Writer) error {
+	encoder, err := zstd.NewWriter(w)
+	if err != nil {
+		return fmt.Errorf("failed to create encoder: %s", err.Error())
+	}
+
+	_, err = io.Copy(encoder, r)
+	if err != nil {
+		encoder.Close() //nolint:errcheck
+
+		return fmt.Errorf("failed to write data: %s", err.Error())
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to close encoder: %s", err.Error())
+	}
+
+	return nil
+}
+
+// DecompressStream will decompress compressed data from reader and write it to writer.
+func (a *Algorithm) DecompressStream(r io.Reader, w io.Writer) error {
+	decoder, err := zstd.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %s", err.Error())
+	}
+
+	defer decoder.Close()
+
+	_, err = io.Copy(w, decoder)
+	if err != nil {
+		return fmt.Errorf("failed to read compressed data: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Accept algorithm.
+func (a *Algorithm) Accept(alg string) bool {
+	return alg == algName
+}
+
+// Close closes open resources.
+func (a *Algorithm) Close() error {
+	if a.decoder != nil {
+		a.decoder.Close()
+	}
+
+	if a.encoder != nil {
+		return a.encoder.Close()
+	}
+
+	return nil
+}
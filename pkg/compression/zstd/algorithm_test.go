@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package zstd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/compression/gzip"
+)
+
+func TestAlgorithm_Accept(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		alg := New()
+		require.True(t, alg.Accept("ZSTD"))
+		require.False(t, alg.Accept("other"))
+	})
+}
+
+func TestAlgorithm_Compress(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		alg := New()
+
+		test := []byte("test data")
+		compressed, err := alg.Compress(test)
+		require.NoError(t, err)
+		require.NotEmpty(t, compressed)
+
+		data, err := alg.Decompress(compressed)
+		require.NoError(t, err)
+		require.NotEmpty(t, data)
+		require.Equal(t, data, test)
+	})
+}
+
+func TestAlgorithm_Decompress(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		alg := New()
+
+		test := []byte("hello world")
+		compressed, err := alg.Compress(test)
+		require.NoError(t, err)
+		require.NotEmpty(t, compressed)
+
+		data, err := alg.Decompress(compressed)
+		require.NoError(t, err)
+		require.NotEmpty(t, data)
+		require.Equal(t, data, test)
+	})
+	t.Run("error - data not compressed", func(t *testing.T) {
+		alg := New()
+
+		test := []byte("test data")
+		data, err := alg.Decompress(test)
+		require.Error(t, err)
+		require.Empty(t, data)
+		require.Contains(t, err.Error(), "failed to read compressed data")
+	})
+}
+
+func TestAlgorithm_Stream(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		alg := New()
+
+		test := bytes.Repeat([]byte("hello world "), 100000)
+
+		var compressed bytes.Buffer
+		require.NoError(t, alg.CompressStream(bytes.NewReader(test), &compressed))
+
+		var data bytes.Buffer
+		require.NoError(t, alg.DecompressStream(&compressed, &data))
+		require.Equal(t, test, data.Bytes())
+	})
+	t.Run("error - data not compressed", func(t *testing.T) {
+		alg := New()
+
+		var data bytes.Buffer
+		err := alg.DecompressStream(bytes.NewReader([]byte("test data")), &data)
+		require.Error(t, err)
+	})
+}
+
+func TestAlgorithm_Close(t *testing.T) {
+	t.Run("success - not used", func(t *testing.T) {
+		alg := New()
+		require.NoError(t, alg.Close())
+	})
+	t.Run("success - after use", func(t *testing.T) {
+		alg := New()
+
+		_, err := alg.Compress([]byte("test data"))
+		require.NoError(t, err)
+
+		require.NoError(t, alg.Close())
+	})
+}
+
+func BenchmarkCompress(b *testing.B) {
+	chunkFile := []byte(getTestChunkFile(100))
+
+	b.Run("ZSTD", func(b *testing.B) {
+		alg := New()
+		defer alg.Close() // nolint: errcheck
+
+		var size int
+
+		for i := 0; i < b.N; i++ {
+			compressed, err := alg.Compress(chunkFile)
+			require.NoError(b, err)
+
+			size = len(compressed)
+		}
+
+		b.ReportMetric(float64(size), "bytes")
+	})
+
+	b.Run("GZIP", func(b *testing.B) {
+		alg := gzip.New()
+
+		var size int
+
+		for i := 0; i < b.N; i++ {
+			compressed, err := alg.Compress(chunkFile)
+			require.NoError(b, err)
+
+			size = len(compressed)
+		}
+
+		b.ReportMetric(float64(size), "bytes")
+	})
+}
+
+// getTestChunkFile returns chunk file content with the given number of deltas.
+func getTestChunkFile(numOfDeltas int) string {
+	var deltas []string
+
+	for i := 0; i < numOfDeltas; i++ {
+		deltas = append(deltas, fmt.Sprintf(deltaTemplate, i, i))
+	}
+
+	return fmt.Sprintf(`{"deltas":[%s]}`, strings.Join(deltas, ","))
+}
+
+const deltaTemplate = `{"patches":[{"action":"add-public-keys","publicKeys":[{"id":"key-%d","publicKeyJwk":{"crv":"P-256","kty":"EC","x":"PUymIqdtF_qxaAqPABSw-C-owT1KYYQbsMKFM-L9fJA","y":"nM84jDHCMOTGTh_ZdHq4dBBdo4Z5PkEOW9jA8z8IsGc"},"purposes":["authentication"],"type":"JsonWebKey2020"}]},{"action":"add-services","services":[{"id":"service-%d","serviceEndpoint":"http://www.example.com","type":"LinkedDomains"}]}],"updateCommitment":"EiDKIkwqO69IPG3pOlHkdb86nYt0aNxSHZu2r-bhEznjdA"}`
@@ -240,8 +240,9 @@ func (r *DocumentHandler) ResolveDocument(shortOrLongFormDID string) (*document.
 		return nil, fmt.Errorf("%s: %s", badRequest, err.Error())
 	}
 
-	// resolve document from the blockchain
-	doc, err := r.resolveRequestWithID(shortFormDID, uniquePortion, pv)
+	// resolve document from the blockchain; for published long-form DID requested DID is kept as document id
+	// and short-form DID is returned as canonical id
+	doc, err := r.resolveRequestWithID(shortOrLongFormDID, uniquePortion, pv)
 	if err == nil {
 		return doc, nil
 	}
@@ -270,7 +271,7 @@ func (r *DocumentHandler) getNamespace(shortOrLongFormDID string) (string, error
 	return "", fmt.Errorf("did must start with configured namespace[%s] or aliases%v", r.namespace, r.aliases)
 }
 
-func (r *DocumentHandler) resolveRequestWithID(shortFormDid, uniquePortion string, pv protocol.Version) (*document.ResolutionResult, error) {
+func (r *DocumentHandler) resolveRequestWithID(did, uniquePortion string, pv protocol.Version) (*document.ResolutionResult, error) {
 	internalResult, err := r.processor.Resolve(uniquePortion)
 	if err != nil {
 		logger.Debugf("Failed to resolve uniquePortion[%s]: %s", uniquePortion, err.Error())
@@ -279,7 +280,7 @@ func (r *DocumentHandler) resolveRequestWithID(shortFormDid, uniquePortion strin
 	}
 
 	ti := make(protocol.TransformationInfo)
-	ti[document.IDProperty] = shortFormDid
+	ti[document.IDProperty] = did
 	ti[document.PublishedProperty] = true
 
 	canonicalRef := ""
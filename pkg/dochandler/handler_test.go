@@ -290,6 +290,41 @@ func TestDocumentHandler_ResolveDocument_InitialValue(t *testing.T) {
 		require.Contains(t, equivalentIds[1], fmt.Sprintf("%s:%s:%s", namespace, domain, label))
 	})
 
+	t.Run("success - canonical id is set once long-form DID has been anchored", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
+		handler, clean := getDocumentHandlerWithProtocolClient(store, pc)
+		require.NotNil(t, handler)
+		defer clean()
+
+		longFormDID := docID + longFormPart
+
+		// before anchoring: no canonical id
+		result, err := handler.ResolveDocument(longFormDID)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Nil(t, result.DocumentMetadata[document.CanonicalIDProperty])
+
+		equivalentIds := result.DocumentMetadata[document.EquivalentIDProperty].([]string)
+		require.Equal(t, []string{docID}, equivalentIds)
+
+		err = store.Put(getAnchoredCreateOperation())
+		require.NoError(t, err)
+
+		// after anchoring: canonical id is short-form DID
+		result, err = handler.ResolveDocument(longFormDID)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, longFormDID, result.Document.ID())
+		require.Equal(t, docID, result.DocumentMetadata[document.CanonicalIDProperty])
+
+		equivalentIds = result.DocumentMetadata[document.EquivalentIDProperty].([]string)
+		require.Contains(t, equivalentIds, docID)
+
+		methodMetadata, ok := result.DocumentMetadata[document.MethodProperty].(document.Metadata)
+		require.True(t, ok)
+		require.Equal(t, true, methodMetadata[document.PublishedProperty])
+	})
+
 	t.Run("error - invalid initial state format (not encoded JCS)", func(t *testing.T) {
 		result, err := dochandler.ResolveDocument(docID + ":payload")
 		require.Error(t, err)
@@ -38,6 +38,12 @@ const (
 	// EquivalentIDProperty is equivalent ID array.
 	EquivalentIDProperty = "equivalentId"
 
+	// CreatedProperty is created (transaction time of create operation) key.
+	CreatedProperty = "created"
+
+	// UpdatedProperty is updated (transaction time of the last operation) key.
+	UpdatedProperty = "updated"
+
 	// MethodProperty is used for method metadata within did document metadata.
 	MethodProperty = "method"
 )
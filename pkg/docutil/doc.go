@@ -17,9 +17,15 @@ import (
 // NamespaceDelimiter is the delimiter that separates the namespace from the unique suffix.
 const NamespaceDelimiter = ":"
 
+// ComputeUniqueSuffix computes unique suffix (encoded multihash of canonicalized suffix data)
+// using the given multihash algorithm.
+func ComputeUniqueSuffix(suffixData interface{}, multihashCode uint) (string, error) {
+	return hashing.CalculateModelMultihash(suffixData, multihashCode)
+}
+
 // CalculateID calculates the ID from model and namespace.
 func CalculateID(namespace string, value interface{}, hashAlgorithmAsMultihashCode uint) (string, error) {
-	uniqueSuffix, err := hashing.CalculateModelMultihash(value, hashAlgorithmAsMultihashCode)
+	uniqueSuffix, err := ComputeUniqueSuffix(value, hashAlgorithmAsMultihashCode)
 	if err != nil {
 		return "", err
 	}
@@ -38,3 +44,19 @@ func GetNamespaceFromID(id string) (string, error) {
 
 	return id[0:pos], nil
 }
+
+// GetUniqueSuffixFromID returns unique suffix from (short-form) ID; ID has to start with the given namespace.
+func GetUniqueSuffixFromID(namespace, id string) (string, error) {
+	prefix := namespace + NamespaceDelimiter
+
+	if !strings.HasPrefix(id, prefix) {
+		return "", errors.Errorf("ID [%s] doesn't match namespace [%s]", id, namespace)
+	}
+
+	uniqueSuffix := id[len(prefix):]
+	if uniqueSuffix == "" || strings.Contains(uniqueSuffix, NamespaceDelimiter) {
+		return "", errors.Errorf("invalid unique suffix in ID [%s]", id)
+	}
+
+	return uniqueSuffix, nil
+}
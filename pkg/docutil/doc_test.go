@@ -32,6 +32,30 @@ func TestCalculateID(t *testing.T) {
 	})
 }
 
+func TestComputeUniqueSuffix(t *testing.T) {
+	t.Run("success - suffix is derived from suffix data", func(t *testing.T) {
+		uniqueSuffix, err := ComputeUniqueSuffix(suffixDataObject, sha2_256)
+		require.NoError(t, err)
+		require.Equal(t, expectedSuffixForSuffixObject, uniqueSuffix)
+	})
+
+	t.Run("success - tampered suffix data results in different suffix", func(t *testing.T) {
+		tampered := *suffixDataObject
+		tampered.RecoveryCommitment = "tampered"
+
+		uniqueSuffix, err := ComputeUniqueSuffix(&tampered, sha2_256)
+		require.NoError(t, err)
+		require.NotEqual(t, expectedSuffixForSuffixObject, uniqueSuffix)
+	})
+
+	t.Run("error - multihash algorithm not supported", func(t *testing.T) {
+		uniqueSuffix, err := ComputeUniqueSuffix(suffixDataObject, 55)
+		require.Error(t, err)
+		require.Empty(t, uniqueSuffix)
+		require.Contains(t, err.Error(), "algorithm not supported, unable to compute hash")
+	})
+}
+
 func TestDidCalculationError(t *testing.T) {
 	// non-supported mulithash code will cause an error
 	id, err := CalculateID(namespace, suffixDataObject, 55)
@@ -64,6 +88,32 @@ func TestNamespaceFromID(t *testing.T) {
 	})
 }
 
+func TestGetUniqueSuffixFromID(t *testing.T) {
+	const suffix = "123456"
+
+	t.Run("success", func(t *testing.T) {
+		uniqueSuffix, err := GetUniqueSuffixFromID(namespace, namespace+NamespaceDelimiter+suffix)
+		require.NoError(t, err)
+		require.Equal(t, suffix, uniqueSuffix)
+	})
+
+	t.Run("error - namespace mismatch", func(t *testing.T) {
+		uniqueSuffix, err := GetUniqueSuffixFromID(namespace, "did:other:"+suffix)
+		require.Error(t, err)
+		require.Empty(t, uniqueSuffix)
+		require.Contains(t, err.Error(), "ID [did:other:123456] doesn't match namespace [did:sidetree]")
+	})
+
+	t.Run("error - invalid unique suffix", func(t *testing.T) {
+		for _, id := range []string{namespace + NamespaceDelimiter, namespace + ":test:" + suffix} {
+			uniqueSuffix, err := GetUniqueSuffixFromID(namespace, id)
+			require.Error(t, err)
+			require.Empty(t, uniqueSuffix)
+			require.Contains(t, err.Error(), "invalid unique suffix")
+		}
+	})
+}
+
 var suffixDataObject = &struct {
 	DeltaHash          string `json:"deltaHash,omitempty"`
 	RecoveryCommitment string `json:"recoveryCommitment,omitempty"`
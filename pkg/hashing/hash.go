@@ -12,6 +12,8 @@ import (
 	"fmt"
 
 	"github.com/multiformats/go-multihash"
+	_ "golang.org/x/crypto/blake2b" // registers crypto.BLAKE2b_256
+	_ "golang.org/x/crypto/sha3"    // registers crypto.SHA3_256
 
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
 	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
@@ -32,13 +34,21 @@ func ComputeMultihash(multihashCode uint, bytes []byte) ([]byte, error) {
 	return multihash.Encode(hashedBytes, uint64(multihashCode))
 }
 
+// multihash code for blake2b with 256 bit digest (blake2b codes are offset by digest size in bytes).
+const blake2b256 = multihash.BLAKE2B_MIN + 31
+
 // GetHashFromMultihash will return hash based on specified multihash code.
+// Supported multihash codes are sha2-256, sha2-512, sha3-256 and blake2b-256.
 func GetHashFromMultihash(multihashCode uint) (h crypto.Hash, err error) {
 	switch multihashCode {
 	case multihash.SHA2_256:
 		h = crypto.SHA256
 	case multihash.SHA2_512:
 		h = crypto.SHA512
+	case multihash.SHA3_256:
+		h = crypto.SHA3_256
+	case blake2b256:
+		h = crypto.BLAKE2b_256
 	default:
 		err = fmt.Errorf("algorithm not supported, unable to compute hash")
 	}
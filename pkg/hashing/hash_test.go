@@ -19,8 +19,10 @@ import (
 const (
 	algSHA256 = 5
 
-	sha2_256 = 18
-	sha2_512 = 19
+	sha2_256    = 18
+	sha2_512    = 19
+	sha3_256    = 22
+	blake2b_256 = 45600
 )
 
 var sample = []byte("test")
@@ -47,6 +49,56 @@ func TestComputeHash(t *testing.T) {
 	require.NotNil(t, hash)
 }
 
+func TestComputeHash_AdditionalAlgorithms(t *testing.T) {
+	tests := []struct {
+		name string
+		code uint
+		hash crypto.Hash
+	}{
+		{"sha3-256", sha3_256, crypto.SHA3_256},
+		{"blake2b-256", blake2b_256, crypto.BLAKE2b_256},
+	}
+
+	for _, tc := range tests {
+		t.Run("success - "+tc.name, func(t *testing.T) {
+			hash, err := GetHashFromMultihash(tc.code)
+			require.NoError(t, err)
+			require.Equal(t, tc.hash, hash)
+
+			mh, err := ComputeMultihash(tc.code, sample)
+			require.NoError(t, err)
+
+			encoded := encoder.EncodeToString(mh)
+			require.True(t, IsSupportedMultihash(encoded))
+			require.True(t, IsComputedUsingMultihashAlgorithms(encoded, []uint{tc.code}))
+			require.False(t, IsComputedUsingMultihashAlgorithms(encoded, []uint{sha2_256}))
+
+			code, err := GetMultihashCode(encoded)
+			require.NoError(t, err)
+			require.Equal(t, uint64(tc.code), code)
+
+			decoded, err := GetMultihash(encoded)
+			require.NoError(t, err)
+			require.Len(t, decoded.Digest, 32)
+
+			expected, err := GetHash(tc.hash, sample)
+			require.NoError(t, err)
+			require.Equal(t, expected, decoded.Digest)
+
+			// round trip: model hash computed on write is verified on read
+			model := map[string]string{"key": "value"}
+
+			modelHash, err := CalculateModelMultihash(model, tc.code)
+			require.NoError(t, err)
+			require.NoError(t, IsValidModelMultihash(model, modelHash))
+
+			err = IsValidModelMultihash(map[string]string{"key": "other"}, modelHash)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "supplied hash doesn't match original content")
+		})
+	}
+}
+
 func TestIsSupportedMultihash(t *testing.T) {
 	// scenario: not base64 encoded (corrupted input)
 	supported := IsSupportedMultihash("XXXXXaGVsbG8=")
@@ -141,6 +193,31 @@ func TestCalculateModelMultihash(t *testing.T) {
 		require.Contains(t, err.Error(), "algorithm not supported, unable to compute hash")
 	})
 
+	t.Run("success - hash doesn't depend on property order", func(t *testing.T) {
+		delta := []byte(`{"updateCommitment":"EiDOrcmRG1ux_gkWWGa-bbqFiIc5PuYwkFbqf_2XtZPAfA","patches":[{"action":"replace","document":{"publicKeys":[]}}]}`)
+		reordered := []byte(`{"patches":[{"document":{"publicKeys":[]},"action":"replace"}],"updateCommitment":"EiDOrcmRG1ux_gkWWGa-bbqFiIc5PuYwkFbqf_2XtZPAfA"}`)
+
+		hash, err := CalculateModelMultihash(delta, sha2_256)
+		require.NoError(t, err)
+
+		reorderedHash, err := CalculateModelMultihash(reordered, sha2_256)
+		require.NoError(t, err)
+		require.Equal(t, hash, reorderedHash)
+
+		// model (struct) and map with the same content result in the same hash
+		hash, err = CalculateModelMultihash(suffixDataObject, sha2_256)
+		require.NoError(t, err)
+
+		mapHash, err := CalculateModelMultihash(map[string]interface{}{
+			"recoveryCommitment": suffixDataObject.RecoveryCommitment,
+			"deltaHash":          suffixDataObject.DeltaHash,
+		}, sha2_256)
+		require.NoError(t, err)
+		require.Equal(t, hash, mapHash)
+
+		require.NoError(t, IsValidModelMultihash(reordered, reorderedHash))
+	})
+
 	t.Run("error - marshal canonical", func(t *testing.T) {
 		var c chan int
 		result, err := CalculateModelMultihash(c, sha2_256)
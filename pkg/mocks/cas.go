@@ -10,7 +10,9 @@ import (
 	"bytes"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 )
@@ -20,13 +22,15 @@ const sha2_256 = 18
 // MockCasClient mocks CAS for testing purposes.
 type MockCasClient struct {
 	sync.RWMutex
-	m   map[string][]byte
-	err error
+	m         map[string][]byte
+	err       error
+	uriErrors map[string]error
+	readDelay time.Duration
 }
 
 // NewMockCasClient creates mock client.
 func NewMockCasClient(err error) *MockCasClient {
-	return &MockCasClient{m: make(map[string][]byte), err: err}
+	return &MockCasClient{m: make(map[string][]byte), err: err, uriErrors: make(map[string]error)}
 }
 
 // Write writes the given content to CAS.
@@ -54,17 +58,28 @@ func (m *MockCasClient) Write(content []byte) (string, error) {
 // Read reads the content of the given address in CAS.
 // returns the content of the given address.
 func (m *MockCasClient) Read(address string) ([]byte, error) {
+	m.RLock()
+	delay := m.readDelay
+	uriErr := m.uriErrors[address]
+	m.RUnlock()
+
+	time.Sleep(delay)
+
 	err := m.GetError()
 	if err != nil {
 		return nil, err
 	}
 
+	if uriErr != nil {
+		return nil, uriErr
+	}
+
 	m.RLock()
 	defer m.RUnlock()
 
 	value, ok := m.m[address]
 	if !ok {
-		return nil, fmt.Errorf("not found")
+		return nil, fmt.Errorf("%w: address[%s]", cas.ErrContentNotFound, address)
 	}
 
 	// decode address to verify hashes
@@ -100,3 +115,25 @@ func (m *MockCasClient) GetError() error {
 
 	return m.err
 }
+
+// SetReadError injects an error returned when reading the given address (nil error removes it).
+func (m *MockCasClient) SetReadError(address string, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if err == nil {
+		delete(m.uriErrors, address)
+
+		return
+	}
+
+	m.uriErrors[address] = err
+}
+
+// SetReadDelay injects an artificial delay into each read.
+func (m *MockCasClient) SetReadDelay(delay time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.readDelay = delay
+}
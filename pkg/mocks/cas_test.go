@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockCasClient(t *testing.T) {
+	t.Run("success - write and read", func(t *testing.T) {
+		c := NewMockCasClient(nil)
+
+		address, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		content, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+	})
+
+	t.Run("error - global error", func(t *testing.T) {
+		c := NewMockCasClient(errors.New("CAS error"))
+
+		address, err := c.Write([]byte("content"))
+		require.EqualError(t, err, "CAS error")
+		require.Empty(t, address)
+
+		content, err := c.Read("address")
+		require.EqualError(t, err, "CAS error")
+		require.Nil(t, content)
+	})
+
+	t.Run("error - per-URI read error", func(t *testing.T) {
+		c := NewMockCasClient(nil)
+
+		failing, err := c.Write([]byte("failing"))
+		require.NoError(t, err)
+
+		working, err := c.Write([]byte("working"))
+		require.NoError(t, err)
+
+		c.SetReadError(failing, errors.New("read error"))
+
+		content, err := c.Read(failing)
+		require.EqualError(t, err, "read error")
+		require.Nil(t, content)
+
+		content, err = c.Read(working)
+		require.NoError(t, err)
+		require.Equal(t, []byte("working"), content)
+
+		// remove injected error
+		c.SetReadError(failing, nil)
+
+		content, err = c.Read(failing)
+		require.NoError(t, err)
+		require.Equal(t, []byte("failing"), content)
+	})
+
+	t.Run("success - read delay", func(t *testing.T) {
+		const delay = 50 * time.Millisecond
+
+		c := NewMockCasClient(nil)
+		c.SetReadDelay(delay)
+
+		address, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		start := time.Now()
+
+		content, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+		require.True(t, time.Since(start) >= delay)
+	})
+}
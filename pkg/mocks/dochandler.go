@@ -26,8 +26,9 @@ const deleted = "_deleted"
 // NewMockDocumentHandler returns a new mock document handler.
 func NewMockDocumentHandler() *MockDocumentHandler {
 	return &MockDocumentHandler{
-		client: NewMockProtocolClient(),
-		store:  make(map[string]document.Document),
+		client:   NewMockProtocolClient(),
+		store:    make(map[string]document.Document),
+		composer: doccomposer.New(),
 	}
 }
 
@@ -37,6 +38,7 @@ type MockDocumentHandler struct {
 	namespace string
 	client    protocol.Client
 	store     map[string]document.Document
+	composer  protocol.DocumentComposer
 }
 
 // WithNamespace sets the namespace.
@@ -60,6 +62,13 @@ func (m *MockDocumentHandler) WithProtocolClient(client protocol.Client) *MockDo
 	return m
 }
 
+// WithDocumentComposer sets the document composer (default is doccomposer.New()).
+func (m *MockDocumentHandler) WithDocumentComposer(composer protocol.DocumentComposer) *MockDocumentHandler {
+	m.composer = composer
+
+	return m
+}
+
 // Namespace returns the namespace.
 func (m *MockDocumentHandler) Namespace() string {
 	return m.namespace
@@ -127,7 +136,7 @@ func (m *MockDocumentHandler) ProcessOperation(operationBuffer []byte, _ uint64)
 		doc = make(document.Document)
 	}
 
-	doc, err = doccomposer.New().ApplyPatches(doc, op.Delta.Patches)
+	doc, err = m.composer.ApplyPatches(doc, op.Delta.Patches)
 	if err != nil {
 		return nil, err
 	}
@@ -207,7 +216,7 @@ func (m *MockDocumentHandler) resolveWithInitialState(did string, initial []byte
 		return nil, err
 	}
 
-	doc, err := doccomposer.New().ApplyPatches(make(document.Document), createReq.Delta.Patches)
+	doc, err := m.composer.ApplyPatches(make(document.Document), createReq.Delta.Patches)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@
 package mocks
 
 import (
+	"context"
 	"sync"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
@@ -23,6 +24,20 @@ type OperationProvider struct {
 		result1 []*operation.AnchoredOperation
 		result2 error
 	}
+	GetTxnOperationsContextStub        func(context.Context, *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)
+	getTxnOperationsContextMutex       sync.RWMutex
+	getTxnOperationsContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 *txn.SidetreeTxn
+	}
+	getTxnOperationsContextReturns struct {
+		result1 []*operation.AnchoredOperation
+		result2 error
+	}
+	getTxnOperationsContextReturnsOnCall map[int]struct {
+		result1 []*operation.AnchoredOperation
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -90,11 +105,77 @@ func (fake *OperationProvider) GetTxnOperationsReturnsOnCall(i int, result1 []*o
 	}{result1, result2}
 }
 
+func (fake *OperationProvider) GetTxnOperationsContext(arg1 context.Context, arg2 *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	fake.getTxnOperationsContextMutex.Lock()
+	ret, specificReturn := fake.getTxnOperationsContextReturnsOnCall[len(fake.getTxnOperationsContextArgsForCall)]
+	fake.getTxnOperationsContextArgsForCall = append(fake.getTxnOperationsContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 *txn.SidetreeTxn
+	}{arg1, arg2})
+	fake.recordInvocation("GetTxnOperationsContext", []interface{}{arg1, arg2})
+	fake.getTxnOperationsContextMutex.Unlock()
+	if fake.GetTxnOperationsContextStub != nil {
+		return fake.GetTxnOperationsContextStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getTxnOperationsContextReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *OperationProvider) GetTxnOperationsContextCallCount() int {
+	fake.getTxnOperationsContextMutex.RLock()
+	defer fake.getTxnOperationsContextMutex.RUnlock()
+	return len(fake.getTxnOperationsContextArgsForCall)
+}
+
+func (fake *OperationProvider) GetTxnOperationsContextCalls(stub func(context.Context, *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)) {
+	fake.getTxnOperationsContextMutex.Lock()
+	defer fake.getTxnOperationsContextMutex.Unlock()
+	fake.GetTxnOperationsContextStub = stub
+}
+
+func (fake *OperationProvider) GetTxnOperationsContextArgsForCall(i int) (context.Context, *txn.SidetreeTxn) {
+	fake.getTxnOperationsContextMutex.RLock()
+	defer fake.getTxnOperationsContextMutex.RUnlock()
+	argsForCall := fake.getTxnOperationsContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *OperationProvider) GetTxnOperationsContextReturns(result1 []*operation.AnchoredOperation, result2 error) {
+	fake.getTxnOperationsContextMutex.Lock()
+	defer fake.getTxnOperationsContextMutex.Unlock()
+	fake.GetTxnOperationsContextStub = nil
+	fake.getTxnOperationsContextReturns = struct {
+		result1 []*operation.AnchoredOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *OperationProvider) GetTxnOperationsContextReturnsOnCall(i int, result1 []*operation.AnchoredOperation, result2 error) {
+	fake.getTxnOperationsContextMutex.Lock()
+	defer fake.getTxnOperationsContextMutex.Unlock()
+	fake.GetTxnOperationsContextStub = nil
+	if fake.getTxnOperationsContextReturnsOnCall == nil {
+		fake.getTxnOperationsContextReturnsOnCall = make(map[int]struct {
+			result1 []*operation.AnchoredOperation
+			result2 error
+		})
+	}
+	fake.getTxnOperationsContextReturnsOnCall[i] = struct {
+		result1 []*operation.AnchoredOperation
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *OperationProvider) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getTxnOperationsMutex.RLock()
 	defer fake.getTxnOperationsMutex.RUnlock()
+	fake.getTxnOperationsContextMutex.RLock()
+	defer fake.getTxnOperationsContextMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
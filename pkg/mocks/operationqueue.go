@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/batch/opqueue"
+)
+
+// MockOperationQueue mocks operation queue for testing purposes. By default it behaves like
+// in-memory operation queue; behaviour of each function can be replaced by setting corresponding
+// function field. Number of calls (and added operations) are recorded.
+type MockOperationQueue struct {
+	AddFunc    func(data *operation.QueuedOperation, protocolGenesisTime uint64) (uint, error)
+	PeekFunc   func(num uint) (operation.QueuedOperationsAtTime, error)
+	RemoveFunc func(num uint) (operation.QueuedOperationsAtTime, func() uint, func(), error)
+	LenFunc    func() uint
+
+	mutex       sync.RWMutex
+	queue       *opqueue.MemQueue
+	added       []*operation.QueuedOperation
+	addCalls    int
+	peekCalls   int
+	removeCalls int
+	lenCalls    int
+}
+
+// NewMockOperationQueue creates mock operation queue.
+func NewMockOperationQueue() *MockOperationQueue {
+	return &MockOperationQueue{queue: &opqueue.MemQueue{}}
+}
+
+// Add adds the given operation to the tail of the queue and returns the new length of the queue.
+func (m *MockOperationQueue) Add(data *operation.QueuedOperation, protocolGenesisTime uint64) (uint, error) {
+	m.mutex.Lock()
+	m.addCalls++
+	m.added = append(m.added, data)
+	m.mutex.Unlock()
+
+	if m.AddFunc != nil {
+		return m.AddFunc(data, protocolGenesisTime)
+	}
+
+	return m.queue.Add(data, protocolGenesisTime)
+}
+
+// Peek returns (up to) the given number of operations from the head of the queue but does not remove them.
+func (m *MockOperationQueue) Peek(num uint) (operation.QueuedOperationsAtTime, error) {
+	m.mutex.Lock()
+	m.peekCalls++
+	m.mutex.Unlock()
+
+	if m.PeekFunc != nil {
+		return m.PeekFunc(num)
+	}
+
+	return m.queue.Peek(num)
+}
+
+// Remove removes (up to) the given number of operations from the head of the queue.
+func (m *MockOperationQueue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error) {
+	m.mutex.Lock()
+	m.removeCalls++
+	m.mutex.Unlock()
+
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(num)
+	}
+
+	return m.queue.Remove(num)
+}
+
+// Len returns the number of operations in the queue.
+func (m *MockOperationQueue) Len() uint {
+	m.mutex.Lock()
+	m.lenCalls++
+	m.mutex.Unlock()
+
+	if m.LenFunc != nil {
+		return m.LenFunc()
+	}
+
+	return m.queue.Len()
+}
+
+// AddCallCount returns the number of times Add was called.
+func (m *MockOperationQueue) AddCallCount() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.addCalls
+}
+
+// PeekCallCount returns the number of times Peek was called.
+func (m *MockOperationQueue) PeekCallCount() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.peekCalls
+}
+
+// RemoveCallCount returns the number of times Remove was called.
+func (m *MockOperationQueue) RemoveCallCount() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.removeCalls
+}
+
+// LenCallCount returns the number of times Len was called.
+func (m *MockOperationQueue) LenCallCount() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.lenCalls
+}
+
+// Added returns operations passed to Add (in order of calls).
+func (m *MockOperationQueue) Added() []*operation.QueuedOperation {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	added := make([]*operation.QueuedOperation, len(m.added))
+	copy(added, m.added)
+
+	return added
+}
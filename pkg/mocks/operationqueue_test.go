@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+func TestMockOperationQueue(t *testing.T) {
+	op1 := &operation.QueuedOperation{UniqueSuffix: "op1"}
+	op2 := &operation.QueuedOperation{UniqueSuffix: "op2"}
+
+	t.Run("success - default in-memory behaviour", func(t *testing.T) {
+		q := NewMockOperationQueue()
+
+		l, err := q.Add(op1, 10)
+		require.NoError(t, err)
+		require.Equal(t, uint(1), l)
+
+		l, err = q.Add(op2, 10)
+		require.NoError(t, err)
+		require.Equal(t, uint(2), l)
+
+		ops, err := q.Peek(1)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, "op1", ops[0].UniqueSuffix)
+		require.Equal(t, uint64(10), ops[0].ProtocolGenesisTime)
+
+		ops, ack, nack, err := q.Remove(1)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.NotNil(t, nack)
+		require.Equal(t, uint(1), ack())
+
+		require.Equal(t, uint(1), q.Len())
+
+		require.Equal(t, 2, q.AddCallCount())
+		require.Equal(t, 1, q.PeekCallCount())
+		require.Equal(t, 1, q.RemoveCallCount())
+		require.Equal(t, 1, q.LenCallCount())
+		require.Equal(t, []*operation.QueuedOperation{op1, op2}, q.Added())
+	})
+
+	t.Run("success - injected functions", func(t *testing.T) {
+		q := NewMockOperationQueue()
+		q.AddFunc = func(*operation.QueuedOperation, uint64) (uint, error) {
+			return 0, errors.New("add error")
+		}
+		q.PeekFunc = func(uint) (operation.QueuedOperationsAtTime, error) {
+			return nil, errors.New("peek error")
+		}
+		q.RemoveFunc = func(uint) (operation.QueuedOperationsAtTime, func() uint, func(), error) {
+			return nil, nil, nil, errors.New("remove error")
+		}
+		q.LenFunc = func() uint {
+			return 5
+		}
+
+		_, err := q.Add(op1, 0)
+		require.EqualError(t, err, "add error")
+
+		_, err = q.Peek(1)
+		require.EqualError(t, err, "peek error")
+
+		_, _, _, err = q.Remove(1)
+		require.EqualError(t, err, "remove error")
+
+		require.Equal(t, uint(5), q.Len())
+
+		require.Equal(t, 1, q.AddCallCount())
+		require.Equal(t, 1, q.PeekCallCount())
+		require.Equal(t, 1, q.RemoveCallCount())
+		require.Equal(t, 1, q.LenCallCount())
+
+		// operations are recorded even if add fails
+		require.Equal(t, []*operation.QueuedOperation{op1}, q.Added())
+	})
+}
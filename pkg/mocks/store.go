@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package mocks
 
 import (
-	"errors"
 	"sync"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
@@ -63,5 +62,5 @@ func (m *MockOperationStore) Get(uniqueSuffix string) ([]*operation.AnchoredOper
 		return ops, nil
 	}
 
-	return nil, errors.New("uniqueSuffix not found in the store")
+	return nil, operation.ErrNotFound
 }
@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+// RejectedOperation contains operation that has been rejected by operation filter and rejection reason.
+type RejectedOperation struct {
+	Operation *operation.AnchoredOperation
+	Reason    string
+}
+
+// RejectingOperationFilter is an operation filter that also reports why operations have been rejected.
+type RejectingOperationFilter interface {
+	FilterWithRejections(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, []*RejectedOperation, error)
+}
+
+// FilterBatch groups operations by unique suffix and filters each suffix group concurrently using
+// up to maxWorkers goroutines (one if maxWorkers is not positive). Valid operations are returned
+// grouped by suffix in the order in which suffixes first appear in the given operations.
+func FilterBatch(filter OperationFilter, ops []*operation.AnchoredOperation, maxWorkers int) ([]*operation.AnchoredOperation, error) {
+	validOps, _, err := filterBatch(ops, maxWorkers,
+		func(suffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, []*RejectedOperation, error) {
+			valid, err := filter.Filter(suffix, ops)
+
+			return valid, nil, err
+		})
+
+	return validOps, err
+}
+
+// FilterBatchWithRejections is the same as FilterBatch but it also returns rejected operations
+// together with rejection reasons.
+func FilterBatchWithRejections(filter RejectingOperationFilter, ops []*operation.AnchoredOperation,
+	maxWorkers int) ([]*operation.AnchoredOperation, []*RejectedOperation, error) {
+	return filterBatch(ops, maxWorkers, filter.FilterWithRejections)
+}
+
+type filterFunc func(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, []*RejectedOperation, error)
+
+func filterBatch(ops []*operation.AnchoredOperation, maxWorkers int,
+	filter filterFunc) ([]*operation.AnchoredOperation, []*RejectedOperation, error) {
+	suffixes, groups := groupBySuffix(ops)
+
+	if maxWorkers <= 0 {
+		maxWorkers = 1
+	}
+
+	if maxWorkers > len(suffixes) {
+		maxWorkers = len(suffixes)
+	}
+
+	valid := make([][]*operation.AnchoredOperation, len(suffixes))
+	rejected := make([][]*RejectedOperation, len(suffixes))
+	errs := make([]error, len(suffixes))
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				valid[i], rejected[i], errs[i] = filter(suffixes[i], groups[suffixes[i]])
+			}
+		}()
+	}
+
+	for i := range suffixes {
+		indexes <- i
+	}
+
+	close(indexes)
+
+	wg.Wait()
+
+	var (
+		validOps    []*operation.AnchoredOperation
+		rejectedOps []*RejectedOperation
+	)
+
+	for i, suffix := range suffixes {
+		if errs[i] != nil {
+			return nil, nil, errors.Wrapf(errs[i], "failed to filter operations for suffix[%s]", suffix)
+		}
+
+		validOps = append(validOps, valid[i]...)
+		rejectedOps = append(rejectedOps, rejected[i]...)
+	}
+
+	return validOps, rejectedOps, nil
+}
+
+func groupBySuffix(ops []*operation.AnchoredOperation) ([]string, map[string][]*operation.AnchoredOperation) {
+	var suffixes []string
+
+	groups := make(map[string][]*operation.AnchoredOperation)
+
+	for _, op := range ops {
+		if _, ok := groups[op.UniqueSuffix]; !ok {
+			suffixes = append(suffixes, op.UniqueSuffix)
+		}
+
+		groups[op.UniqueSuffix] = append(groups[op.UniqueSuffix], op)
+	}
+
+	return suffixes, groups
+}
@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+func TestFilterBatch(t *testing.T) {
+	var ops []*operation.AnchoredOperation
+
+	for i := 0; i < 20; i++ {
+		ops = append(ops,
+			&operation.AnchoredOperation{UniqueSuffix: fmt.Sprintf("suffix-%d", i%7), Type: operation.TypeUpdate, TransactionTime: uint64(i)},
+		)
+	}
+
+	filter := &mockOperationFilter{}
+
+	// sequential result
+	suffixes, groups := groupBySuffix(ops)
+
+	var expected []*operation.AnchoredOperation
+
+	for _, suffix := range suffixes {
+		valid, err := filter.Filter(suffix, groups[suffix])
+		require.NoError(t, err)
+
+		expected = append(expected, valid...)
+	}
+
+	require.NotEmpty(t, expected)
+	require.True(t, len(expected) < len(ops))
+
+	t.Run("success", func(t *testing.T) {
+		for _, workers := range []int{0, 1, 3, 100} {
+			validOps, err := FilterBatch(filter, ops, workers)
+			require.NoError(t, err)
+			require.Equal(t, expected, validOps)
+		}
+	})
+
+	t.Run("success - no operations", func(t *testing.T) {
+		validOps, err := FilterBatch(filter, nil, 5)
+		require.NoError(t, err)
+		require.Empty(t, validOps)
+	})
+
+	t.Run("error - filter error", func(t *testing.T) {
+		validOps, err := FilterBatch(&mockOperationFilter{errSuffix: "suffix-3"}, ops, 3)
+		require.Error(t, err)
+		require.Nil(t, validOps)
+		require.Contains(t, err.Error(), "failed to filter operations for suffix[suffix-3]: filter error")
+	})
+}
+
+func TestFilterBatchWithRejections(t *testing.T) {
+	ops := []*operation.AnchoredOperation{
+		{UniqueSuffix: "abc", Type: operation.TypeCreate, TransactionTime: 2},
+		{UniqueSuffix: "abc", Type: operation.TypeUpdate, TransactionTime: 3},
+		{UniqueSuffix: "def", Type: operation.TypeUpdate, TransactionTime: 4},
+		{UniqueSuffix: "def", Type: operation.TypeUpdate, TransactionTime: 5},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		validOps, rejectedOps, err := FilterBatchWithRejections(&mockOperationFilter{}, ops, 2)
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{ops[0], ops[2]}, validOps)
+		require.Equal(t, []*RejectedOperation{
+			{Operation: ops[1], Reason: "odd transaction time"},
+			{Operation: ops[3], Reason: "odd transaction time"},
+		}, rejectedOps)
+	})
+
+	t.Run("error - filter error", func(t *testing.T) {
+		validOps, rejectedOps, err := FilterBatchWithRejections(&mockOperationFilter{errSuffix: "def"}, ops, 2)
+		require.Error(t, err)
+		require.Nil(t, validOps)
+		require.Nil(t, rejectedOps)
+		require.Contains(t, err.Error(), "failed to filter operations for suffix[def]: filter error")
+	})
+}
+
+// mockOperationFilter keeps operations with even transaction time.
+type mockOperationFilter struct {
+	errSuffix string
+}
+
+func (m *mockOperationFilter) Filter(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+	validOps, _, err := m.FilterWithRejections(uniqueSuffix, ops)
+
+	return validOps, err
+}
+
+func (m *mockOperationFilter) FilterWithRejections(uniqueSuffix string,
+	ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, []*RejectedOperation, error) {
+	if uniqueSuffix == m.errSuffix {
+		return nil, nil, errors.New("filter error")
+	}
+
+	var (
+		validOps    []*operation.AnchoredOperation
+		rejectedOps []*RejectedOperation
+	)
+
+	for _, op := range ops {
+		if op.UniqueSuffix != uniqueSuffix {
+			return nil, nil, fmt.Errorf("unexpected suffix[%s]", op.UniqueSuffix)
+		}
+
+		if op.TransactionTime%2 != 0 {
+			rejectedOps = append(rejectedOps, &RejectedOperation{Operation: op, Reason: "odd transaction time"})
+
+			continue
+		}
+
+		validOps = append(validOps, op)
+	}
+
+	return validOps, rejectedOps, nil
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package observer
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -74,6 +75,66 @@ func TestStartObserver(t *testing.T) {
 	})
 }
 
+func TestObserver_PerNamespaceProtocol(t *testing.T) {
+	const (
+		namespace1 = "did:ns1"
+		namespace2 = "did:ns2"
+
+		sha2_256 = 18
+		sha2_512 = 19
+	)
+
+	newProtocolClient := func(multihashAlg uint) (*mocks.MockProtocolClient, *mocks.TxnProcessor) {
+		tp := &mocks.TxnProcessor{}
+
+		pc := mocks.NewMockProtocolClient()
+		pc.Protocol.MultihashAlgorithms = []uint{multihashAlg}
+		pc.Versions[0].TransactionProcessorReturns(tp)
+		pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+		return pc, tp
+	}
+
+	pc1, tp1 := newProtocolClient(sha2_256)
+	pc2, tp2 := newProtocolClient(sha2_512)
+
+	providers := &Providers{
+		ProtocolClientProvider: mocks.NewMockProtocolClientProvider().
+			WithProtocolClient(namespace1, pc1).
+			WithProtocolClient(namespace2, pc2),
+	}
+
+	o := New(providers)
+
+	txn1 := txn.SidetreeTxn{Namespace: namespace1, TransactionTime: 10, TransactionNumber: 1, AnchorString: "1.address1"}
+	txn2 := txn.SidetreeTxn{Namespace: namespace2, TransactionTime: 11, TransactionNumber: 2, AnchorString: "1.address2"}
+	txn3 := txn.SidetreeTxn{Namespace: namespace1, TransactionTime: 12, TransactionNumber: 3, AnchorString: "1.address3"}
+
+	o.process([]txn.SidetreeTxn{txn1, txn2, txn3})
+
+	// each transaction is processed under the protocol of its own namespace
+	require.Equal(t, 2, tp1.ProcessCallCount())
+	require.Equal(t, 1, tp2.ProcessCallCount())
+
+	processed, _ := tp1.ProcessArgsForCall(0)
+	require.Equal(t, txn1, processed)
+
+	processed, _ = tp1.ProcessArgsForCall(1)
+	require.Equal(t, txn3, processed)
+
+	processed, _ = tp2.ProcessArgsForCall(0)
+	require.Equal(t, txn2, processed)
+
+	for ns, alg := range map[string]uint{namespace1: sha2_256, namespace2: sha2_512} {
+		pc, err := providers.ProtocolClientProvider.ForNamespace(ns)
+		require.NoError(t, err)
+
+		v, err := pc.Get(0)
+		require.NoError(t, err)
+		require.Equal(t, []uint{alg}, v.Protocol().MultihashAlgorithms)
+	}
+}
+
 func TestTxnProcessor_Process(t *testing.T) {
 	t.Run("test error from txn operations provider", func(t *testing.T) {
 		errExpected := fmt.Errorf("txn operations provider error")
@@ -128,6 +189,14 @@ type mockTxnOpsProvider struct {
 }
 
 func (m *mockTxnOpsProvider) GetTxnOperations(txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	return m.GetTxnOperationsContext(context.Background(), txn)
+}
+
+func (m *mockTxnOpsProvider) GetTxnOperationsContext(ctx context.Context, txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if m.err != nil {
 		return nil, m.err
 	}
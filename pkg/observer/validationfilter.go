@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+const missingCreateReason = "missing create operation"
+
+// OperationReader retrieves stored operations for a document. If there are no stored operations for
+// the given unique suffix Get should return either no operations or operation.ErrNotFound (may be wrapped).
+type OperationReader interface {
+	Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error)
+}
+
+// OperationValidationFilter requires that operations reference an existing document: operations for
+// unique suffix are valid only if create operation is either in the filtered operations or in operation store.
+//
+// In strict mode (default) operations for documents without create operation are dropped. In deferred mode
+// such operations are returned as pending (see FilterWithPending) so that they can be retried after the
+// create operation lands (e.g. create operation is in a transaction that has not been processed yet).
+type OperationValidationFilter struct {
+	store              OperationReader
+	deferMissingCreate bool
+}
+
+// ValidationFilterOption is an operation validation filter option.
+type ValidationFilterOption func(opts *OperationValidationFilter)
+
+// WithDeferredMissingCreate returns operations for documents without create operation as pending
+// rather than dropping them.
+func WithDeferredMissingCreate() ValidationFilterOption {
+	return func(opts *OperationValidationFilter) {
+		opts.deferMissingCreate = true
+	}
+}
+
+// NewOperationValidationFilter returns new operation validation filter.
+func NewOperationValidationFilter(store OperationReader, opts ...ValidationFilterOption) *OperationValidationFilter {
+	f := &OperationValidationFilter{store: store}
+
+	// apply options
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Filter returns valid operations for the given unique suffix.
+func (f *OperationValidationFilter) Filter(uniqueSuffix string, ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, error) {
+	valid, _, err := f.FilterWithPending(uniqueSuffix, ops)
+
+	return valid, err
+}
+
+// FilterWithRejections returns valid operations together with operations that have been rejected
+// because of missing create operation. Pending operations (deferred mode) are not reported as rejected.
+func (f *OperationValidationFilter) FilterWithRejections(uniqueSuffix string,
+	ops []*operation.AnchoredOperation) ([]*operation.AnchoredOperation, []*RejectedOperation, error) {
+	exists, err := f.documentExists(uniqueSuffix, ops)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if exists {
+		return ops, nil, nil
+	}
+
+	if f.deferMissingCreate {
+		return nil, nil, nil
+	}
+
+	var rejected []*RejectedOperation
+
+	for _, op := range ops {
+		rejected = append(rejected, &RejectedOperation{Operation: op, Reason: missingCreateReason})
+	}
+
+	return nil, rejected, nil
+}
+
+// FilterWithPending returns valid operations and (in deferred mode) operations that are pending
+// because create operation for the unique suffix has not been processed yet.
+func (f *OperationValidationFilter) FilterWithPending(uniqueSuffix string,
+	ops []*operation.AnchoredOperation) (valid, pending []*operation.AnchoredOperation, err error) {
+	exists, err := f.documentExists(uniqueSuffix, ops)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if exists {
+		return ops, nil, nil
+	}
+
+	if f.deferMissingCreate {
+		return nil, ops, nil
+	}
+
+	return nil, nil, nil
+}
+
+func (f *OperationValidationFilter) documentExists(uniqueSuffix string, ops []*operation.AnchoredOperation) (bool, error) {
+	for _, op := range ops {
+		if op.Type == operation.TypeCreate {
+			return true, nil
+		}
+	}
+
+	storedOps, err := f.store.Get(uniqueSuffix)
+	if errors.Is(err, operation.ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to retrieve operations for suffix[%s]", uniqueSuffix)
+	}
+
+	for _, op := range storedOps {
+		if op.Type == operation.TypeCreate {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+func TestOperationValidationFilter(t *testing.T) {
+	const suffix = "suffix"
+
+	createOp := &operation.AnchoredOperation{UniqueSuffix: suffix, Type: operation.TypeCreate}
+	updateOp := &operation.AnchoredOperation{UniqueSuffix: suffix, Type: operation.TypeUpdate}
+
+	emptyStore := &mockOperationStore{}
+	createdStore := &mockOperationStore{getFunc: func(string) ([]*operation.AnchoredOperation, error) {
+		return []*operation.AnchoredOperation{createOp}, nil
+	}}
+
+	t.Run("strict mode", func(t *testing.T) {
+		t.Run("success - create operation in filtered operations", func(t *testing.T) {
+			valid, err := NewOperationValidationFilter(emptyStore).Filter(suffix, []*operation.AnchoredOperation{createOp, updateOp})
+			require.NoError(t, err)
+			require.Equal(t, []*operation.AnchoredOperation{createOp, updateOp}, valid)
+		})
+
+		t.Run("success - create operation in store", func(t *testing.T) {
+			valid, pending, err := NewOperationValidationFilter(createdStore).FilterWithPending(suffix, []*operation.AnchoredOperation{updateOp})
+			require.NoError(t, err)
+			require.Equal(t, []*operation.AnchoredOperation{updateOp}, valid)
+			require.Empty(t, pending)
+		})
+
+		t.Run("success - update without create is dropped", func(t *testing.T) {
+			f := NewOperationValidationFilter(emptyStore)
+
+			valid, pending, err := f.FilterWithPending(suffix, []*operation.AnchoredOperation{updateOp})
+			require.NoError(t, err)
+			require.Empty(t, valid)
+			require.Empty(t, pending)
+
+			valid, rejected, err := f.FilterWithRejections(suffix, []*operation.AnchoredOperation{updateOp})
+			require.NoError(t, err)
+			require.Empty(t, valid)
+			require.Len(t, rejected, 1)
+			require.Equal(t, updateOp, rejected[0].Operation)
+			require.Equal(t, "missing create operation", rejected[0].Reason)
+		})
+	})
+
+	t.Run("deferred mode", func(t *testing.T) {
+		t.Run("success - update without create is pending", func(t *testing.T) {
+			f := NewOperationValidationFilter(emptyStore, WithDeferredMissingCreate())
+
+			valid, pending, err := f.FilterWithPending(suffix, []*operation.AnchoredOperation{updateOp})
+			require.NoError(t, err)
+			require.Empty(t, valid)
+			require.Equal(t, []*operation.AnchoredOperation{updateOp}, pending)
+
+			valid, err = f.Filter(suffix, []*operation.AnchoredOperation{updateOp})
+			require.NoError(t, err)
+			require.Empty(t, valid)
+
+			valid, rejected, err := f.FilterWithRejections(suffix, []*operation.AnchoredOperation{updateOp})
+			require.NoError(t, err)
+			require.Empty(t, valid)
+			require.Empty(t, rejected)
+		})
+
+		t.Run("success - pending update is valid after create lands", func(t *testing.T) {
+			store := &mockOperationStore{}
+
+			f := NewOperationValidationFilter(store, WithDeferredMissingCreate())
+
+			_, pending, err := f.FilterWithPending(suffix, []*operation.AnchoredOperation{updateOp})
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+
+			store.getFunc = createdStore.getFunc
+
+			valid, pending, err := f.FilterWithPending(suffix, pending)
+			require.NoError(t, err)
+			require.Equal(t, []*operation.AnchoredOperation{updateOp}, valid)
+			require.Empty(t, pending)
+
+			valid, rejected, err := f.FilterWithRejections(suffix, valid)
+			require.NoError(t, err)
+			require.Equal(t, []*operation.AnchoredOperation{updateOp}, valid)
+			require.Empty(t, rejected)
+		})
+	})
+
+	t.Run("success - store not found error", func(t *testing.T) {
+		store := &mockOperationStore{getFunc: func(string) ([]*operation.AnchoredOperation, error) {
+			return nil, fmt.Errorf("%w: suffix[suffix]", operation.ErrNotFound)
+		}}
+
+		valid, rejected, err := NewOperationValidationFilter(store).
+			FilterWithRejections(suffix, []*operation.AnchoredOperation{updateOp})
+		require.NoError(t, err)
+		require.Empty(t, valid)
+		require.Len(t, rejected, 1)
+		require.Equal(t, updateOp, rejected[0].Operation)
+	})
+
+	t.Run("error - store error", func(t *testing.T) {
+		store := &mockOperationStore{getFunc: func(string) ([]*operation.AnchoredOperation, error) {
+			return nil, errors.New("store error")
+		}}
+
+		f := NewOperationValidationFilter(store, WithDeferredMissingCreate())
+
+		valid, pending, err := f.FilterWithPending(suffix, []*operation.AnchoredOperation{updateOp})
+		require.Error(t, err)
+		require.Empty(t, valid)
+		require.Empty(t, pending)
+		require.Contains(t, err.Error(), "failed to retrieve operations for suffix[suffix]: store error")
+
+		valid, rejected, err := f.FilterWithRejections(suffix, []*operation.AnchoredOperation{updateOp})
+		require.Error(t, err)
+		require.Empty(t, valid)
+		require.Empty(t, rejected)
+	})
+
+	t.Run("success - filter batch", func(t *testing.T) {
+		otherUpdateOp := &operation.AnchoredOperation{UniqueSuffix: "other", Type: operation.TypeUpdate}
+
+		valid, err := FilterBatch(NewOperationValidationFilter(emptyStore),
+			[]*operation.AnchoredOperation{createOp, updateOp, otherUpdateOp}, 2)
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{createOp, updateOp}, valid)
+	})
+}
@@ -0,0 +1,54 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+// BulkOperationStoreClient defines interface for retrieving operations related to multiple documents
+// with a single call. Operation store client may implement it in order to optimize bulk retrieval.
+type BulkOperationStoreClient interface {
+	OperationStoreClient
+
+	// GetBulk retrieves all operations related to each of the given documents (keyed by unique suffix)
+	GetBulk(uniqueSuffixes []string) (map[string][]*operation.AnchoredOperation, error)
+}
+
+// NewBulkOperationStoreClient returns the given store if it implements BulkOperationStoreClient; otherwise
+// the given store is wrapped with default implementation that retrieves operations for each unique suffix.
+func NewBulkOperationStoreClient(store OperationStoreClient) BulkOperationStoreClient {
+	if bulkStore, ok := store.(BulkOperationStoreClient); ok {
+		return bulkStore
+	}
+
+	return &bulkStoreAdapter{OperationStoreClient: store}
+}
+
+// bulkStoreAdapter implements GetBulk by retrieving operations for one unique suffix at a time.
+type bulkStoreAdapter struct {
+	OperationStoreClient
+}
+
+// GetBulk retrieves operations for each of the given unique suffixes. Error is returned
+// if operations for any of the unique suffixes cannot be retrieved.
+func (a *bulkStoreAdapter) GetBulk(uniqueSuffixes []string) (map[string][]*operation.AnchoredOperation, error) {
+	result := make(map[string][]*operation.AnchoredOperation)
+
+	for _, uniqueSuffix := range uniqueSuffixes {
+		ops, err := a.Get(uniqueSuffix)
+		if err != nil {
+			return nil, fmt.Errorf("get operations for unique suffix[%s]: %s", uniqueSuffix, err.Error())
+		}
+
+		result[uniqueSuffix] = ops
+	}
+
+	return result, nil
+}
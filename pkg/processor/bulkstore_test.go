@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+func TestNewBulkOperationStoreClient(t *testing.T) {
+	store := mocks.NewMockOperationStore(nil)
+
+	for _, suffix := range []string{"suffix1", "suffix2"} {
+		require.NoError(t, store.Put(&operation.AnchoredOperation{Type: operation.TypeCreate, UniqueSuffix: suffix}))
+	}
+
+	t.Run("success - default implementation retrieves operations for each suffix", func(t *testing.T) {
+		ops, err := NewBulkOperationStoreClient(store).GetBulk([]string{"suffix1", "suffix2"})
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.Len(t, ops["suffix1"], 1)
+		require.Len(t, ops["suffix2"], 1)
+		require.Equal(t, "suffix1", ops["suffix1"][0].UniqueSuffix)
+		require.Equal(t, "suffix2", ops["suffix2"][0].UniqueSuffix)
+	})
+
+	t.Run("success - default implementation with no suffixes", func(t *testing.T) {
+		ops, err := NewBulkOperationStoreClient(store).GetBulk(nil)
+		require.NoError(t, err)
+		require.Empty(t, ops)
+	})
+
+	t.Run("success - store that implements bulk retrieval is returned as is", func(t *testing.T) {
+		bulkStore := &bulkOperationStore{MockOperationStore: store}
+
+		client := NewBulkOperationStoreClient(bulkStore)
+		require.Same(t, bulkStore, client)
+
+		ops, err := client.GetBulk([]string{"suffix1", "suffix2", "unknown"})
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.Equal(t, 1, bulkStore.bulkCalls)
+	})
+
+	t.Run("error - default implementation store error", func(t *testing.T) {
+		ops, err := NewBulkOperationStoreClient(store).GetBulk([]string{"suffix1", "unknown"})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), "get operations for unique suffix[unknown]: uniqueSuffix not found in the store")
+	})
+
+	t.Run("error - store error", func(t *testing.T) {
+		ops, err := NewBulkOperationStoreClient(mocks.NewMockOperationStore(errors.New("store error"))).
+			GetBulk([]string{"suffix1"})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), "store error")
+	})
+}
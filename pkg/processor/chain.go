@@ -0,0 +1,185 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+)
+
+// ChainFindingType is the type of operation chain integrity finding.
+type ChainFindingType string
+
+const (
+	// RevealValueMismatch is reported for operation with reveal value that doesn't match any commitment in the chain.
+	RevealValueMismatch ChainFindingType = "reveal-value-mismatch"
+
+	// RejectedOperation is reported for operation that has not been applied although it matches commitment
+	// in the chain (e.g. invalid signature, reused commitment or another operation for the same commitment applied).
+	RejectedOperation ChainFindingType = "rejected-operation"
+
+	// OperationAfterDeactivate is reported for operation anchored after document has been deactivated.
+	OperationAfterDeactivate ChainFindingType = "operation-after-deactivate"
+
+	// DuplicateTransactionNumber is reported for operation anchored in the same transaction as previous operation.
+	DuplicateTransactionNumber ChainFindingType = "duplicate-transaction-number"
+)
+
+// ChainFinding describes a break in the operation chain.
+type ChainFinding struct {
+	Type      ChainFindingType
+	Operation *operation.AnchoredOperation
+	Reason    string
+}
+
+// ChainReport contains results of operation chain verification.
+type ChainReport struct {
+	UniqueSuffix string
+
+	// Operations is the number of operations for the document in operation store
+	Operations int
+
+	// Applied is the number of operations (including create) that have been applied
+	Applied int
+
+	Findings []*ChainFinding
+}
+
+// VerifyChain walks operation history of the document with the given unique suffix and reports breaks
+// in the operation chain. Resolved document is neither returned nor cached. Error is returned if operations
+// cannot be retrieved or if document doesn't have valid create operation.
+func (s *OperationProcessor) VerifyChain(uniqueSuffix string) (*ChainReport, error) {
+	ops, err := s.store.Get(uniqueSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	sortOperations(ops)
+
+	rm, counter, err := s.replay(context.Background(), uniqueSuffix, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ChainReport{
+		UniqueSuffix: uniqueSuffix,
+		Operations:   len(ops),
+		Applied:      len(counter.applied),
+	}
+
+	report.Findings = append(report.Findings, findDuplicateTransactionNumbers(ops)...)
+	report.Findings = append(report.Findings, s.findUnappliedOperations(ops, rm, counter)...)
+
+	return report, nil
+}
+
+func findDuplicateTransactionNumbers(ops []*operation.AnchoredOperation) []*ChainFinding {
+	var findings []*ChainFinding
+
+	txnOps := make(map[uint64]*operation.AnchoredOperation)
+
+	for _, op := range ops {
+		prev, ok := txnOps[op.TransactionNumber]
+		if !ok {
+			txnOps[op.TransactionNumber] = op
+
+			continue
+		}
+
+		findings = append(findings, &ChainFinding{
+			Type:      DuplicateTransactionNumber,
+			Operation: op,
+			Reason: fmt.Sprintf("transaction number[%d] is already used by '%s' operation",
+				op.TransactionNumber, prev.Type),
+		})
+	}
+
+	return findings
+}
+
+// pre-condition: operations have to be sorted.
+func (s *OperationProcessor) findUnappliedOperations(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, counter *operationCounter) []*ChainFinding {
+	applied := make(map[*operation.AnchoredOperation]bool)
+
+	// commitments that have been established by applied operations
+	commitments := make(map[string]bool)
+
+	for _, a := range counter.applied {
+		applied[a.op] = true
+
+		for _, c := range []string{a.state.UpdateCommitment, a.state.RecoveryCommitment} {
+			if c != "" {
+				commitments[c] = true
+			}
+		}
+	}
+
+	var deactivateOp *operation.AnchoredOperation
+	if rm.Deactivated {
+		deactivateOp = counter.applied[len(counter.applied)-1].op
+	}
+
+	var findings []*ChainFinding
+
+	for _, op := range ops {
+		if applied[op] {
+			continue
+		}
+
+		if deactivateOp != nil && isAnchoredAfter(op, deactivateOp) {
+			findings = append(findings, &ChainFinding{
+				Type:      OperationAfterDeactivate,
+				Operation: op,
+				Reason: fmt.Sprintf("operation is anchored after deactivation at transaction time[%d] and number[%d]",
+					deactivateOp.TransactionTime, deactivateOp.TransactionNumber),
+			})
+
+			continue
+		}
+
+		findings = append(findings, s.checkUnappliedOperation(op, commitments))
+	}
+
+	return findings
+}
+
+func (s *OperationProcessor) checkUnappliedOperation(op *operation.AnchoredOperation, commitments map[string]bool) *ChainFinding {
+	if op.Type == operation.TypeCreate {
+		return &ChainFinding{Type: RejectedOperation, Operation: op, Reason: "create operation has not been applied"}
+	}
+
+	rv, err := s.getRevealValue(op)
+	if err != nil {
+		return &ChainFinding{Type: RejectedOperation, Operation: op, Reason: err.Error()}
+	}
+
+	c, err := commitment.GetCommitmentFromRevealValue(rv)
+	if err != nil {
+		return &ChainFinding{Type: RejectedOperation, Operation: op,
+			Reason: fmt.Sprintf("calculate commitment from reveal value: %s", err.Error())}
+	}
+
+	if !commitments[c] {
+		return &ChainFinding{Type: RevealValueMismatch, Operation: op,
+			Reason: fmt.Sprintf("commitment[%s] calculated from reveal value is not in operation chain", c)}
+	}
+
+	return &ChainFinding{Type: RejectedOperation, Operation: op,
+		Reason: fmt.Sprintf("operation for commitment[%s] has not been applied", c)}
+}
+
+func isAnchoredAfter(op, other *operation.AnchoredOperation) bool {
+	if op.TransactionTime != other.TransactionTime {
+		return op.TransactionTime > other.TransactionTime
+	}
+
+	return op.TransactionNumber > other.TransactionNumber
+}
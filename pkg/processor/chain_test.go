@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+func TestVerifyChain(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	pc := newMockProtocolClient()
+
+	t.Run("success - intact chain", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		nextUpdateKey := putUpdateOperation(t, store, updateKey, uniqueSuffix, 1, 1)
+		putUpdateOperation(t, store, nextUpdateKey, uniqueSuffix, 2, 2)
+
+		report, err := New("test", store, pc).VerifyChain(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, uniqueSuffix, report.UniqueSuffix)
+		require.Equal(t, 3, report.Operations)
+		require.Equal(t, 3, report.Applied)
+		require.Empty(t, report.Findings)
+	})
+
+	t.Run("success - forged reveal value is reported", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		putUpdateOperation(t, store, updateKey, uniqueSuffix, 1, 1)
+
+		forgedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		putUpdateOperation(t, store, forgedKey, uniqueSuffix, 2, 2)
+
+		report, err := New("test", store, pc).VerifyChain(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Applied)
+		require.Len(t, report.Findings, 1)
+		require.Equal(t, RevealValueMismatch, report.Findings[0].Type)
+		require.Equal(t, uint64(2), report.Findings[0].Operation.TransactionNumber)
+		require.Contains(t, report.Findings[0].Reason, "calculated from reveal value is not in operation chain")
+	})
+
+	t.Run("success - operation that has not been applied is reported", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		// two operations for the same commitment; only the first one is applied
+		putUpdateOperation(t, store, updateKey, uniqueSuffix, 1, 1)
+		putUpdateOperation(t, store, updateKey, uniqueSuffix, 2, 2)
+
+		report, err := New("test", store, pc).VerifyChain(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Applied)
+		require.Len(t, report.Findings, 1)
+		require.Equal(t, RejectedOperation, report.Findings[0].Type)
+		require.Equal(t, uint64(2), report.Findings[0].Operation.TransactionNumber)
+		require.Contains(t, report.Findings[0].Reason, "has not been applied")
+	})
+
+	t.Run("success - operation after deactivate is reported", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		deactivateOp, err := getDeactivateOperation(recoveryKey, uniqueSuffix)
+		require.NoError(t, err)
+
+		anchoredOp := getAnchoredOperation(deactivateOp, 1)
+		anchoredOp.TransactionNumber = 1
+		require.NoError(t, store.Put(anchoredOp))
+
+		putUpdateOperation(t, store, updateKey, uniqueSuffix, 2, 2)
+
+		report, err := New("test", store, pc).VerifyChain(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Applied)
+		require.Len(t, report.Findings, 1)
+		require.Equal(t, OperationAfterDeactivate, report.Findings[0].Type)
+		require.Equal(t, operation.TypeUpdate, report.Findings[0].Operation.Type)
+		require.Contains(t, report.Findings[0].Reason,
+			"operation is anchored after deactivation at transaction time[1] and number[1]")
+	})
+
+	t.Run("success - duplicate transaction number is reported", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		nextUpdateKey := putUpdateOperation(t, store, updateKey, uniqueSuffix, 1, 1)
+		putUpdateOperation(t, store, nextUpdateKey, uniqueSuffix, 2, 1)
+
+		report, err := New("test", store, pc).VerifyChain(uniqueSuffix)
+		require.NoError(t, err)
+		require.Len(t, report.Findings, 1)
+		require.Equal(t, DuplicateTransactionNumber, report.Findings[0].Type)
+		require.Equal(t, uint64(2), report.Findings[0].Operation.TransactionTime)
+		require.Contains(t, report.Findings[0].Reason, "transaction number[1] is already used by 'update' operation")
+	})
+
+	t.Run("error - store error", func(t *testing.T) {
+		report, err := New("test", mocks.NewMockOperationStore(errors.New("store error")), pc).VerifyChain("suffix")
+		require.Error(t, err)
+		require.Nil(t, report)
+		require.Contains(t, err.Error(), "store error")
+	})
+
+	t.Run("error - missing create operation", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
+
+		putUpdateOperation(t, store, updateKey, "suffix", 1, 1)
+
+		report, err := New("test", store, pc).VerifyChain("suffix")
+		require.Error(t, err)
+		require.Nil(t, report)
+		require.Contains(t, err.Error(), "missing create operation")
+	})
+}
+
+// putUpdateOperation stores update operation signed with the given key and returns next update key.
+func putUpdateOperation(t *testing.T, store *mocks.MockOperationStore, updateKey *ecdsa.PrivateKey,
+	uniqueSuffix string, transactionTime, transactionNumber uint64) *ecdsa.PrivateKey {
+	t.Helper()
+
+	updateOp, nextUpdateKey, err := getUpdateOperation(updateKey, uniqueSuffix, transactionTime)
+	require.NoError(t, err)
+
+	anchoredOp := getAnchoredOperation(updateOp, transactionTime)
+	anchoredOp.TransactionNumber = transactionNumber
+
+	require.NoError(t, store.Put(anchoredOp))
+
+	return nextUpdateKey
+}
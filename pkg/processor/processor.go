@@ -7,15 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package processor
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"sync"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
 )
 
 var logger = log.New("sidetree-core-processor")
@@ -23,9 +29,91 @@ var logger = log.New("sidetree-core-processor")
 // OperationProcessor will process document operations in chronological order and create final document during resolution.
 // It uses operation store client to retrieve all operations that are related to requested document.
 type OperationProcessor struct {
-	name  string
-	store OperationStoreClient
-	pc    protocol.Client
+	name      string
+	namespace string
+	store     OperationStoreClient
+	pc        protocol.Client
+	rules     []OperationRule
+	cache     *lru.Cache
+
+	maxOperations int
+	logger        Logger
+}
+
+// cachedDocument holds resolution model together with information about operations that were used to create it.
+type cachedDocument struct {
+	rm                    *protocol.ResolutionModel
+	opsNum                int
+	lastTransactionTime   uint64
+	lastTransactionNumber uint64
+}
+
+// OperationRule is a deployment specific validation rule that is applied (in addition to protocol rules)
+// before an operation is applied to the document. Operation is rejected if rule returns an error.
+type OperationRule interface {
+	// Validate validates operation against the current document state (empty state for create operation)
+	Validate(op *operation.AnchoredOperation, rm *protocol.ResolutionModel) error
+}
+
+// Logger is used by operation processor for logging (e.g. skipped operations).
+type Logger interface {
+	Debugf(msg string, args ...interface{})
+	Infof(msg string, args ...interface{})
+}
+
+// BatchResult holds resolution model (or resolution error) for one of the unique suffixes resolved in batch.
+type BatchResult struct {
+	ResolutionModel *protocol.ResolutionModel
+	Err             error
+}
+
+// Option is an operation processor option.
+type Option func(opts *OperationProcessor)
+
+// WithDocumentCache enables LRU cache of resolved documents (keyed by unique suffix) for up to size entries.
+// Operations are still retrieved from operation store on every resolution; cached document is returned
+// (without re-applying operations) only if operations haven't changed since the document was cached.
+// Any new operation for the unique suffix fully invalidates cached document and all operations are re-applied.
+func WithDocumentCache(size int) Option {
+	return func(opts *OperationProcessor) {
+		if size <= 0 {
+			opts.cache = nil
+
+			return
+		}
+
+		// error is returned for non-positive size only
+		opts.cache, _ = lru.New(size) //nolint:errcheck
+	}
+}
+
+// WithMaxOperations sets maximum number of operations (including create) that will be applied
+// when resolving a document. Resolution fails if document has more valid operations. Zero means no limit.
+func WithMaxOperations(n int) Option {
+	return func(opts *OperationProcessor) {
+		opts.maxOperations = n
+	}
+}
+
+// WithOperationRules sets additional operation validation rules.
+func WithOperationRules(rules ...OperationRule) Option {
+	return func(opts *OperationProcessor) {
+		opts.rules = append(opts.rules, rules...)
+	}
+}
+
+// WithNamespace sets DID namespace (e.g. "did:sidetree") that is required for resolving documents by DID.
+func WithNamespace(namespace string) Option {
+	return func(opts *OperationProcessor) {
+		opts.namespace = namespace
+	}
+}
+
+// WithLogger sets logger for operation processor (default logger is used if not set).
+func WithLogger(l Logger) Option {
+	return func(opts *OperationProcessor) {
+		opts.logger = l
+	}
 }
 
 // OperationStoreClient defines interface for retrieving all operations related to document.
@@ -35,56 +123,368 @@ type OperationStoreClient interface {
 }
 
 // New returns new operation processor with the given name. (Note that name is only used for logging.)
-func New(name string, store OperationStoreClient, pc protocol.Client) *OperationProcessor {
-	return &OperationProcessor{name: name, store: store, pc: pc}
+func New(name string, store OperationStoreClient, pc protocol.Client, opts ...Option) *OperationProcessor {
+	s := &OperationProcessor{name: name, store: store, pc: pc, logger: logger}
+
+	// apply options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Resolve document based on the given unique suffix.
 // Parameters:
 // uniqueSuffix - unique portion of ID to resolve. for example "abc123" in "did:sidetree:abc123".
 func (s *OperationProcessor) Resolve(uniqueSuffix string) (*protocol.ResolutionModel, error) {
+	return s.ResolveContext(context.Background(), uniqueSuffix)
+}
+
+// ResolveDID resolves document based on the given (short-form) DID, for example "did:sidetree:abc123".
+// DID has to start with namespace configured using WithNamespace option.
+func (s *OperationProcessor) ResolveDID(did string) (*protocol.ResolutionModel, error) {
+	if s.namespace == "" {
+		return nil, errors.New("namespace is not configured")
+	}
+
+	uniqueSuffix, err := docutil.GetUniqueSuffixFromID(s.namespace, did)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Resolve(uniqueSuffix)
+}
+
+// ResolveContext is the same as Resolve but it stops processing operations once the given context is done.
+func (s *OperationProcessor) ResolveContext(ctx context.Context, uniqueSuffix string) (*protocol.ResolutionModel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	ops, err := s.store.Get(uniqueSuffix)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.resolveOperations(ctx, uniqueSuffix, ops)
+}
+
+// ResolveBatch resolves documents for the given unique suffixes in parallel using up to maxWorkers goroutines
+// (one if maxWorkers is not positive). If operation store client implements BulkOperationStoreClient operations
+// for all unique suffixes are retrieved with a single call (unique suffixes that are missing from bulk result
+// are retrieved individually).
+// Returned map contains resolution result (or error) for each unique suffix; errors are isolated per unique suffix.
+func (s *OperationProcessor) ResolveBatch(uniqueSuffixes []string, maxWorkers int) map[string]*BatchResult {
+	results := make(map[string]*BatchResult)
+
+	var suffixes []string
+
+	for _, suffix := range uniqueSuffixes {
+		if _, ok := results[suffix]; ok {
+			// duplicate unique suffix
+			continue
+		}
+
+		results[suffix] = &BatchResult{}
+		suffixes = append(suffixes, suffix)
+	}
+
+	batchOps, batchErr := s.getBulkOperations(uniqueSuffixes)
+
+	if maxWorkers <= 0 {
+		maxWorkers = 1
+	}
+
+	if maxWorkers > len(suffixes) {
+		maxWorkers = len(suffixes)
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				s.resolveBatchResult(suffixes[i], batchOps, batchErr, results[suffixes[i]])
+			}
+		}()
+	}
+
+	for i := range suffixes {
+		indexes <- i
+	}
+
+	close(indexes)
+
+	wg.Wait()
+
+	return results
+}
+
+func (s *OperationProcessor) resolveBatchResult(uniqueSuffix string, batchOps map[string][]*operation.AnchoredOperation,
+	batchErr error, result *BatchResult) {
+	if batchErr != nil {
+		result.Err = batchErr
+
+		return
+	}
+
+	if ops, ok := batchOps[uniqueSuffix]; ok {
+		result.ResolutionModel, result.Err = s.resolveOperations(context.Background(), uniqueSuffix, ops)
+
+		return
+	}
+
+	result.ResolutionModel, result.Err = s.Resolve(uniqueSuffix)
+}
+
+// getBulkOperations retrieves operations for all unique suffixes if operation store client supports it;
+// otherwise operations are retrieved for each unique suffix during resolution so that errors are isolated.
+func (s *OperationProcessor) getBulkOperations(uniqueSuffixes []string) (map[string][]*operation.AnchoredOperation, error) {
+	bulkStore, ok := s.store.(BulkOperationStoreClient)
+	if !ok {
+		return nil, nil
+	}
+
+	ops, err := bulkStore.GetBulk(uniqueSuffixes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve operations for batch: %s", err.Error())
+	}
+
+	return ops, nil
+}
+
+// pre-condition: operations have been retrieved from operation store.
+func (s *OperationProcessor) resolveOperations(ctx context.Context, uniqueSuffix string, ops []*operation.AnchoredOperation) (*protocol.ResolutionModel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	sortOperations(ops)
 
-	logger.Debugf("[%s] Found %d operations for unique suffix [%s]: %+v", s.name, len(ops), uniqueSuffix, ops)
+	s.logger.Debugf("[%s] Found %d operations for unique suffix [%s]: %+v", s.name, len(ops), uniqueSuffix, ops)
+
+	if rm, ok := s.getCachedDocument(uniqueSuffix, ops); ok {
+		s.logger.Debugf("[%s] Retrieved document for unique suffix [%s] from cache", s.name, uniqueSuffix)
 
-	rm := &protocol.ResolutionModel{}
+		return rm, nil
+	}
 
+	rm, err := s.resolve(ctx, uniqueSuffix, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheDocument(uniqueSuffix, ops, rm)
+
+	return rm, nil
+}
+
+// ResolveAt resolves document based on the given unique suffix as of the given transaction time and number;
+// only operations anchored at or before that transaction are applied.
+func (s *OperationProcessor) ResolveAt(uniqueSuffix string, transactionTime, transactionNumber uint64) (*protocol.ResolutionModel, error) {
+	ops, err := s.store.Get(uniqueSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	sortOperations(ops)
+
+	ops = getOpsWithTxnLessThanOrEqualTo(ops, transactionTime, transactionNumber)
+
+	s.logger.Debugf("[%s] Found %d operations for unique suffix [%s] at transaction time[%d] and number[%d]: %+v",
+		s.name, len(ops), uniqueSuffix, transactionTime, transactionNumber, ops)
+
+	// resolved document is not cached since it doesn't reflect the latest state of the document
+	return s.resolve(context.Background(), uniqueSuffix, ops)
+}
+
+// getCachedDocument returns cached resolution model if it has been created from the same (sorted) operations;
+// otherwise cached resolution model is removed (full invalidation).
+func (s *OperationProcessor) getCachedDocument(uniqueSuffix string, ops []*operation.AnchoredOperation) (*protocol.ResolutionModel, bool) {
+	if s.cache == nil || len(ops) == 0 {
+		return nil, false
+	}
+
+	value, ok := s.cache.Get(uniqueSuffix)
+	if !ok {
+		return nil, false
+	}
+
+	cached, ok := value.(*cachedDocument)
+	if !ok {
+		return nil, false
+	}
+
+	last := ops[len(ops)-1]
+
+	if cached.opsNum != len(ops) || cached.lastTransactionTime != last.TransactionTime ||
+		cached.lastTransactionNumber != last.TransactionNumber {
+		// new operations have arrived
+		s.cache.Remove(uniqueSuffix)
+
+		return nil, false
+	}
+
+	// callers may modify returned document (e.g. document transformer sets document ID)
+	return copyResolutionModel(cached.rm), true
+}
+
+func (s *OperationProcessor) cacheDocument(uniqueSuffix string, ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel) {
+	if s.cache == nil || len(ops) == 0 {
+		return
+	}
+
+	last := ops[len(ops)-1]
+
+	s.cache.Add(uniqueSuffix, &cachedDocument{
+		rm:                    copyResolutionModel(rm),
+		opsNum:                len(ops),
+		lastTransactionTime:   last.TransactionTime,
+		lastTransactionNumber: last.TransactionNumber,
+	})
+}
+
+// copyResolutionModel returns deep copy of the given resolution model so that cached resolution model
+// doesn't share document (or any other map or slice) with resolution models returned to callers.
+func copyResolutionModel(rm *protocol.ResolutionModel) *protocol.ResolutionModel {
+	result := *rm
+
+	if rm.Doc != nil {
+		result.Doc = deepCopy(reflect.ValueOf(rm.Doc)).Interface().(document.Document)
+	}
+
+	if rm.AnchorOrigin != nil {
+		result.AnchorOrigin = deepCopy(reflect.ValueOf(rm.AnchorOrigin)).Interface()
+	}
+
+	if rm.EquivalentReferences != nil {
+		result.EquivalentReferences = append([]string{}, rm.EquivalentReferences...)
+	}
+
+	return &result
+}
+
+// deepCopy returns deep copy of maps and slices (other values are returned as is).
+func deepCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.New(value.Type()).Elem()
+		result.Set(deepCopy(value.Elem()))
+
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(deepCopy(value.Index(i)))
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+// pre-condition: operations have to be sorted.
+func (s *OperationProcessor) resolve(ctx context.Context, uniqueSuffix string, ops []*operation.AnchoredOperation) (*protocol.ResolutionModel, error) {
+	rm, _, err := s.replay(ctx, uniqueSuffix, ops)
+
+	return rm, err
+}
+
+// replay applies valid operations and returns resolution model together with operation counter
+// that holds applied operations (in the order they have been applied).
+// pre-condition: operations have to be sorted.
+func (s *OperationProcessor) replay(ctx context.Context, uniqueSuffix string, ops []*operation.AnchoredOperation) (*protocol.ResolutionModel, *operationCounter, error) {
 	// split operations into 'create', 'update' and 'full' operations
 	createOps, updateOps, fullOps := splitOperations(ops)
 	if len(createOps) == 0 {
-		return nil, errors.New("missing create operation")
+		return nil, nil, errors.New("missing create operation")
 	}
 
+	var err error
+
 	// apply 'create' operations first
-	rm = s.applyFirstValidCreateOperation(createOps, rm)
+	rm, createOp := s.applyFirstValidCreateOperation(createOps, &protocol.ResolutionModel{})
 	if rm == nil {
-		return nil, errors.New("valid create operation not found")
+		return nil, nil, errors.New("valid create operation not found")
+	}
+
+	createdTime := rm.LastOperationTransactionTime
+
+	counter := &operationCounter{max: s.maxOperations}
+
+	err = counter.increment(createOp, rm)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// apply 'full' operations first
 	if len(fullOps) > 0 {
-		logger.Debugf("[%s] Applying %d full operations for unique suffix [%s]", s.name, len(fullOps), uniqueSuffix)
+		s.logger.Debugf("[%s] Applying %d full operations for unique suffix [%s]", s.name, len(fullOps), uniqueSuffix)
+
+		rm, err = s.applyOperations(ctx, fullOps, rm, getRecoveryCommitment, counter)
+		if err != nil {
+			return nil, nil, err
+		}
 
-		rm = s.applyOperations(fullOps, rm, getRecoveryCommitment)
 		if rm.Deactivated {
 			// document was deactivated, stop processing
-			return rm, nil
+			return setDocumentTimes(rm, createdTime, counter), counter, nil
 		}
 	}
 
 	// next apply update ops since last 'full' transaction
 	filteredUpdateOps := getOpsWithTxnGreaterThan(updateOps, rm.LastOperationTransactionTime, rm.LastOperationTransactionNumber)
 	if len(filteredUpdateOps) > 0 {
-		logger.Debugf("[%s] Applying %d update operations after last full operation for unique suffix [%s]", s.name, len(filteredUpdateOps), uniqueSuffix)
-		rm = s.applyOperations(filteredUpdateOps, rm, getUpdateCommitment)
+		s.logger.Debugf("[%s] Applying %d update operations after last full operation for unique suffix [%s]", s.name, len(filteredUpdateOps), uniqueSuffix)
+		rm, err = s.applyOperations(ctx, filteredUpdateOps, rm, getUpdateCommitment, counter)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return rm, nil
+	return setDocumentTimes(rm, createdTime, counter), counter, nil
+}
+
+// setDocumentTimes sets transaction time of the create operation and (if any operation has been applied
+// after create operation) transaction time of the last applied operation.
+func setDocumentTimes(rm *protocol.ResolutionModel, createdTime uint64, counter *operationCounter) *protocol.ResolutionModel {
+	rm.CreatedTime = createdTime
+
+	if len(counter.applied) > 1 {
+		rm.UpdatedTime = rm.LastOperationTransactionTime
+	}
+
+	return rm
 }
 
 func (s *OperationProcessor) createOperationHashMap(ops []*operation.AnchoredOperation) map[string][]*operation.AnchoredOperation {
@@ -93,14 +493,14 @@ func (s *OperationProcessor) createOperationHashMap(ops []*operation.AnchoredOpe
 	for _, op := range ops {
 		rv, err := s.getRevealValue(op)
 		if err != nil {
-			logger.Infof("[%s] Skipped bad operation while creating operation hash map {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
+			s.logger.Infof("[%s] Skipped bad operation while creating operation hash map {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
 			continue
 		}
 
 		c, err := commitment.GetCommitmentFromRevealValue(rv)
 		if err != nil {
-			logger.Infof("[%s] Skipped calculating commitment while creating operation hash map {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
+			s.logger.Infof("[%s] Skipped calculating commitment while creating operation hash map {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
 			continue
 		}
@@ -147,7 +547,22 @@ func getOpsWithTxnGreaterThan(ops []*operation.AnchoredOperation, txnTime, txnNu
 	return nil
 }
 
-func (s *OperationProcessor) applyOperations(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, commitmentFnc fnc) *protocol.ResolutionModel {
+// pre-condition: operations have to be sorted.
+func getOpsWithTxnLessThanOrEqualTo(ops []*operation.AnchoredOperation, txnTime, txnNumber uint64) []*operation.AnchoredOperation {
+	for index, op := range ops {
+		if op.TransactionTime > txnTime {
+			return ops[:index]
+		}
+
+		if op.TransactionTime == txnTime && op.TransactionNumber > txnNumber {
+			return ops[:index]
+		}
+	}
+
+	return ops
+}
+
+func (s *OperationProcessor) applyOperations(ctx context.Context, ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, commitmentFnc fnc, counter *operationCounter) (*protocol.ResolutionModel, error) {
 	// suffix for logging
 	uniqueSuffix := ops[0].UniqueSuffix
 
@@ -159,49 +574,79 @@ func (s *OperationProcessor) applyOperations(ops []*operation.AnchoredOperation,
 	commitmentMap := make(map[string]bool)
 
 	c := commitmentFnc(state)
-	logger.Debugf("[%s] Processing commitment '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
+	s.logger.Debugf("[%s] Processing commitment '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
 
 	commitmentOps, ok := opMap[c]
 	for ok {
-		logger.Debugf("[%s] Found %d operation(s) for commitment '%s' {UniqueSuffix: %s}", s.name, len(commitmentOps), c, uniqueSuffix)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-		newState := s.applyFirstValidOperation(commitmentOps, state, c, commitmentMap)
+		s.logger.Debugf("[%s] Found %d operation(s) for commitment '%s' {UniqueSuffix: %s}", s.name, len(commitmentOps), c, uniqueSuffix)
+
+		newState, op := s.applyFirstValidOperation(commitmentOps, state, c, commitmentMap)
 
 		// can't find a valid operation to apply
 		if newState == nil {
-			logger.Infof("[%s] Unable to apply valid operation for commitment '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
+			s.logger.Infof("[%s] Unable to apply valid operation for commitment '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
 
 			break
 		}
 
+		if err := counter.increment(op, newState); err != nil {
+			return nil, err
+		}
+
 		// commitment has been processed successfully
 		commitmentMap[c] = true
 		state = newState
 
-		logger.Debugf("[%s] Successfully processed commitment '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
+		s.logger.Debugf("[%s] Successfully processed commitment '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
 
 		// get next commitment to be processed
 		c = commitmentFnc(state)
 
-		logger.Debugf("[%s] Next commitment to process is '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
+		s.logger.Debugf("[%s] Next commitment to process is '%s' {UniqueSuffix: %s}", s.name, c, uniqueSuffix)
 
 		// stop if there is no next commitment
 		if c == "" {
-			return state
+			return state, nil
 		}
 
 		commitmentOps, ok = opMap[c]
 	}
 
 	if len(commitmentMap) != len(ops) {
-		logger.Infof("[%s] Number of commitments applied '%d' doesn't match number of operations '%d' {UniqueSuffix: %s}", s.name, len(commitmentMap), len(ops), uniqueSuffix)
+		s.logger.Infof("[%s] Number of commitments applied '%d' doesn't match number of operations '%d' {UniqueSuffix: %s}", s.name, len(commitmentMap), len(ops), uniqueSuffix)
 	}
 
-	return state
+	return state, nil
 }
 
 type fnc func(rm *protocol.ResolutionModel) string
 
+// operationCounter records applied operations and enforces maximum number of operations (if set).
+type operationCounter struct {
+	max     int
+	applied []*appliedOperation
+}
+
+// appliedOperation holds applied operation together with document state after operation has been applied.
+type appliedOperation struct {
+	op    *operation.AnchoredOperation
+	state *protocol.ResolutionModel
+}
+
+func (c *operationCounter) increment(op *operation.AnchoredOperation, state *protocol.ResolutionModel) error {
+	c.applied = append(c.applied, &appliedOperation{op: op, state: state})
+
+	if c.max > 0 && len(c.applied) > c.max {
+		return fmt.Errorf("maximum number of operations[%d] exceeded for unique suffix[%s]", c.max, op.UniqueSuffix)
+	}
+
+	return nil
+}
+
 func getUpdateCommitment(rm *protocol.ResolutionModel) string {
 	return rm.UpdateCommitment
 }
@@ -210,40 +655,43 @@ func getRecoveryCommitment(rm *protocol.ResolutionModel) string {
 	return rm.RecoveryCommitment
 }
 
-func (s *OperationProcessor) applyFirstValidCreateOperation(createOps []*operation.AnchoredOperation, rm *protocol.ResolutionModel) *protocol.ResolutionModel {
+func (s *OperationProcessor) applyFirstValidCreateOperation(createOps []*operation.AnchoredOperation, rm *protocol.ResolutionModel) (*protocol.ResolutionModel, *operation.AnchoredOperation) {
 	for _, op := range createOps {
 		var state *protocol.ResolutionModel
 		var err error
 
 		if state, err = s.applyOperation(op, rm); err != nil {
-			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
+			s.logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
 			continue
 		}
 
-		logger.Debugf("[%s] After applying create op %+v, recover commitment[%s], update commitment[%s], New doc: %s", s.name, op, state.RecoveryCommitment, state.UpdateCommitment, state.Doc)
+		s.logger.Debugf("[%s] After applying create op %+v, recover commitment[%s], update commitment[%s], New doc: %s", s.name, op, state.RecoveryCommitment, state.UpdateCommitment, state.Doc)
 
-		return state
+		return state, op
 	}
 
-	return nil
+	return nil, nil
 }
 
 // this function should be used for update, recover and deactivate operations (create is handled differently).
-func (s *OperationProcessor) applyFirstValidOperation(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, currCommitment string, processedCommitments map[string]bool) *protocol.ResolutionModel {
+// Reveal value chaining is enforced by the caller: ops are looked up in operation hash map by current commitment
+// and the map is keyed by the commitment computed from operation reveal value (see createOperationHashMap),
+// so reveal value of each of the given operations hashes to currCommitment.
+func (s *OperationProcessor) applyFirstValidOperation(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel, currCommitment string, processedCommitments map[string]bool) (*protocol.ResolutionModel, *operation.AnchoredOperation) {
 	for _, op := range ops {
 		var state *protocol.ResolutionModel
 		var err error
 
 		nextCommitment, err := s.getCommitment(op)
 		if err != nil {
-			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
+			s.logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
 			continue
 		}
 
 		if currCommitment == nextCommitment {
-			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: operation commitment(key) equals next operation commitment(key)", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber)
+			s.logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: operation commitment(key) equals next operation commitment(key)", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber)
 
 			continue
 		}
@@ -252,27 +700,33 @@ func (s *OperationProcessor) applyFirstValidOperation(ops []*operation.AnchoredO
 			// for recovery and update operations check if next commitment has been used already; if so skip to next operation
 			_, processed := processedCommitments[nextCommitment]
 			if processed {
-				logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: next operation commitment(key) has already been used", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber)
+				s.logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: next operation commitment(key) has already been used", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber)
 
 				continue
 			}
 		}
 
 		if state, err = s.applyOperation(op, rm); err != nil {
-			logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
+			s.logger.Infof("[%s] Skipped bad operation {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", s.name, op.UniqueSuffix, op.Type, op.TransactionTime, op.TransactionNumber, err)
 
 			continue
 		}
 
-		logger.Debugf("[%s] After applying op %+v, recover commitment[%s], update commitment[%s], New doc: %s", s.name, op, state.RecoveryCommitment, state.UpdateCommitment, state.Doc)
+		s.logger.Debugf("[%s] After applying op %+v, recover commitment[%s], update commitment[%s], New doc: %s", s.name, op, state.RecoveryCommitment, state.UpdateCommitment, state.Doc)
 
-		return state
+		return state, op
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (s *OperationProcessor) applyOperation(op *operation.AnchoredOperation, rm *protocol.ResolutionModel) (*protocol.ResolutionModel, error) {
+	for _, rule := range s.rules {
+		if err := rule.Validate(op, rm); err != nil {
+			return nil, fmt.Errorf("validate '%s' operation: %s", op.Type, err.Error())
+		}
+	}
+
 	p, err := s.pc.Get(op.ProtocolGenesisTime)
 	if err != nil {
 		return nil, fmt.Errorf("apply '%s' operation: %s", op.Type, err.Error())
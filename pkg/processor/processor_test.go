@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package processor
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -14,7 +15,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -31,6 +35,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationapplier"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
@@ -119,6 +124,46 @@ func TestResolve(t *testing.T) {
 	})
 }
 
+func TestResolveDID(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+	pc := newMockProtocolClient()
+
+	t.Run("success", func(t *testing.T) {
+		p := New("test", store, pc, WithNamespace(mocks.DefaultNS))
+
+		rm, err := p.ResolveDID(mocks.DefaultNS + ":" + uniqueSuffix)
+		require.NoError(t, err)
+		require.NotNil(t, rm)
+
+		expected, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, expected.Doc, rm.Doc)
+	})
+
+	t.Run("error - namespace mismatch", func(t *testing.T) {
+		p := New("test", store, pc, WithNamespace(mocks.DefaultNS))
+
+		rm, err := p.ResolveDID("did:other:" + uniqueSuffix)
+		require.Error(t, err)
+		require.Nil(t, rm)
+		require.Contains(t, err.Error(), "doesn't match namespace [did:sidetree]")
+	})
+
+	t.Run("error - namespace is not configured", func(t *testing.T) {
+		rm, err := New("test", store, pc).ResolveDID(mocks.DefaultNS + ":" + uniqueSuffix)
+		require.Error(t, err)
+		require.Nil(t, rm)
+		require.Contains(t, err.Error(), "namespace is not configured")
+	})
+}
+
 func TestUpdateDocument(t *testing.T) {
 	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	require.NoError(t, e)
@@ -209,132 +254,841 @@ func TestUpdateDocument(t *testing.T) {
 		pubJWK, err := pubkey.GetPublicKeyJWK(&nextUpdateKey.PublicKey)
 		require.NoError(t, err)
 
-		// previous operation commit value was calculated with protocol value at block 50
-		rv, err := commitment.GetRevealValue(pubJWK, getProtocol(50).MultihashAlgorithms[0])
+		// previous operation commit value was calculated with protocol value at block 50
+		rv, err := commitment.GetRevealValue(pubJWK, getProtocol(50).MultihashAlgorithms[0])
+		require.NoError(t, err)
+
+		// protocol value for hashing algorithm changed at block 100
+		op, nextUpdateKey, err := getUpdateOperation(nextUpdateKey, uniqueSuffix, 500)
+		require.NoError(t, err)
+
+		op.RevealValue = rv
+
+		updateOp = getAnchoredOperation(op, 500)
+		err = store.Put(updateOp)
+		require.Nil(t, err)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.Nil(t, err)
+
+		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special500", didDoc["test"])
+
+		// test consecutive update within new protocol value
+		updateOp, nextUpdateKey, err = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, 700)
+		require.Nil(t, err)
+		err = store.Put(updateOp)
+		require.Nil(t, err)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.Nil(t, err)
+
+		// check if service type value is updated again (done via json patch)
+		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special700", didDoc["test"])
+	})
+
+	t.Run("success -  operation with reused next commitment ignored", func(t *testing.T) {
+		// scenario: update 1 followed by update 2 followed by update 3 with reused commitment from 1
+
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, nextUpdateKey, err := getUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.Nil(t, err)
+
+		delta1 := updateOp.Delta
+
+		err = store.Put(getAnchoredOperation(updateOp, 1))
+		require.Nil(t, err)
+
+		p := New("test", store, pc)
+		result, err := p.Resolve(uniqueSuffix)
+		require.Nil(t, err)
+
+		// check if service type value is updated (done via json patch)
+		didDoc := document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special1", didDoc["test"])
+
+		// test consecutive update
+		updateOp, nextUpdateKey, err = getUpdateOperation(nextUpdateKey, uniqueSuffix, 2)
+		require.Nil(t, err)
+
+		err = store.Put(getAnchoredOperation(updateOp, 2))
+		require.Nil(t, err)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.Nil(t, err)
+
+		// service type value is updated since operation is valid
+		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special2", didDoc["test"])
+
+		// two successful update operations - next update with reused commitment from op 1
+		updateOp, nextUpdateKey, err = getUpdateOperation(nextUpdateKey, uniqueSuffix, 1)
+		require.Nil(t, err)
+
+		delta3 := updateOp.Delta
+		delta3.UpdateCommitment = delta1.UpdateCommitment
+
+		err = store.Put(getAnchoredOperation(updateOp, 1))
+		require.Nil(t, err)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.Nil(t, err)
+
+		// service type value is not updated since commitment value was reused
+		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special2", didDoc["test"])
+	})
+
+	t.Run("success - operation with same commitment as next operation commitment is ignored", func(t *testing.T) {
+		// scenario: update 1 followed by update 2 with same operation commitment as next operation commitment
+
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, nextUpdateKey, err := getUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.Nil(t, err)
+
+		delta1 := updateOp.Delta
+		require.NoError(t, err)
+
+		err = store.Put(getAnchoredOperation(updateOp, 1))
+		require.Nil(t, err)
+
+		p := New("test", store, pc)
+		result, err := p.Resolve(uniqueSuffix)
+		require.Nil(t, err)
+
+		// check if service type value is updated (done via json patch)
+		didDoc := document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special1", didDoc["test"])
+
+		// update operation commitment is the same as next operation commitment
+		updateOp, nextUpdateKey, err = getUpdateOperation(nextUpdateKey, uniqueSuffix, 1)
+		require.Nil(t, err)
+
+		delta2 := updateOp.Delta
+		delta2.UpdateCommitment = delta1.UpdateCommitment
+
+		err = store.Put(getAnchoredOperation(updateOp, 1))
+		require.Nil(t, err)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.Nil(t, err)
+
+		// service type value is not updated since commitment value was reused
+		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special1", didDoc["test"])
+	})
+}
+
+func TestRevealValueChaining(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	pc := newMockProtocolClient()
+
+	t.Run("success - chain of updates is applied", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, nextUpdateKey, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		updateOp, _, err = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, 2)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		p := New("test", store, pc)
+
+		rm, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		didDoc := document.DidDocumentFromJSONLDObject(rm.Doc)
+		require.Equal(t, "special2", didDoc["test"])
+		require.Equal(t, updateOp.TransactionTime, rm.LastOperationTransactionTime)
+	})
+
+	t.Run("error - operation with forged reveal value is not applied", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		p := New("test", store, pc)
+
+		expected, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		forgedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		forgedOp, _, err := getAnchoredUpdateOperation(forgedKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(forgedOp))
+
+		rm, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		// resolution result is the state before forged operation was stored
+		require.Equal(t, expected, rm)
+		require.Zero(t, rm.UpdatedTime)
+		require.NotEqual(t, forgedOp.TransactionTime, rm.LastOperationTransactionTime)
+	})
+
+	t.Run("error - operation with forged reveal value in the chain of updates is not applied", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, nextUpdateKey, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		forgedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		forgedOp, _, err := getAnchoredUpdateOperation(forgedKey, uniqueSuffix, 2)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(forgedOp))
+
+		updateOp, _, err = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, 3)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		l := &capturingLogger{}
+
+		rm, err := New("test", store, pc, WithLogger(l)).Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		didDoc := document.DidDocumentFromJSONLDObject(rm.Doc)
+		require.Equal(t, "special3", didDoc["test"])
+		require.Equal(t, updateOp.TransactionTime, rm.LastOperationTransactionTime)
+
+		// forged operation is not among applied operations since its reveal value doesn't hash
+		// to any of the commitments in the chain
+		applied := appliedTransactionTimes(l.debugMessages())
+		require.Equal(t, []uint64{1, updateOp.TransactionTime}, applied)
+		require.NotContains(t, applied, forgedOp.TransactionTime)
+	})
+}
+
+func TestDocumentComposer(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	t.Run("success - custom document composer is used for resolution", func(t *testing.T) {
+		pc := newMockProtocolClient()
+
+		dc := &fieldComposer{field: "composed"}
+
+		for _, v := range pc.Versions {
+			v.OperationApplierReturns(operationapplier.New(v.Protocol(), operationparser.New(v.Protocol()), dc))
+			v.DocumentComposerReturns(dc)
+		}
+
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, _, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		rm, err := New("test", store, pc).Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		didDoc := document.DidDocumentFromJSONLDObject(rm.Doc)
+		require.Equal(t, "special1", didDoc["test"])
+		require.Equal(t, true, didDoc["composed"])
+	})
+}
+
+func TestOperationRules(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	pc := newMockProtocolClient()
+
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+	updateOp, _, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+	require.NoError(t, err)
+
+	err = store.Put(updateOp)
+	require.NoError(t, err)
+
+	t.Run("success - operation that passes rules is applied", func(t *testing.T) {
+		rule := &maxUpdateSizeRule{maxSize: len(updateOp.OperationBuffer)}
+
+		p := New("test", store, pc, WithOperationRules(rule))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		didDoc := document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Equal(t, "special1", didDoc["test"])
+
+		// rules are invoked for create and update operation
+		require.Equal(t, 2, rule.calls)
+	})
+
+	t.Run("success - oversized update is rejected", func(t *testing.T) {
+		p := New("test", store, pc, WithOperationRules(&maxUpdateSizeRule{maxSize: len(updateOp.OperationBuffer) - 1}))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		// create operation has been applied but update operation has been rejected
+		didDoc := document.DidDocumentFromJSONLDObject(result.Doc)
+		require.Nil(t, didDoc["test"])
+		require.NotEmpty(t, result.UpdateCommitment)
+	})
+
+	t.Run("error - create operation rejected by rule", func(t *testing.T) {
+		p := New("test", store, pc, WithOperationRules(&maxUpdateSizeRule{}, &rejectCreateRule{}))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "valid create operation not found")
+	})
+}
+
+func TestDocumentCache(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	t.Run("success - operations are not re-applied (protocol client is not consulted) if there are no new operations", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, nextUpdateKey, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		pc := &countingProtocolClient{Client: newMockProtocolClient()}
+
+		p := New("test", store, pc, WithDocumentCache(10))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special1", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+
+		calls := pc.calls
+		require.True(t, calls > 0)
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special1", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+		require.Equal(t, calls, pc.calls)
+
+		// cached document is fully invalidated when new operation arrives: all operations
+		// (create and both updates) are re-applied, not only the new one
+		updateOp, _, err = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, 2)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		result, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special2", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+		require.True(t, pc.calls-calls > calls)
+	})
+
+	t.Run("success - modifying returned document doesn't modify cached document", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		updateOp, _, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		pc := &countingProtocolClient{Client: newMockProtocolClient()}
+
+		p := New("test", store, pc, WithDocumentCache(10))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		expected, err := json.Marshal(result.Doc)
+		require.NoError(t, err)
+
+		calls := pc.calls
+
+		for i := 0; i < 2; i++ {
+			result, err = p.Resolve(uniqueSuffix)
+			require.NoError(t, err)
+			require.Equal(t, calls, pc.calls)
+
+			docBytes, err := json.Marshal(result.Doc)
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(docBytes))
+
+			// modify top-level and nested document properties (e.g. document transformer sets document ID)
+			result.Doc[document.IDProperty] = "did:other:" + uniqueSuffix
+			result.Doc["test"] = "modified"
+
+			for _, value := range result.Doc {
+				if values, ok := value.([]interface{}); ok && len(values) > 0 {
+					if m, ok := values[0].(map[string]interface{}); ok {
+						m["id"] = "modified"
+					}
+
+					values[0] = "modified"
+				}
+			}
+		}
+	})
+
+	t.Run("success - concurrent resolutions modify returned documents", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		p := New("test", store, newMockProtocolClient(), WithDocumentCache(10))
+
+		_, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				result, err := p.Resolve(uniqueSuffix)
+				if err != nil {
+					return
+				}
+
+				result.Doc[document.IDProperty] = fmt.Sprintf("did:%d:%s", i, uniqueSuffix)
+			}(i)
+		}
+
+		wg.Wait()
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Empty(t, result.Doc[document.IDProperty])
+	})
+
+	t.Run("success - cache disabled", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		pc := &countingProtocolClient{Client: newMockProtocolClient()}
+
+		p := New("test", store, pc, WithDocumentCache(10), WithDocumentCache(0))
+
+		_, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+
+		calls := pc.calls
+
+		_, err = p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, 2*calls, pc.calls)
+	})
+
+	t.Run("error - resolution errors are not cached", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
+
+		p := New("test", store, newMockProtocolClient(), WithDocumentCache(10))
+
+		_, err := p.Resolve("suffix")
+		require.Error(t, err)
+
+		_, err = p.Resolve("suffix")
+		require.Error(t, err)
+	})
+}
+
+func TestResolveContext(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+	updateOp, nextUpdateKey, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 1)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(updateOp))
+
+	updateOp, _, err = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, 2)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(updateOp))
+
+	t.Run("success", func(t *testing.T) {
+		p := New("test", store, newMockProtocolClient())
+
+		result, err := p.ResolveContext(context.Background(), uniqueSuffix)
+		require.NoError(t, err)
+		require.Equal(t, "special2", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+	})
+
+	t.Run("error - context cancelled before store get", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := New("test", store, newMockProtocolClient())
+
+		result, err := p.ResolveContext(ctx, uniqueSuffix)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Nil(t, result)
+	})
+
+	t.Run("error - context cancelled while store get is blocked", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		p := New("test", &blockingOperationStore{OperationStoreClient: store, ctx: ctx}, newMockProtocolClient())
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		result, err := p.ResolveContext(ctx, uniqueSuffix)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Nil(t, result)
+	})
+
+	t.Run("error - context cancelled during operation processing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// cancel once the first update operation is being processed
+		pc := &countingProtocolClient{Client: newMockProtocolClient(), onGet: func(calls int) {
+			if calls == 4 {
+				cancel()
+			}
+		}}
+
+		p := New("test", store, pc)
+
+		result, err := p.ResolveContext(ctx, uniqueSuffix)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Nil(t, result)
+	})
+}
+
+func TestResolveBatch(t *testing.T) {
+	const numDocs = 3
+
+	store := mocks.NewMockOperationStore(nil)
+
+	var suffixes []string
+
+	for i := 0; i < numDocs; i++ {
+		recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		createOp, err := getAnchoredCreateOperation(recoveryKey, updateKey)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(createOp))
+
+		updateOp, _, err := getAnchoredUpdateOperation(updateKey, createOp.UniqueSuffix, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(updateOp))
+
+		suffixes = append(suffixes, createOp.UniqueSuffix)
+	}
+
+	pc := newMockProtocolClient()
+
+	t.Run("success - all documents are resolved", func(t *testing.T) {
+		results := New("test", store, pc).ResolveBatch(suffixes, numDocs)
+		require.Len(t, results, numDocs)
+
+		for _, suffix := range suffixes {
+			require.NoError(t, results[suffix].Err)
+			require.NotNil(t, results[suffix].ResolutionModel)
+
+			didDoc := document.DidDocumentFromJSONLDObject(results[suffix].ResolutionModel.Doc)
+			require.Equal(t, "special1", didDoc["test"])
+		}
+	})
+
+	t.Run("success - operations are retrieved with single call from bulk store", func(t *testing.T) {
+		bulkStore := &bulkOperationStore{MockOperationStore: store}
+
+		results := New("test", bulkStore, pc).ResolveBatch(suffixes, numDocs)
+		require.Len(t, results, numDocs)
+
+		for _, suffix := range suffixes {
+			require.NoError(t, results[suffix].Err)
+			require.NotNil(t, results[suffix].ResolutionModel)
+		}
+
+		require.Equal(t, 1, bulkStore.bulkCalls)
+	})
+
+	t.Run("success - duplicate suffixes are resolved once", func(t *testing.T) {
+		results := New("test", store, pc).ResolveBatch([]string{suffixes[0], suffixes[0]}, numDocs)
+		require.Len(t, results, 1)
+		require.NoError(t, results[suffixes[0]].Err)
+	})
+
+	t.Run("success - number of concurrent resolutions is limited by max workers", func(t *testing.T) {
+		for maxWorkers, expected := range map[int]int{0: 1, 2: 2, 10: numDocs} {
+			trackingStore := &concurrencyTrackingStore{MockOperationStore: store}
+
+			results := New("test", trackingStore, pc).ResolveBatch(suffixes, maxWorkers)
+			require.Len(t, results, numDocs)
+
+			for _, suffix := range suffixes {
+				require.NoError(t, results[suffix].Err)
+			}
+
+			require.LessOrEqual(t, trackingStore.maxActive, expected, maxWorkers)
+		}
+	})
+
+	t.Run("error - errors are isolated per suffix", func(t *testing.T) {
+		results := New("test", store, pc).ResolveBatch(append([]string{"unknown"}, suffixes...), numDocs)
+		require.Len(t, results, numDocs+1)
+
+		require.Error(t, results["unknown"].Err)
+		require.Nil(t, results["unknown"].ResolutionModel)
+		require.Contains(t, results["unknown"].Err.Error(), "uniqueSuffix not found in the store")
+
+		for _, suffix := range suffixes {
+			require.NoError(t, results[suffix].Err)
+			require.NotNil(t, results[suffix].ResolutionModel)
+		}
+	})
+
+	t.Run("error - bulk store error", func(t *testing.T) {
+		bulkStore := &bulkOperationStore{MockOperationStore: store, err: errors.New("bulk error")}
+
+		results := New("test", bulkStore, pc).ResolveBatch(suffixes, numDocs)
+		require.Len(t, results, numDocs)
+
+		for _, suffix := range suffixes {
+			require.Error(t, results[suffix].Err)
+			require.Nil(t, results[suffix].ResolutionModel)
+			require.Contains(t, results[suffix].Err.Error(), "failed to retrieve operations for batch: bulk error")
+		}
+	})
+}
+
+func TestMaxOperations(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	pc := newMockProtocolClient()
+
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+	nextUpdateKey := updateKey
+
+	for i := uint64(1); i <= 3; i++ {
+		var updateOp *operation.AnchoredOperation
+
+		updateOp, nextUpdateKey, e = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, i)
+		require.NoError(t, e)
+		require.NoError(t, store.Put(updateOp))
+	}
+
+	t.Run("success - number of operations within limit", func(t *testing.T) {
+		for _, max := range []int{0, 4, 10} {
+			p := New("test", store, pc, WithMaxOperations(max))
+
+			result, err := p.Resolve(uniqueSuffix)
+			require.NoError(t, err)
+			require.Equal(t, "special3", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+		}
+	})
+
+	t.Run("error - maximum number of operations exceeded", func(t *testing.T) {
+		p := New("test", store, pc, WithMaxOperations(3))
+
+		result, err := p.Resolve(uniqueSuffix)
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "maximum number of operations[3] exceeded")
+	})
+
+	t.Run("success - invalid operations are not counted", func(t *testing.T) {
+		createOnlyStore, createOnlySuffix := getDefaultStore(recoveryKey, updateKey)
+
+		p := New("test", createOnlyStore, pc, WithMaxOperations(1))
+
+		_, err := p.Resolve(createOnlySuffix)
 		require.NoError(t, err)
 
-		// protocol value for hashing algorithm changed at block 100
-		op, nextUpdateKey, err := getUpdateOperation(nextUpdateKey, uniqueSuffix, 500)
+		require.NoError(t, createOnlyStore.Put(&operation.AnchoredOperation{
+			UniqueSuffix: createOnlySuffix, Type: operation.TypeUpdate, TransactionTime: 1,
+		}))
+
+		_, err = p.Resolve(createOnlySuffix)
 		require.NoError(t, err)
+	})
+}
 
-		op.RevealValue = rv
+func TestLogger(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
 
-		updateOp = getAnchoredOperation(op, 500)
-		err = store.Put(updateOp)
-		require.Nil(t, err)
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
 
-		result, err = p.Resolve(uniqueSuffix)
-		require.Nil(t, err)
+	t.Run("success - rejected operation is logged through injected logger", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
 
-		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
-		require.Equal(t, "special500", didDoc["test"])
+		createOp, err := getCreateOperation(recoveryKey, updateKey, defaultBlockNumber)
+		require.NoError(t, err)
 
-		// test consecutive update within new protocol value
-		updateOp, nextUpdateKey, err = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, 700)
-		require.Nil(t, err)
-		err = store.Put(updateOp)
-		require.Nil(t, err)
+		createOp.SuffixData = &model.SuffixDataModel{}
 
-		result, err = p.Resolve(uniqueSuffix)
+		err = store.Put(getAnchoredOperation(createOp, defaultBlockNumber))
 		require.Nil(t, err)
 
-		// check if service type value is updated again (done via json patch)
-		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
-		require.Equal(t, "special700", didDoc["test"])
+		l := &capturingLogger{}
+
+		p := New("test", store, newMockProtocolClient(), WithLogger(l))
+		doc, err := p.Resolve(createOp.UniqueSuffix)
+		require.Error(t, err)
+		require.Nil(t, doc)
+
+		require.NotEmpty(t, l.debugMessages())
+		require.Len(t, l.infoMessages(), 1)
+		require.Contains(t, l.infoMessages()[0],
+			fmt.Sprintf("[test] Skipped bad operation {UniqueSuffix: %s, Type: create", createOp.UniqueSuffix))
 	})
+}
 
-	t.Run("success -  operation with reused next commitment ignored", func(t *testing.T) {
-		// scenario: update 1 followed by update 2 followed by update 3 with reused commitment from 1
+func TestResolveAt(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	pc := newMockProtocolClient()
 
+	t.Run("success - document at intermediate transaction", func(t *testing.T) {
 		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
 
-		updateOp, nextUpdateKey, err := getUpdateOperation(updateKey, uniqueSuffix, 1)
-		require.Nil(t, err)
+		nextUpdateKey := updateKey
 
-		delta1 := updateOp.Delta
+		for blockNum := uint64(1); blockNum <= 3; blockNum++ {
+			var updateOp *operation.AnchoredOperation
 
-		err = store.Put(getAnchoredOperation(updateOp, 1))
-		require.Nil(t, err)
+			updateOp, nextUpdateKey, e = getAnchoredUpdateOperation(nextUpdateKey, uniqueSuffix, blockNum)
+			require.NoError(t, e)
 
-		p := New("test", store, pc)
-		result, err := p.Resolve(uniqueSuffix)
-		require.Nil(t, err)
+			require.NoError(t, store.Put(updateOp))
+		}
 
-		// check if service type value is updated (done via json patch)
-		didDoc := document.DidDocumentFromJSONLDObject(result.Doc)
-		require.Equal(t, "special1", didDoc["test"])
+		p := New("test", store, pc, WithDocumentCache(10))
 
-		// test consecutive update
-		updateOp, nextUpdateKey, err = getUpdateOperation(nextUpdateKey, uniqueSuffix, 2)
-		require.Nil(t, err)
+		result, err := p.ResolveAt(uniqueSuffix, 2, 0)
+		require.NoError(t, err)
+		require.Equal(t, "special2", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+		require.Equal(t, uint64(2), result.LastOperationTransactionTime)
 
-		err = store.Put(getAnchoredOperation(updateOp, 2))
-		require.Nil(t, err)
+		result, err = p.ResolveAt(uniqueSuffix, 1, 0)
+		require.NoError(t, err)
+		require.Equal(t, "special1", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+
+		// only create operation
+		result, err = p.ResolveAt(uniqueSuffix, defaultBlockNumber, 0)
+		require.NoError(t, err)
+		require.Nil(t, document.DidDocumentFromJSONLDObject(result.Doc)["test"])
 
+		// historical resolution is not cached
 		result, err = p.Resolve(uniqueSuffix)
-		require.Nil(t, err)
+		require.NoError(t, err)
+		require.Equal(t, "special3", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
 
-		// service type value is updated since operation is valid
-		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
-		require.Equal(t, "special2", didDoc["test"])
+		result, err = p.ResolveAt(uniqueSuffix, 100, 0)
+		require.NoError(t, err)
+		require.Equal(t, "special3", document.DidDocumentFromJSONLDObject(result.Doc)["test"])
+	})
 
-		// two successful update operations - next update with reused commitment from op 1
-		updateOp, nextUpdateKey, err = getUpdateOperation(nextUpdateKey, uniqueSuffix, 1)
-		require.Nil(t, err)
+	t.Run("error - document created after transaction", func(t *testing.T) {
+		store := mocks.NewMockOperationStore(nil)
 
-		delta3 := updateOp.Delta
-		delta3.UpdateCommitment = delta1.UpdateCommitment
+		createOp, err := getCreateOperation(recoveryKey, updateKey, 5)
+		require.NoError(t, err)
 
-		err = store.Put(getAnchoredOperation(updateOp, 1))
-		require.Nil(t, err)
+		anchoredOp := getAnchoredOperation(createOp, 5)
+		require.NoError(t, store.Put(anchoredOp))
 
-		result, err = p.Resolve(uniqueSuffix)
-		require.Nil(t, err)
+		p := New("test", store, pc)
 
-		// service type value is not updated since commitment value was reused
-		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
-		require.Equal(t, "special2", didDoc["test"])
+		result, err := p.ResolveAt(anchoredOp.UniqueSuffix, 4, 0)
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "missing create operation")
 	})
 
-	t.Run("success - operation with same commitment as next operation commitment is ignored", func(t *testing.T) {
-		// scenario: update 1 followed by update 2 with same operation commitment as next operation commitment
+	t.Run("error - store error", func(t *testing.T) {
+		p := New("test", mocks.NewMockOperationStore(errors.New("store error")), pc)
 
-		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+		result, err := p.ResolveAt("suffix", 1, 0)
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "store error")
+	})
+}
 
-		updateOp, nextUpdateKey, err := getUpdateOperation(updateKey, uniqueSuffix, 1)
-		require.Nil(t, err)
+func TestDocumentTimes(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
 
-		delta1 := updateOp.Delta
-		require.NoError(t, err)
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
 
-		err = store.Put(getAnchoredOperation(updateOp, 1))
-		require.Nil(t, err)
+	pc := newMockProtocolClient()
 
-		p := New("test", store, pc)
-		result, err := p.Resolve(uniqueSuffix)
-		require.Nil(t, err)
+	store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
 
-		// check if service type value is updated (done via json patch)
-		didDoc := document.DidDocumentFromJSONLDObject(result.Doc)
-		require.Equal(t, "special1", didDoc["test"])
+	p := New("test", store, pc)
 
-		// update operation commitment is the same as next operation commitment
-		updateOp, nextUpdateKey, err = getUpdateOperation(nextUpdateKey, uniqueSuffix, 1)
-		require.Nil(t, err)
+	// create only
+	result, err := p.Resolve(uniqueSuffix)
+	require.NoError(t, err)
+	require.Equal(t, uint64(defaultBlockNumber), result.CreatedTime)
+	require.Equal(t, uint64(0), result.UpdatedTime)
 
-		delta2 := updateOp.Delta
-		delta2.UpdateCommitment = delta1.UpdateCommitment
+	// create + update
+	updateOp, _, err := getAnchoredUpdateOperation(updateKey, uniqueSuffix, 5)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(updateOp))
 
-		err = store.Put(getAnchoredOperation(updateOp, 1))
-		require.Nil(t, err)
+	result, err = p.Resolve(uniqueSuffix)
+	require.NoError(t, err)
+	require.Equal(t, uint64(defaultBlockNumber), result.CreatedTime)
+	require.Equal(t, uint64(5), result.UpdatedTime)
 
-		result, err = p.Resolve(uniqueSuffix)
-		require.Nil(t, err)
+	// create + update + recover
+	recoverOp, nextRecoveryKey, err := getAnchoredRecoverOperation(recoveryKey, updateKey, uniqueSuffix, 10)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(recoverOp))
 
-		// service type value is not updated since commitment value was reused
-		didDoc = document.DidDocumentFromJSONLDObject(result.Doc)
-		require.Equal(t, "special1", didDoc["test"])
-	})
+	result, err = p.Resolve(uniqueSuffix)
+	require.NoError(t, err)
+	require.Equal(t, uint64(defaultBlockNumber), result.CreatedTime)
+	require.Equal(t, uint64(10), result.UpdatedTime)
+
+	// create + update + recover + deactivate
+	deactivateOp, err := getDeactivateOperation(nextRecoveryKey, uniqueSuffix)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(getAnchoredOperation(deactivateOp, 15)))
+
+	result, err = p.Resolve(uniqueSuffix)
+	require.NoError(t, err)
+	require.True(t, result.Deactivated)
+	require.Equal(t, uint64(defaultBlockNumber), result.CreatedTime)
+	require.Equal(t, uint64(15), result.UpdatedTime)
 }
 
 func TestProcessOperation(t *testing.T) {
@@ -429,6 +1183,33 @@ func TestDeactivate(t *testing.T) {
 		require.Empty(t, doc.UpdateCommitment)
 		require.Empty(t, doc.RecoveryCommitment)
 	})
+
+	t.Run("success - deactivation transaction metadata", func(t *testing.T) {
+		store, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		op, err := getDeactivateOperation(recoveryKey, uniqueSuffix)
+		require.NoError(t, err)
+
+		err = store.Put(getAnchoredOperation(op, 7))
+		require.NoError(t, err)
+
+		p := New("test", store, pc)
+		rm, err := p.Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.True(t, rm.Deactivated)
+		require.Equal(t, uint64(7), rm.LastOperationTransactionTime)
+		require.Equal(t, uint64(defaultBlockNumber), rm.CreatedTime)
+		require.Equal(t, uint64(7), rm.UpdatedTime)
+
+		info := make(protocol.TransformationInfo)
+		info[document.IDProperty] = "did:sidetree:" + uniqueSuffix
+		info[document.PublishedProperty] = true
+
+		metadata, err := doctransformer.CreateDocumentMetadata(rm, info)
+		require.NoError(t, err)
+		require.Equal(t, true, metadata[document.DeactivatedProperty])
+		require.Equal(t, uint64(7), metadata[document.UpdatedProperty])
+	})
 }
 
 func TestRecover(t *testing.T) {
@@ -865,6 +1646,123 @@ func TestOpsWithTxnGreaterThan(t *testing.T) {
 	require.Equal(t, 1, len(txns))
 }
 
+func TestOpsWithTxnLessThanOrEqualTo(t *testing.T) {
+	op1 := &operation.AnchoredOperation{
+		TransactionTime:   1,
+		TransactionNumber: 1,
+	}
+
+	op2 := &operation.AnchoredOperation{
+		TransactionTime:   1,
+		TransactionNumber: 2,
+	}
+
+	op3 := &operation.AnchoredOperation{
+		TransactionTime:   2,
+		TransactionNumber: 1,
+	}
+
+	ops := []*operation.AnchoredOperation{op1, op2, op3}
+
+	txns := getOpsWithTxnLessThanOrEqualTo(ops, 0, 0)
+	require.Equal(t, 0, len(txns))
+
+	txns = getOpsWithTxnLessThanOrEqualTo(ops, 1, 1)
+	require.Equal(t, 1, len(txns))
+
+	txns = getOpsWithTxnLessThanOrEqualTo(ops, 1, 5)
+	require.Equal(t, 2, len(txns))
+
+	txns = getOpsWithTxnLessThanOrEqualTo(ops, 2, 1)
+	require.Equal(t, 3, len(txns))
+}
+
+type countingProtocolClient struct {
+	protocol.Client
+	calls int
+	onGet func(calls int)
+}
+
+func (c *countingProtocolClient) Get(transactionTime uint64) (protocol.Version, error) {
+	c.calls++
+
+	if c.onGet != nil {
+		c.onGet(c.calls)
+	}
+
+	return c.Client.Get(transactionTime)
+}
+
+// blockingOperationStore blocks until context is done.
+type blockingOperationStore struct {
+	OperationStoreClient
+	ctx context.Context
+}
+
+func (s *blockingOperationStore) Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error) {
+	<-s.ctx.Done()
+
+	return s.OperationStoreClient.Get(uniqueSuffix)
+}
+
+// bulkOperationStore overrides default (looping) bulk retrieval of operations.
+type bulkOperationStore struct {
+	*mocks.MockOperationStore
+	err       error
+	bulkCalls int
+}
+
+func (s *bulkOperationStore) GetBulk(uniqueSuffixes []string) (map[string][]*operation.AnchoredOperation, error) {
+	s.bulkCalls++
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	result := make(map[string][]*operation.AnchoredOperation)
+
+	for _, suffix := range uniqueSuffixes {
+		ops, err := s.Get(suffix)
+		if err != nil {
+			continue
+		}
+
+		result[suffix] = ops
+	}
+
+	return result, nil
+}
+
+// maxUpdateSizeRule rejects update operations larger than maximum size.
+type maxUpdateSizeRule struct {
+	maxSize int
+	calls   int
+}
+
+func (r *maxUpdateSizeRule) Validate(op *operation.AnchoredOperation, rm *protocol.ResolutionModel) error {
+	r.calls++
+
+	if rm == nil {
+		return fmt.Errorf("missing resolution model")
+	}
+
+	if op.Type == operation.TypeUpdate && len(op.OperationBuffer) > r.maxSize {
+		return fmt.Errorf("update operation size %d exceeds maximum size %d", len(op.OperationBuffer), r.maxSize)
+	}
+
+	return nil
+}
+
+type rejectCreateRule struct{}
+
+func (r *rejectCreateRule) Validate(op *operation.AnchoredOperation, _ *protocol.ResolutionModel) error {
+	if op.Type == operation.TypeCreate {
+		return fmt.Errorf("create operations are not allowed")
+	}
+
+	return nil
+}
+
 func getUpdateOperation(privateKey *ecdsa.PrivateKey, uniqueSuffix string, blockNum uint64) (*model.Operation, *ecdsa.PrivateKey, error) {
 	s := ecsigner.New(privateKey, "ES256", "")
 
@@ -1313,6 +2211,22 @@ func (m *mockDocComposer) ApplyPatches(doc document.Document, patches []patch.Pa
 	return make(document.Document), nil
 }
 
+// fieldComposer applies patches using default document composer and then adds field to the document.
+type fieldComposer struct {
+	field string
+}
+
+func (c *fieldComposer) ApplyPatches(doc document.Document, patches []patch.Patch) (document.Document, error) {
+	result, err := doccomposer.New().ApplyPatches(doc, patches)
+	if err != nil {
+		return nil, err
+	}
+
+	result[c.field] = true
+
+	return result, nil
+}
+
 // mock protocol client with two protocol versions, first one effective at block 0, second at block 100.
 func newMockProtocolClient() *mocks.MockProtocolClient {
 	pc := mocks.NewMockProtocolClient()
@@ -1353,3 +2267,90 @@ func newMockProtocolClient() *mocks.MockProtocolClient {
 
 	return pc
 }
+
+type capturingLogger struct {
+	mutex sync.Mutex
+	debug []string
+	info  []string
+}
+
+func (l *capturingLogger) Debugf(msg string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.debug = append(l.debug, fmt.Sprintf(msg, args...))
+}
+
+func (l *capturingLogger) Infof(msg string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.info = append(l.info, fmt.Sprintf(msg, args...))
+}
+
+// appliedTransactionTimes returns transaction times of applied update, recover and deactivate operations
+// (in the order they were applied) from processor debug messages.
+func appliedTransactionTimes(debugMessages []string) []uint64 {
+	var times []uint64
+
+	for _, msg := range debugMessages {
+		if !strings.Contains(msg, "After applying op ") {
+			continue
+		}
+
+		var txnTime uint64
+
+		i := strings.Index(msg, "TransactionTime:")
+		if i < 0 {
+			continue
+		}
+
+		if _, err := fmt.Sscanf(msg[i:], "TransactionTime:%d", &txnTime); err == nil {
+			times = append(times, txnTime)
+		}
+	}
+
+	return times
+}
+
+func (l *capturingLogger) debugMessages() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.debug
+}
+
+func (l *capturingLogger) infoMessages() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.info
+}
+
+type concurrencyTrackingStore struct {
+	*mocks.MockOperationStore
+	mutex     sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (s *concurrencyTrackingStore) Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error) {
+	s.mutex.Lock()
+	s.active++
+
+	if s.active > s.maxActive {
+		s.maxActive = s.active
+	}
+
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		s.active--
+		s.mutex.Unlock()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	return s.MockOperationStore.Get(uniqueSuffix)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+// ShardFunc returns the index of the operation store shard that holds operations for the given unique suffix.
+type ShardFunc func(uniqueSuffix string) int
+
+// NewShardedOperationStoreClient returns operation store client that routes retrieval of operations for
+// each unique suffix to the store chosen by the given shard function. Bulk retrieval is split into
+// one bulk call per shard.
+func NewShardedOperationStoreClient(shard ShardFunc, stores ...OperationStoreClient) BulkOperationStoreClient {
+	return &shardedStore{shard: shard, stores: stores}
+}
+
+type shardedStore struct {
+	shard  ShardFunc
+	stores []OperationStoreClient
+}
+
+// Get retrieves all operations related to document from the shard that holds the given unique suffix.
+func (s *shardedStore) Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error) {
+	i, err := s.shardIndex(uniqueSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stores[i].Get(uniqueSuffix)
+}
+
+// GetBulk retrieves operations for the given unique suffixes with one bulk call per shard.
+func (s *shardedStore) GetBulk(uniqueSuffixes []string) (map[string][]*operation.AnchoredOperation, error) {
+	suffixesByShard := make(map[int][]string)
+
+	for _, uniqueSuffix := range uniqueSuffixes {
+		i, err := s.shardIndex(uniqueSuffix)
+		if err != nil {
+			return nil, err
+		}
+
+		suffixesByShard[i] = append(suffixesByShard[i], uniqueSuffix)
+	}
+
+	result := make(map[string][]*operation.AnchoredOperation)
+
+	for i, suffixes := range suffixesByShard {
+		ops, err := NewBulkOperationStoreClient(s.stores[i]).GetBulk(suffixes)
+		if err != nil {
+			return nil, fmt.Errorf("get operations from shard[%d]: %s", i, err.Error())
+		}
+
+		for suffix, suffixOps := range ops {
+			result[suffix] = suffixOps
+		}
+	}
+
+	return result, nil
+}
+
+func (s *shardedStore) shardIndex(uniqueSuffix string) (int, error) {
+	i := s.shard(uniqueSuffix)
+	if i < 0 || i >= len(s.stores) {
+		return 0, fmt.Errorf("shard[%d] for unique suffix[%s] is out of range (number of shards: %d)",
+			i, uniqueSuffix, len(s.stores))
+	}
+
+	return i, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+func TestNewShardedOperationStoreClient(t *testing.T) {
+	shard0 := mocks.NewMockOperationStore(nil)
+	shard1 := mocks.NewMockOperationStore(nil)
+
+	require.NoError(t, shard0.Put(&operation.AnchoredOperation{Type: operation.TypeCreate, UniqueSuffix: "suffix0"}))
+	require.NoError(t, shard1.Put(&operation.AnchoredOperation{Type: operation.TypeCreate, UniqueSuffix: "suffix1"}))
+
+	shard := func(uniqueSuffix string) int {
+		return int(uniqueSuffix[len(uniqueSuffix)-1] - '0')
+	}
+
+	t.Run("success - get is routed to shard", func(t *testing.T) {
+		store := NewShardedOperationStoreClient(shard, shard0, shard1)
+
+		ops, err := store.Get("suffix1")
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, "suffix1", ops[0].UniqueSuffix)
+
+		// suffix0 is only in shard 0
+		ops, err = NewShardedOperationStoreClient(func(string) int { return 1 }, shard0, shard1).Get("suffix0")
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), "uniqueSuffix not found in the store")
+	})
+
+	t.Run("success - bulk retrieval from multiple shards", func(t *testing.T) {
+		bulkShard := &bulkOperationStore{MockOperationStore: shard1}
+
+		store := NewShardedOperationStoreClient(shard, shard0, bulkShard)
+
+		ops, err := store.GetBulk([]string{"suffix0", "suffix1"})
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.Equal(t, "suffix0", ops["suffix0"][0].UniqueSuffix)
+		require.Equal(t, "suffix1", ops["suffix1"][0].UniqueSuffix)
+		require.Equal(t, 1, bulkShard.bulkCalls)
+	})
+
+	t.Run("success - resolve through operation processor", func(t *testing.T) {
+		recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		docStore, uniqueSuffix := getDefaultStore(recoveryKey, updateKey)
+
+		store := NewShardedOperationStoreClient(func(string) int { return 1 }, shard0, docStore)
+
+		doc, err := New("test", store, newMockProtocolClient()).Resolve(uniqueSuffix)
+		require.NoError(t, err)
+		require.NotNil(t, doc)
+
+		results := New("test", store, newMockProtocolClient()).ResolveBatch([]string{uniqueSuffix}, 1)
+		require.NoError(t, results[uniqueSuffix].Err)
+	})
+
+	t.Run("error - shard out of range", func(t *testing.T) {
+		store := NewShardedOperationStoreClient(func(string) int { return 2 }, shard0, shard1)
+
+		ops, err := store.Get("suffix0")
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), "shard[2] for unique suffix[suffix0] is out of range (number of shards: 2)")
+
+		bulkOps, err := store.GetBulk([]string{"suffix0"})
+		require.Error(t, err)
+		require.Nil(t, bulkOps)
+		require.Contains(t, err.Error(), "is out of range")
+	})
+
+	t.Run("error - shard error", func(t *testing.T) {
+		store := NewShardedOperationStoreClient(shard, shard0, mocks.NewMockOperationStore(errors.New("store error")))
+
+		ops, err := store.GetBulk([]string{"suffix0", "suffix1"})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), "get operations from shard[1]")
+		require.Contains(t, err.Error(), "store error")
+	})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memstore
+
+import (
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+)
+
+// ProcessedTxnStore keeps track of processed Sidetree transactions in memory.
+type ProcessedTxnStore struct {
+	mutex     sync.RWMutex
+	processed map[txnKey]struct{}
+}
+
+// txnKey identifies transaction by namespace, transaction time and transaction number.
+type txnKey struct {
+	namespace         string
+	transactionTime   uint64
+	transactionNumber uint64
+}
+
+// NewProcessedTxnStore returns new in-memory processed transaction store.
+func NewProcessedTxnStore() *ProcessedTxnStore {
+	return &ProcessedTxnStore{processed: make(map[txnKey]struct{})}
+}
+
+// IsProcessed returns true if the given transaction has been marked as processed.
+func (s *ProcessedTxnStore) IsProcessed(sidetreeTxn txn.SidetreeTxn) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.processed[keyOf(sidetreeTxn)]
+
+	return ok, nil
+}
+
+// MarkProcessed records the given transaction as processed.
+func (s *ProcessedTxnStore) MarkProcessed(sidetreeTxn txn.SidetreeTxn) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.processed[keyOf(sidetreeTxn)] = struct{}{}
+
+	return nil
+}
+
+func keyOf(sidetreeTxn txn.SidetreeTxn) txnKey {
+	return txnKey{
+		namespace:         sidetreeTxn.Namespace,
+		transactionTime:   sidetreeTxn.TransactionTime,
+		transactionNumber: sidetreeTxn.TransactionNumber,
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprocessor"
+)
+
+var _ txnprocessor.ProcessedTxnStore = (*ProcessedTxnStore)(nil)
+
+func TestProcessedTxnStore(t *testing.T) {
+	sidetreeTxn := txn.SidetreeTxn{Namespace: "did:sidetree", TransactionTime: 10, TransactionNumber: 1}
+
+	t.Run("success - mark processed", func(t *testing.T) {
+		s := NewProcessedTxnStore()
+
+		processed, err := s.IsProcessed(sidetreeTxn)
+		require.NoError(t, err)
+		require.False(t, processed)
+
+		require.NoError(t, s.MarkProcessed(sidetreeTxn))
+
+		processed, err = s.IsProcessed(sidetreeTxn)
+		require.NoError(t, err)
+		require.True(t, processed)
+	})
+
+	t.Run("success - transactions are keyed by namespace, time and number", func(t *testing.T) {
+		s := NewProcessedTxnStore()
+
+		require.NoError(t, s.MarkProcessed(sidetreeTxn))
+
+		otherNamespace := sidetreeTxn
+		otherNamespace.Namespace = "did:other"
+
+		otherTime := sidetreeTxn
+		otherTime.TransactionTime = 11
+
+		otherNumber := sidetreeTxn
+		otherNumber.TransactionNumber = 2
+
+		for _, other := range []txn.SidetreeTxn{otherNamespace, otherTime, otherNumber} {
+			processed, err := s.IsProcessed(other)
+			require.NoError(t, err)
+			require.False(t, processed)
+		}
+
+		// other fields (e.g. anchor string) are not part of the key
+		sameTxn := sidetreeTxn
+		sameTxn.AnchorString = "1.anchor"
+
+		processed, err := s.IsProcessed(sameTxn)
+		require.NoError(t, err)
+		require.True(t, processed)
+	})
+
+	t.Run("success - transaction processor skips already processed transaction", func(t *testing.T) {
+		opStore := New()
+		opsProvider := &txnOpsProvider{
+			ops: []*operation.AnchoredOperation{{UniqueSuffix: "suffix", Type: operation.TypeCreate}},
+		}
+
+		p := txnprocessor.New(&txnprocessor.Providers{
+			OpStore:                   opStore,
+			OperationProtocolProvider: opsProvider,
+			ProcessedTxnStore:         NewProcessedTxnStore(),
+		})
+
+		require.NoError(t, p.Process(sidetreeTxn))
+		require.NoError(t, p.Process(sidetreeTxn))
+		require.NoError(t, p.ProcessBatch([]txn.SidetreeTxn{sidetreeTxn}))
+
+		require.Equal(t, 1, opsProvider.calls)
+
+		ops, err := opStore.Get("suffix")
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, sidetreeTxn.TransactionNumber, ops[0].TransactionNumber)
+	})
+}
+
+type txnOpsProvider struct {
+	ops   []*operation.AnchoredOperation
+	calls int
+}
+
+func (p *txnOpsProvider) GetTxnOperations(sidetreeTxn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	return p.GetTxnOperationsContext(context.Background(), sidetreeTxn)
+}
+
+func (p *txnOpsProvider) GetTxnOperationsContext(_ context.Context, _ *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	p.calls++
+
+	// return copies since transaction processor sets anchoring details on returned operations
+	var ops []*operation.AnchoredOperation
+
+	for _, op := range p.ops {
+		opCopy := *op
+		ops = append(ops, &opCopy)
+	}
+
+	return ops, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package memstore implements operation store and processed transaction store that keep data in memory.
+// It is intended for local development and testing.
+package memstore
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+// Store keeps anchored operations in memory (indexed by unique suffix).
+type Store struct {
+	mutex      sync.RWMutex
+	operations map[string][]*operation.AnchoredOperation
+}
+
+// New returns new in-memory operation store.
+func New() *Store {
+	return &Store{operations: make(map[string][]*operation.AnchoredOperation)}
+}
+
+// Put appends the given operations to operations stored for their unique suffixes.
+func (s *Store) Put(ops []*operation.AnchoredOperation) error {
+	for _, op := range ops {
+		if op.UniqueSuffix == "" {
+			return fmt.Errorf("missing unique suffix for %s operation", op.Type)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, op := range ops {
+		s.operations[op.UniqueSuffix] = append(s.operations[op.UniqueSuffix], op)
+	}
+
+	return nil
+}
+
+// Get returns all operations stored for the given unique suffix (in the order they were stored).
+// Error wrapping operation.ErrNotFound is returned if there are no operations for the unique suffix.
+func (s *Store) Get(uniqueSuffix string) ([]*operation.AnchoredOperation, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ops, ok := s.operations[uniqueSuffix]
+	if !ok {
+		return nil, fmt.Errorf("%w: suffix[%s]", operation.ErrNotFound, uniqueSuffix)
+	}
+
+	// return a copy so that subsequent puts don't affect returned operations
+	return append([]*operation.AnchoredOperation{}, ops...), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/observer"
+	"github.com/trustbloc/sidetree-core-go/pkg/processor"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprocessor"
+)
+
+var (
+	_ processor.OperationStoreClient = (*Store)(nil)
+	_ txnprocessor.OperationStore    = (*Store)(nil)
+	_ observer.OperationReader       = (*Store)(nil)
+)
+
+func TestStore(t *testing.T) {
+	createOp := &operation.AnchoredOperation{UniqueSuffix: "suffix", Type: operation.TypeCreate}
+	updateOp := &operation.AnchoredOperation{UniqueSuffix: "suffix", Type: operation.TypeUpdate}
+	otherOp := &operation.AnchoredOperation{UniqueSuffix: "other", Type: operation.TypeCreate}
+
+	t.Run("success - put and get", func(t *testing.T) {
+		s := New()
+
+		require.NoError(t, s.Put([]*operation.AnchoredOperation{createOp, otherOp}))
+		require.NoError(t, s.Put([]*operation.AnchoredOperation{updateOp}))
+
+		ops, err := s.Get("suffix")
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{createOp, updateOp}, ops)
+
+		ops, err = s.Get("other")
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{otherOp}, ops)
+	})
+
+	t.Run("success - returned operations are not affected by subsequent put", func(t *testing.T) {
+		s := New()
+
+		require.NoError(t, s.Put([]*operation.AnchoredOperation{createOp}))
+
+		ops, err := s.Get("suffix")
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put([]*operation.AnchoredOperation{updateOp}))
+		require.Len(t, ops, 1)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		ops, err := New().Get("suffix")
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.True(t, errors.Is(err, operation.ErrNotFound))
+		require.Contains(t, err.Error(), "suffix[suffix]")
+	})
+
+	t.Run("error - missing unique suffix", func(t *testing.T) {
+		s := New()
+
+		err := s.Put([]*operation.AnchoredOperation{createOp, {Type: operation.TypeUpdate}})
+		require.EqualError(t, err, "missing unique suffix for update operation")
+
+		// nothing is stored if any of the operations is invalid
+		_, err = s.Get("suffix")
+		require.True(t, errors.Is(err, operation.ErrNotFound))
+	})
+
+	t.Run("success - validation filter treats not found as missing document", func(t *testing.T) {
+		s := New()
+
+		valid, rejected, err := observer.NewOperationValidationFilter(s).
+			FilterWithRejections("suffix", []*operation.AnchoredOperation{updateOp})
+		require.NoError(t, err)
+		require.Empty(t, valid)
+		require.Len(t, rejected, 1)
+
+		require.NoError(t, s.Put([]*operation.AnchoredOperation{createOp}))
+
+		valid, err = observer.NewOperationValidationFilter(s).Filter("suffix", []*operation.AnchoredOperation{updateOp})
+		require.NoError(t, err)
+		require.Equal(t, []*operation.AnchoredOperation{updateOp}, valid)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/multiformats/go-multihash"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-core-go/pkg/patch"
@@ -50,6 +51,51 @@ type CreateRequestInfo struct {
 
 // NewCreateRequest is utility function to create payload for 'create' request.
 func NewCreateRequest(info *CreateRequestInfo) ([]byte, error) {
+	schema, err := newCreateRequestModel(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return canonicalizer.MarshalCanonical(schema)
+}
+
+// BuildCreateOperation is utility function to build create operation from patches and commitments
+// using the first multihash algorithm of the given protocol. It returns operation and encoded 'create' request.
+func BuildCreateOperation(patches []patch.Patch, recoveryCommitment, updateCommitment string, p protocol.Protocol) (*model.Operation, []byte, error) {
+	if len(p.MultihashAlgorithms) == 0 {
+		return nil, nil, errors.New("protocol multihash algorithms not provided")
+	}
+
+	schema, err := newCreateRequestModel(&CreateRequestInfo{
+		Patches:            patches,
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		MultihashCode:      p.MultihashAlgorithms[0],
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request, err := canonicalizer.MarshalCanonical(schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uniqueSuffix, err := model.GetUniqueSuffix(schema.SuffixData, p.MultihashAlgorithms)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &model.Operation{
+		OperationBuffer: request,
+		Type:            operation.TypeCreate,
+		UniqueSuffix:    uniqueSuffix,
+		Delta:           schema.Delta,
+		SuffixData:      schema.SuffixData,
+	}, request, nil
+}
+
+func newCreateRequestModel(info *CreateRequestInfo) (*model.CreateRequest, error) {
 	if err := validateCreateRequest(info); err != nil {
 		return nil, err
 	}
@@ -76,13 +122,11 @@ func NewCreateRequest(info *CreateRequestInfo) ([]byte, error) {
 		Type:               info.Type,
 	}
 
-	schema := &model.CreateRequest{
+	return &model.CreateRequest{
 		Operation:  operation.TypeCreate,
 		Delta:      delta,
 		SuffixData: suffixData,
-	}
-
-	return canonicalizer.MarshalCanonical(schema)
+	}, nil
 }
 
 func getPatches(opaque string, patches []patch.Patch) ([]patch.Patch, error) {
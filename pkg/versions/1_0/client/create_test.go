@@ -15,10 +15,13 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 	"github.com/trustbloc/sidetree-core-go/pkg/patch"
 	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
 )
 
 const (
@@ -183,3 +186,73 @@ const addKeys = `[{
 		"x": "PUymIqdtF_qxaAqPABSw-C-owT1KYYQbsMKFM-L9fJA"
 	}
 }]`
+
+func TestBuildCreateOperation(t *testing.T) {
+	recoverPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updatePrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	recoverJWK, err := pubkey.GetPublicKeyJWK(&recoverPrivateKey.PublicKey)
+	require.NoError(t, err)
+
+	updateJWK, err := pubkey.GetPublicKeyJWK(&updatePrivateKey.PublicKey)
+	require.NoError(t, err)
+
+	recoveryCommitment, err := commitment.GetCommitment(recoverJWK, sha2_256)
+	require.NoError(t, err)
+
+	updateCommitment, err := commitment.GetCommitment(updateJWK, sha2_256)
+	require.NoError(t, err)
+
+	servicePatch, err := patch.NewAddServiceEndpointsPatch(`[{"id":"svc","type":"type","serviceEndpoint":"https://example.com"}]`)
+	require.NoError(t, err)
+
+	patches := []patch.Patch{servicePatch}
+
+	p := mocks.GetDefaultProtocolParameters()
+
+	t.Run("success - round trip through parser", func(t *testing.T) {
+		op, request, err := BuildCreateOperation(patches, recoveryCommitment, updateCommitment, p)
+		require.NoError(t, err)
+		require.NotNil(t, op)
+		require.NotEmpty(t, request)
+		require.Equal(t, operation.TypeCreate, op.Type)
+		require.Equal(t, request, op.OperationBuffer)
+
+		parsed, err := operationparser.New(p).ParseCreateOperation(request, false)
+		require.NoError(t, err)
+		require.Equal(t, op.UniqueSuffix, parsed.UniqueSuffix)
+		require.Equal(t, op.SuffixData, parsed.SuffixData)
+		require.Equal(t, op.Delta.UpdateCommitment, parsed.Delta.UpdateCommitment)
+		require.Equal(t, recoveryCommitment, parsed.SuffixData.RecoveryCommitment)
+	})
+
+	t.Run("error - missing multihash algorithms", func(t *testing.T) {
+		invalid := p
+		invalid.MultihashAlgorithms = nil
+
+		op, request, err := BuildCreateOperation(patches, recoveryCommitment, updateCommitment, invalid)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Nil(t, request)
+		require.Contains(t, err.Error(), "protocol multihash algorithms not provided")
+	})
+
+	t.Run("error - missing patches", func(t *testing.T) {
+		op, request, err := BuildCreateOperation(nil, recoveryCommitment, updateCommitment, p)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Nil(t, request)
+		require.Contains(t, err.Error(), "either opaque document or patches have to be supplied")
+	})
+
+	t.Run("error - equal commitments", func(t *testing.T) {
+		op, request, err := BuildCreateOperation(patches, recoveryCommitment, recoveryCommitment, p)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Nil(t, request)
+		require.Contains(t, err.Error(), "recovery and update commitments cannot be equal")
+	})
+}
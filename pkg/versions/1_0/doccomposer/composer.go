@@ -21,11 +21,39 @@ var logger = log.New("sidetree-core-composer")
 
 // DocumentComposer applies patches to the document.
 type DocumentComposer struct {
+	strictKeyRemoval bool
+	maxJSONPatchOps  int
+}
+
+// Option is a document composer instance option.
+type Option func(opts *DocumentComposer)
+
+// WithStrictKeyRemoval instructs document composer to fail remove public keys patch if any of the key ids
+// doesn't exist in the document. By default unknown key ids are ignored.
+func WithStrictKeyRemoval() Option {
+	return func(opts *DocumentComposer) {
+		opts.strictKeyRemoval = true
+	}
+}
+
+// WithMaxJSONPatchOperations sets the maximum number of operations in a single JSON patch (RFC 6902).
+// Zero (default) means that number of operations is not checked.
+func WithMaxJSONPatchOperations(maxOps int) Option {
+	return func(opts *DocumentComposer) {
+		opts.maxJSONPatchOps = maxOps
+	}
 }
 
 // New creates new document composer.
-func New() *DocumentComposer {
-	return &DocumentComposer{}
+func New(opts ...Option) *DocumentComposer {
+	dc := &DocumentComposer{}
+
+	// apply options
+	for _, opt := range opts {
+		opt(dc)
+	}
+
+	return dc
 }
 
 // ApplyPatches applies patches to the document.
@@ -36,7 +64,7 @@ func (c *DocumentComposer) ApplyPatches(doc document.Document, patches []patch.P
 	}
 
 	for _, p := range patches {
-		result, err = applyPatch(result, p)
+		result, err = c.applyPatch(result, p)
 		if err != nil {
 			return nil, err
 		}
@@ -46,7 +74,7 @@ func (c *DocumentComposer) ApplyPatches(doc document.Document, patches []patch.P
 }
 
 // applyPatch applies a patch to the document.
-func applyPatch(doc document.Document, p patch.Patch) (document.Document, error) {
+func (c *DocumentComposer) applyPatch(doc document.Document, p patch.Patch) (document.Document, error) {
 	action, err := p.GetAction()
 	if err != nil {
 		return nil, err
@@ -61,11 +89,11 @@ func applyPatch(doc document.Document, p patch.Patch) (document.Document, error)
 	case patch.Replace:
 		return applyRecover(value)
 	case patch.JSONPatch:
-		return applyJSON(doc, value)
+		return c.applyJSON(doc, value)
 	case patch.AddPublicKeys:
 		return applyAddPublicKeys(doc, value)
 	case patch.RemovePublicKeys:
-		return applyRemovePublicKeys(doc, value)
+		return applyRemovePublicKeys(doc, value, c.strictKeyRemoval)
 	case patch.AddServiceEndpoints:
 		return applyAddServiceEndpoints(doc, value)
 	case patch.RemoveServiceEndpoints:
@@ -75,7 +103,7 @@ func applyPatch(doc document.Document, p patch.Patch) (document.Document, error)
 	return nil, fmt.Errorf("action '%s' is not supported", action)
 }
 
-func applyJSON(doc document.Document, entry interface{}) (document.Document, error) {
+func (c *DocumentComposer) applyJSON(doc document.Document, entry interface{}) (document.Document, error) {
 	logger.Debugf("applying JSON patch: %v", entry)
 
 	bytes, err := json.Marshal(entry)
@@ -88,6 +116,10 @@ func applyJSON(doc document.Document, entry interface{}) (document.Document, err
 		return nil, err
 	}
 
+	if c.maxJSONPatchOps > 0 && len(jsonPatches) > c.maxJSONPatchOps {
+		return nil, fmt.Errorf("number of JSON patch operations[%d] exceeds maximum[%d]", len(jsonPatches), c.maxJSONPatchOps)
+	}
+
 	docBytes, err := doc.Bytes()
 	if err != nil {
 		return nil, err
@@ -164,11 +196,21 @@ func convertPublicKeys(pubKeys []document.PublicKey) []interface{} {
 }
 
 // remove public keys from the document.
-func applyRemovePublicKeys(doc document.Document, entry interface{}) (document.Document, error) {
+func applyRemovePublicKeys(doc document.Document, entry interface{}, strict bool) (document.Document, error) {
 	logger.Debugf("applying remove public keys patch: %v", entry)
 
 	keysToRemove := sliceToMap(document.StringArray(entry))
 
+	if strict {
+		existingPublicKeysMap := sliceToMapPK(doc.PublicKeys())
+
+		for _, id := range document.StringArray(entry) {
+			if _, ok := existingPublicKeysMap[id]; !ok {
+				return nil, fmt.Errorf("public key id '%s' not found in the document", id)
+			}
+		}
+	}
+
 	var newPublicKeys []interface{}
 
 	for _, key := range doc.PublicKeys() {
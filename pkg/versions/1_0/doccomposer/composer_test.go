@@ -124,6 +124,48 @@ func TestApplyPatches_JSON(t *testing.T) {
 		require.Nil(t, doc)
 		require.Contains(t, err.Error(), "Unexpected kind: invalid")
 	})
+	t.Run("success - add, replace and remove ops", func(t *testing.T) {
+		ietf, err := patch.NewJSONPatch(`[
+			{"op": "add", "path": "/name", "value": "value"},
+			{"op": "add", "path": "/other", "value": "other"},
+			{"op": "replace", "path": "/name", "value": "new value"},
+			{"op": "remove", "path": "/other"}
+		]`)
+		require.NoError(t, err)
+
+		doc, err := documentComposer.ApplyPatches(make(document.Document), []patch.Patch{ietf})
+		require.NoError(t, err)
+		require.Equal(t, "new value", doc["name"])
+		require.NotContains(t, doc, "other")
+	})
+	t.Run("success - number of operations within maximum", func(t *testing.T) {
+		ietf, err := patch.NewJSONPatch(`[{"op": "add", "path": "/name", "value": "value"}]`)
+		require.NoError(t, err)
+
+		doc, err := New(WithMaxJSONPatchOperations(1)).ApplyPatches(make(document.Document), []patch.Patch{ietf})
+		require.NoError(t, err)
+		require.Equal(t, "value", doc["name"])
+	})
+	t.Run("error - number of operations exceeds maximum", func(t *testing.T) {
+		ietf, err := patch.NewJSONPatch(`[
+			{"op": "add", "path": "/name", "value": "value"},
+			{"op": "remove", "path": "/name"}
+		]`)
+		require.NoError(t, err)
+
+		doc, err := New(WithMaxJSONPatchOperations(1)).ApplyPatches(make(document.Document), []patch.Patch{ietf})
+		require.Error(t, err)
+		require.Nil(t, doc)
+		require.Contains(t, err.Error(), "number of JSON patch operations[2] exceeds maximum[1]")
+	})
+	t.Run("error - path doesn't exist", func(t *testing.T) {
+		ietf, err := patch.NewJSONPatch(`[{"op": "remove", "path": "/missing"}]`)
+		require.NoError(t, err)
+
+		doc, err := documentComposer.ApplyPatches(make(document.Document), []patch.Patch{ietf})
+		require.Error(t, err)
+		require.Nil(t, doc)
+	})
 }
 
 func TestApplyPatches_AddPublicKeys(t *testing.T) {
@@ -232,6 +274,57 @@ func TestApplyPatches_RemovePublicKeys(t *testing.T) {
 		diddoc := document.DidDocumentFromJSONLDObject(doc)
 		require.Equal(t, 2, len(diddoc.PublicKeys()))
 	})
+
+	t.Run("success - strict removal of existing key", func(t *testing.T) {
+		doc, err := setupDefaultDoc()
+		require.NoError(t, err)
+
+		removePublicKeys, err := patch.NewRemovePublicKeysPatch(`["key1"]`)
+		require.NoError(t, err)
+
+		doc, err = New(WithStrictKeyRemoval()).ApplyPatches(doc, []patch.Patch{removePublicKeys})
+		require.NoError(t, err)
+
+		diddoc := document.DidDocumentFromJSONLDObject(doc)
+		require.Equal(t, 1, len(diddoc.PublicKeys()))
+		require.Equal(t, "key2", diddoc.PublicKeys()[0].ID())
+	})
+
+	t.Run("error - strict removal of non-existing key", func(t *testing.T) {
+		doc, err := setupDefaultDoc()
+		require.NoError(t, err)
+
+		removePublicKeys, err := patch.NewRemovePublicKeysPatch(`["key1", "key3"]`)
+		require.NoError(t, err)
+
+		result, err := New(WithStrictKeyRemoval()).ApplyPatches(doc, []patch.Patch{removePublicKeys})
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.Contains(t, err.Error(), "public key id 'key3' not found in the document")
+
+		// original document is not modified
+		require.Equal(t, 2, len(document.DidDocumentFromJSONLDObject(doc).PublicKeys()))
+	})
+
+	t.Run("success - patch round trip", func(t *testing.T) {
+		removePublicKeys, err := patch.NewRemovePublicKeysPatch(`["key1"]`)
+		require.NoError(t, err)
+
+		bytes, err := removePublicKeys.Bytes()
+		require.NoError(t, err)
+
+		parsed, err := patch.FromBytes(bytes)
+		require.NoError(t, err)
+
+		doc, err := setupDefaultDoc()
+		require.NoError(t, err)
+
+		doc, err = documentComposer.ApplyPatches(doc, []patch.Patch{parsed})
+		require.NoError(t, err)
+
+		diddoc := document.DidDocumentFromJSONLDObject(doc)
+		require.Equal(t, 1, len(diddoc.PublicKeys()))
+	})
 }
 
 func TestApplyPatches_AddServiceEndpoints(t *testing.T) {
@@ -340,6 +433,26 @@ func TestApplyPatches_RemoveServiceEndpoints(t *testing.T) {
 		diddoc := document.DidDocumentFromJSONLDObject(doc)
 		require.Equal(t, 2, len(diddoc.Services()))
 	})
+
+	t.Run("success - add two services to empty document and remove one", func(t *testing.T) {
+		addServices, err := patch.NewAddServiceEndpointsPatch(`[
+			{"id": "svc1", "type": "type1", "serviceEndpoint": "http://www.example.com/1"},
+			{"id": "svc2", "type": "type2", "serviceEndpoint": "http://www.example.com/2"}
+		]`)
+		require.NoError(t, err)
+
+		removeServices, err := patch.NewRemoveServiceEndpointsPatch(`["svc1"]`)
+		require.NoError(t, err)
+
+		doc, err := documentComposer.ApplyPatches(make(document.Document), []patch.Patch{addServices, removeServices})
+		require.NoError(t, err)
+
+		services := document.DidDocumentFromJSONLDObject(doc).Services()
+		require.Equal(t, 1, len(services))
+		require.Equal(t, "svc2", services[0].ID())
+		require.Equal(t, "type2", services[0].Type())
+		require.Equal(t, "http://www.example.com/2", services[0].ServiceEndpoint())
+	})
 }
 
 func setupDefaultDoc() (document.Document, error) {
@@ -50,6 +50,15 @@ func CreateDocumentMetadata(rm *protocol.ResolutionModel, info protocol.Transfor
 		docMetadata[document.DeactivatedProperty] = rm.Deactivated
 	}
 
+	// created and updated times are known for published documents only
+	if isPublished, ok := published.(bool); ok && isPublished {
+		docMetadata[document.CreatedProperty] = rm.CreatedTime
+
+		if rm.UpdatedTime != 0 {
+			docMetadata[document.UpdatedProperty] = rm.UpdatedTime
+		}
+	}
+
 	canonicalID, ok := info[document.CanonicalIDProperty]
 	if ok {
 		docMetadata[document.CanonicalIDProperty] = canonicalID
@@ -70,6 +70,35 @@ func TestPopulateDocumentMetadata(t *testing.T) {
 		require.Empty(t, methodMetadata[document.UpdateCommitmentProperty])
 	})
 
+	t.Run("success - created and updated times", func(t *testing.T) {
+		internal2 := &protocol.ResolutionModel{Doc: doc, CreatedTime: 10, UpdatedTime: 20}
+
+		info := make(protocol.TransformationInfo)
+		info[document.IDProperty] = "did:abc:123"
+		info[document.PublishedProperty] = true
+
+		documentMetadata, err := CreateDocumentMetadata(internal2, info)
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), documentMetadata[document.CreatedProperty])
+		require.Equal(t, uint64(20), documentMetadata[document.UpdatedProperty])
+
+		// document has not been updated after create
+		internal2.UpdatedTime = 0
+
+		documentMetadata, err = CreateDocumentMetadata(internal2, info)
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), documentMetadata[document.CreatedProperty])
+		require.NotContains(t, documentMetadata, document.UpdatedProperty)
+
+		// times are not known for unpublished documents
+		info[document.PublishedProperty] = false
+
+		documentMetadata, err = CreateDocumentMetadata(internal2, info)
+		require.NoError(t, err)
+		require.NotContains(t, documentMetadata, document.CreatedProperty)
+		require.NotContains(t, documentMetadata, document.UpdatedProperty)
+	})
+
 	t.Run("error - internal document is missing", func(t *testing.T) {
 		info := make(protocol.TransformationInfo)
 		info[document.IDProperty] = "doc:abc:xyz"
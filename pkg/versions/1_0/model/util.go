@@ -12,7 +12,7 @@ import (
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
-	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
 )
 
 // GetAnchoredOperation is utility method for converting operation model into anchored operation.
@@ -79,7 +79,7 @@ func GetUniqueSuffix(model *SuffixDataModel, algs []uint) (string, error) {
 	// only one multihashing algorithm. Later versions may have multiple values for backward compatibility.
 	// At that point (version 2) the spec will hopefully better define how to handle this scenarios:
 	// https://github.com/decentralized-identity/sidetree/issues/965
-	encodedComputedMultihash, err := hashing.CalculateModelMultihash(model, algs[0])
+	encodedComputedMultihash, err := docutil.ComputeUniqueSuffix(model, algs[0])
 	if err != nil {
 		return "", fmt.Errorf("failed to calculate unique suffix: %s", err.Error())
 	}
@@ -7,8 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package operationapplier
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"github.com/trustbloc/edge-core/pkg/log"
 
@@ -17,6 +20,8 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	internal "github.com/trustbloc/sidetree-core-go/pkg/internal/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
 )
 
@@ -29,8 +34,14 @@ type Applier struct {
 	protocol.Protocol
 	OperationParser
 	protocol.DocumentComposer
+
+	signatureCache *lru.Cache
+	verify         func(signedData string, jwk *jws.JWK) error
 }
 
+// Option is an applier option.
+type Option func(opts *Applier)
+
 // OperationParser defines the functions for parsing operations.
 type OperationParser interface {
 	ValidateSuffixData(suffixData *model.SuffixDataModel) error
@@ -44,13 +55,37 @@ type OperationParser interface {
 	ParseSignedDataForRecover(compactJWS string) (*model.RecoverSignedDataModel, error)
 }
 
+// WithSignatureCache enables caching of successful signature verifications for up to size signed data entries.
+// Operations are content addressed so once verified signed data doesn't have to be re-verified during
+// subsequent resolutions. Zero size disables the cache.
+func WithSignatureCache(size int) Option {
+	return func(opts *Applier) {
+		if size <= 0 {
+			opts.signatureCache = nil
+
+			return
+		}
+
+		// error is returned for non-positive size only
+		opts.signatureCache, _ = lru.New(size) //nolint:errcheck
+	}
+}
+
 // New returns a new operation applier for the given protocol.
-func New(p protocol.Protocol, parser OperationParser, dc protocol.DocumentComposer) *Applier {
-	return &Applier{
+func New(p protocol.Protocol, parser OperationParser, dc protocol.DocumentComposer, opts ...Option) *Applier {
+	applier := &Applier{
 		Protocol:         p,
 		OperationParser:  parser,
 		DocumentComposer: dc,
+		verify:           verifyJWS,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(applier)
 	}
+
+	return applier
 }
 
 // Apply applies the given anchored operation.
@@ -81,6 +116,12 @@ func (s *Applier) applyCreateOperation(anchoredOp *operation.AnchoredOperation,
 		return nil, fmt.Errorf("failed to parse create operation in batch mode: %s", err.Error())
 	}
 
+	// unique suffix computed from suffix data (during parsing) has to match claimed unique suffix
+	if anchoredOp.UniqueSuffix != op.UniqueSuffix {
+		return nil, fmt.Errorf("create operation unique suffix[%s] doesn't match unique suffix computed from suffix data[%s]",
+			anchoredOp.UniqueSuffix, op.UniqueSuffix)
+	}
+
 	// from this point any error should advance recovery commitment
 	result := &protocol.ResolutionModel{
 		Doc:                              make(document.Document),
@@ -110,7 +151,7 @@ func (s *Applier) applyCreateOperation(anchoredOp *operation.AnchoredOperation,
 
 	result.UpdateCommitment = op.Delta.UpdateCommitment
 
-	doc, err := s.ApplyPatches(make(document.Document), op.Delta.Patches)
+	doc, err := s.applyPatches(make(document.Document), op.Delta.Patches)
 	if err != nil {
 		logger.Infof("Apply patches failed; advance commitments {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", anchoredOp.UniqueSuffix, anchoredOp.Type, anchoredOp.TransactionTime, anchoredOp.TransactionTime, err)
 
@@ -146,7 +187,7 @@ func (s *Applier) applyUpdateOperation(anchoredOp *operation.AnchoredOperation,
 	}
 
 	// verify signature
-	_, err = internal.VerifyJWS(op.SignedData, signedDataModel.UpdateKey)
+	err = s.verifySignature(op.SignedData, signedDataModel.UpdateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check signature: %s", err.Error())
 	}
@@ -177,7 +218,7 @@ func (s *Applier) applyUpdateOperation(anchoredOp *operation.AnchoredOperation,
 		return result, nil
 	}
 
-	doc, err := s.ApplyPatches(rm.Doc, op.Delta.Patches)
+	doc, err := s.applyPatches(rm.Doc, op.Delta.Patches)
 	if err != nil {
 		logger.Infof("Apply patches failed; advance update commitment {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", op.UniqueSuffix, op.Type, anchoredOp.TransactionTime, anchoredOp.TransactionTime, err)
 
@@ -213,7 +254,7 @@ func (s *Applier) applyDeactivateOperation(anchoredOp *operation.AnchoredOperati
 	}
 
 	// verify signature
-	_, err = internal.VerifyJWS(op.SignedData, signedDataModel.RecoveryKey)
+	err = s.verifySignature(op.SignedData, signedDataModel.RecoveryKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check signature: %s", err.Error())
 	}
@@ -256,7 +297,7 @@ func (s *Applier) applyRecoverOperation(anchoredOp *operation.AnchoredOperation,
 	}
 
 	// verify signature
-	_, err = internal.VerifyJWS(op.SignedData, signedDataModel.RecoveryKey)
+	err = s.verifySignature(op.SignedData, signedDataModel.RecoveryKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check signature: %s", err.Error())
 	}
@@ -298,7 +339,7 @@ func (s *Applier) applyRecoverOperation(anchoredOp *operation.AnchoredOperation,
 		return result, nil
 	}
 
-	doc, err := s.ApplyPatches(make(document.Document), op.Delta.Patches)
+	doc, err := s.applyPatches(make(document.Document), op.Delta.Patches)
 	if err != nil {
 		logger.Infof("Apply patches failed; advance commitments {UniqueSuffix: %s, Type: %s, TransactionTime: %d, TransactionNumber: %d}. Reason: %s", op.UniqueSuffix, op.Type, anchoredOp.TransactionTime, anchoredOp.TransactionTime, err)
 
@@ -310,6 +351,60 @@ func (s *Applier) applyRecoverOperation(anchoredOp *operation.AnchoredOperation,
 	return result, nil
 }
 
+// applyPatches applies patches to the document and checks size of the resulting document against
+// protocol maximum document size.
+func (s *Applier) applyPatches(doc document.Document, patches []patch.Patch) (document.Document, error) {
+	result, err := s.ApplyPatches(doc, patches)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.MaxDocumentSize == 0 {
+		return result, nil
+	}
+
+	docBytes, err := result.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docBytes) > int(s.MaxDocumentSize) {
+		return nil, fmt.Errorf("document size after patches exceeds maximum: size[%d], maximum size[%d]",
+			len(docBytes), s.MaxDocumentSize)
+	}
+
+	return result, nil
+}
+
+func (s *Applier) verifySignature(signedData string, jwk *jws.JWK) error {
+	if s.signatureCache == nil {
+		return s.verify(signedData, jwk)
+	}
+
+	// signing key is part of signed data so signed data hash identifies verification result
+	hash := sha256.Sum256([]byte(signedData))
+	key := hex.EncodeToString(hash[:])
+
+	if s.signatureCache.Contains(key) {
+		return nil
+	}
+
+	err := s.verify(signedData, jwk)
+	if err != nil {
+		return err
+	}
+
+	s.signatureCache.Add(key, true)
+
+	return nil
+}
+
+func verifyJWS(signedData string, jwk *jws.JWK) error {
+	_, err := internal.VerifyJWS(signedData, jwk)
+
+	return err
+}
+
 func (s *Applier) verifyAnchoringTimeRange(from, until int64, anchor uint64) error {
 	if from == 0 && until == 0 {
 		// from and until are not specified - nothing to check
@@ -12,6 +12,7 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
 	"testing"
 	"time"
@@ -23,8 +24,10 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-core-go/pkg/internal/signutil"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 	"github.com/trustbloc/sidetree-core-go/pkg/patch"
 	"github.com/trustbloc/sidetree-core-go/pkg/util/ecsigner"
@@ -169,6 +172,35 @@ func TestApplier_Apply(t *testing.T) {
 		require.Contains(t, err.Error(), "failed to parse create operation in batch mode")
 	})
 
+	t.Run("success - unique suffix is derived from suffix data", func(t *testing.T) {
+		op, err := getCreateOperation(recoveryKey, updateKey)
+		require.NoError(t, err)
+
+		uniqueSuffix, err := docutil.ComputeUniqueSuffix(op.SuffixData, sha2_256)
+		require.NoError(t, err)
+
+		createOp := getAnchoredOperation(op)
+		require.Equal(t, uniqueSuffix, createOp.UniqueSuffix)
+
+		rm, err := New(p, parser, dc).Apply(createOp, &protocol.ResolutionModel{})
+		require.NoError(t, err)
+		require.NotNil(t, rm.Doc)
+	})
+
+	t.Run("error - tampered unique suffix", func(t *testing.T) {
+		createOp, err := getAnchoredCreateOperation(recoveryKey, updateKey)
+		require.NoError(t, err)
+
+		computedSuffix := createOp.UniqueSuffix
+		createOp.UniqueSuffix = "tampered"
+
+		rm, err := New(p, parser, dc).Apply(createOp, &protocol.ResolutionModel{})
+		require.Error(t, err)
+		require.Nil(t, rm)
+		require.Contains(t, err.Error(), fmt.Sprintf(
+			"create operation unique suffix[tampered] doesn't match unique suffix computed from suffix data[%s]", computedSuffix))
+	})
+
 	t.Run("error - apply patches (document composer) error", func(t *testing.T) {
 		applier := New(p, parser, &mockDocComposer{Err: errors.New("document composer error")})
 
@@ -183,6 +215,155 @@ func TestApplier_Apply(t *testing.T) {
 	})
 }
 
+func TestApplier_SignatureCache(t *testing.T) {
+	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	updateKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, e)
+
+	createOp, err := getAnchoredCreateOperation(recoveryKey, updateKey)
+	require.NoError(t, err)
+
+	updateOp, _, err := getAnchoredUpdateOperation(updateKey, createOp.UniqueSuffix, 1)
+	require.NoError(t, err)
+
+	recoverOp, _, err := getAnchoredRecoverOperation(recoveryKey, updateKey, createOp.UniqueSuffix, 2)
+	require.NoError(t, err)
+
+	// resolves document multiple times and returns number of signature verifications
+	resolve := func(applier *Applier, times int) int {
+		verifications := 0
+
+		applier.verify = func(signedData string, jwk *jws.JWK) error {
+			verifications++
+
+			return verifyJWS(signedData, jwk)
+		}
+
+		for i := 0; i < times; i++ {
+			rm, err := applier.Apply(createOp, &protocol.ResolutionModel{})
+			require.NoError(t, err)
+
+			_, err = applier.Apply(updateOp, rm)
+			require.NoError(t, err)
+
+			_, err = applier.Apply(recoverOp, rm)
+			require.NoError(t, err)
+		}
+
+		return verifications
+	}
+
+	t.Run("success - verified once per unique operation", func(t *testing.T) {
+		applier := New(p, parser, dc, WithSignatureCache(100))
+
+		require.Equal(t, 2, resolve(applier, 3))
+	})
+
+	t.Run("success - cache disabled", func(t *testing.T) {
+		applier := New(p, parser, dc, WithSignatureCache(0))
+		require.Nil(t, applier.signatureCache)
+
+		require.Equal(t, 6, resolve(applier, 3))
+	})
+
+	t.Run("error - failed verification is not cached", func(t *testing.T) {
+		applier := New(p, parser, dc, WithSignatureCache(100))
+
+		verifications := 0
+		applier.verify = func(signedData string, jwk *jws.JWK) error {
+			verifications++
+
+			return errors.New("verify error")
+		}
+
+		rm, err := applier.Apply(createOp, &protocol.ResolutionModel{})
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			result, err := applier.Apply(updateOp, rm)
+			require.Error(t, err)
+			require.Nil(t, result)
+			require.Contains(t, err.Error(), "failed to check signature: verify error")
+		}
+
+		require.Equal(t, 2, verifications)
+	})
+}
+
+func TestApplier_MaxDocumentSize(t *testing.T) {
+	recoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	updateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	createOp, err := getAnchoredCreateOperation(recoveryKey, updateKey)
+	require.NoError(t, err)
+
+	updateOp, _, err := getAnchoredUpdateOperation(updateKey, createOp.UniqueSuffix, 1)
+	require.NoError(t, err)
+
+	created, err := New(p, parser, dc).Apply(createOp, &protocol.ResolutionModel{})
+	require.NoError(t, err)
+
+	createdBytes, err := created.Doc.Bytes()
+	require.NoError(t, err)
+
+	t.Run("success - no maximum document size", func(t *testing.T) {
+		applier := New(p, parser, dc)
+
+		rm, err := applier.Apply(updateOp, created)
+		require.NoError(t, err)
+		require.Equal(t, "special1", rm.Doc["test"])
+	})
+
+	t.Run("success - document size within maximum", func(t *testing.T) {
+		pWithMaxSize := p
+		pWithMaxSize.MaxDocumentSize = uint(len(createdBytes)) + 100
+
+		applier := New(pWithMaxSize, parser, dc)
+
+		rm, err := applier.Apply(createOp, &protocol.ResolutionModel{})
+		require.NoError(t, err)
+		require.Equal(t, created.Doc, rm.Doc)
+
+		rm, err = applier.Apply(updateOp, rm)
+		require.NoError(t, err)
+		require.Equal(t, "special1", rm.Doc["test"])
+	})
+
+	t.Run("success - patches that exceed maximum document size are not applied", func(t *testing.T) {
+		pWithMaxSize := p
+		pWithMaxSize.MaxDocumentSize = uint(len(createdBytes))
+
+		applier := New(pWithMaxSize, parser, dc)
+
+		rm, err := applier.Apply(createOp, &protocol.ResolutionModel{})
+		require.NoError(t, err)
+		require.Equal(t, created.Doc, rm.Doc)
+
+		// update operation is applied but document is not changed (same as for any other invalid patch)
+		result, err := applier.Apply(updateOp, rm)
+		require.NoError(t, err)
+		require.Equal(t, created.Doc, result.Doc)
+		require.NotEqual(t, rm.UpdateCommitment, result.UpdateCommitment)
+		require.Equal(t, updateOp.TransactionTime, result.LastOperationTransactionTime)
+	})
+
+	t.Run("success - create patches that exceed maximum document size are not applied", func(t *testing.T) {
+		pWithMaxSize := p
+		pWithMaxSize.MaxDocumentSize = uint(len(createdBytes)) - 1
+
+		applier := New(pWithMaxSize, parser, dc)
+
+		rm, err := applier.Apply(createOp, &protocol.ResolutionModel{})
+		require.NoError(t, err)
+		require.Empty(t, rm.Doc)
+	})
+}
+
 func TestUpdateDocument(t *testing.T) {
 	recoveryKey, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	require.NoError(t, e)
@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
@@ -68,6 +70,23 @@ func (p *Parser) ParseCreateOperation(request []byte, batch bool) (*model.Operat
 	}, nil
 }
 
+// ParseCreateOperationReader will read create operation request from the given reader and parse it.
+// At most maximum operation size bytes are read; larger requests are rejected. Request is buffered
+// since original request is part of the parsed operation.
+func (p *Parser) ParseCreateOperationReader(r io.Reader, batch bool) (*model.Operation, error) {
+	request, err := ioutil.ReadAll(io.LimitReader(r, int64(p.MaxOperationSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read create request: %s", err.Error())
+	}
+
+	if len(request) > int(p.MaxOperationSize) {
+		return nil, newCategorizedError(ErrMalformedRequest,
+			fmt.Errorf("operation size exceeds maximum operation size[%d]", p.MaxOperationSize))
+	}
+
+	return p.ParseCreateOperation(request, batch)
+}
+
 // parseCreateRequest parses a 'create' request.
 func (p *Parser) parseCreateRequest(payload []byte) (*model.CreateRequest, error) {
 	schema := &model.CreateRequest{}
@@ -89,6 +108,10 @@ func (p *Parser) ValidateDelta(delta *model.DeltaModel) error {
 		return errors.New("missing patches")
 	}
 
+	if p.MaxPatchesPerDelta > 0 && len(delta.Patches) > int(p.MaxPatchesPerDelta) {
+		return fmt.Errorf("number of patches[%d] exceeds maximum number of patches per delta[%d]", len(delta.Patches), p.MaxPatchesPerDelta)
+	}
+
 	for _, ptch := range delta.Patches {
 		action, err := ptch.GetAction()
 		if err != nil {
@@ -7,13 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package operationparser
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
 	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
@@ -159,6 +165,54 @@ func TestParseCreateOperation(t *testing.T) {
 	})
 }
 
+func TestParseCreateOperationReader(t *testing.T) {
+	payload, err := getCreateRequestBytes()
+	require.NoError(t, err)
+
+	p := protocol.Protocol{
+		MaxOperationHashLength: 100,
+		MaxDeltaSize:           maxDeltaSize,
+		MaxOperationSize:       uint(len(payload)),
+		MultihashAlgorithms:    []uint{sha2_256},
+		Patches:                []string{"replace", "add-public-keys", "remove-public-keys", "add-services", "remove-services", "ietf-json-patch"},
+	}
+
+	parser := New(p)
+
+	t.Run("success", func(t *testing.T) {
+		op, err := parser.ParseCreateOperationReader(bytes.NewReader(payload), false)
+		require.NoError(t, err)
+		require.Equal(t, operation.TypeCreate, op.Type)
+		require.Equal(t, payload, op.OperationBuffer)
+
+		expected, err := parser.ParseCreateOperation(payload, false)
+		require.NoError(t, err)
+		require.Equal(t, expected, op)
+	})
+
+	t.Run("error - request exceeds maximum operation size", func(t *testing.T) {
+		op, err := parser.ParseCreateOperationReader(bytes.NewReader(append(payload, ' ')), false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), fmt.Sprintf("operation size exceeds maximum operation size[%d]", len(payload)))
+		require.True(t, errors.Is(err, ErrMalformedRequest))
+	})
+
+	t.Run("error - read error", func(t *testing.T) {
+		op, err := parser.ParseCreateOperationReader(iotest.ErrReader(errors.New("read error")), false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "failed to read create request: read error")
+	})
+
+	t.Run("error - missing suffix data", func(t *testing.T) {
+		op, err := parser.ParseCreateOperationReader(strings.NewReader("{}"), false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "missing suffix data")
+	})
+}
+
 func TestValidateSuffixData(t *testing.T) {
 	p := protocol.Protocol{
 		MaxOperationHashLength: maxHashLength,
@@ -243,6 +297,72 @@ func TestValidateDelta(t *testing.T) {
 		require.Contains(t, err.Error(), "delta size[336] exceeds maximum delta size[50]")
 	})
 
+	t.Run("success - delta size equals max delta size", func(t *testing.T) {
+		delta, err := getDelta()
+		require.NoError(t, err)
+
+		canonicalDelta, err := canonicalizer.MarshalCanonical(delta)
+		require.NoError(t, err)
+
+		deltaSize := uint(len(canonicalDelta))
+
+		parserAtLimit := New(protocol.Protocol{
+			MaxOperationHashLength: maxHashLength,
+			MaxDeltaSize:           deltaSize,
+			MultihashAlgorithms:    []uint{sha2_256},
+			Patches:                patches,
+		})
+
+		err = parserAtLimit.ValidateDelta(delta)
+		require.NoError(t, err)
+
+		parserOverLimit := New(protocol.Protocol{
+			MaxOperationHashLength: maxHashLength,
+			MaxDeltaSize:           deltaSize - 1,
+			MultihashAlgorithms:    []uint{sha2_256},
+			Patches:                patches,
+		})
+
+		err = parserOverLimit.ValidateDelta(delta)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), fmt.Sprintf("delta size[%d] exceeds maximum delta size[%d]", deltaSize, deltaSize-1))
+	})
+
+	t.Run("success - number of patches equals max patches per delta", func(t *testing.T) {
+		delta, err := getDelta()
+		require.NoError(t, err)
+
+		parserWithMaxPatches := New(protocol.Protocol{
+			MaxOperationHashLength: maxHashLength,
+			MaxDeltaSize:           maxDeltaSize,
+			MultihashAlgorithms:    []uint{sha2_256},
+			Patches:                patches,
+			MaxPatchesPerDelta:     uint(len(delta.Patches)),
+		})
+
+		err = parserWithMaxPatches.ValidateDelta(delta)
+		require.NoError(t, err)
+	})
+
+	t.Run("error - number of patches exceeds max patches per delta", func(t *testing.T) {
+		delta, err := getDelta()
+		require.NoError(t, err)
+
+		delta.Patches = append(delta.Patches, delta.Patches[0])
+
+		parserWithMaxPatches := New(protocol.Protocol{
+			MaxOperationHashLength: maxHashLength,
+			MaxDeltaSize:           maxDeltaSize,
+			MultihashAlgorithms:    []uint{sha2_256},
+			Patches:                patches,
+			MaxPatchesPerDelta:     1,
+		})
+
+		err = parserWithMaxPatches.ValidateDelta(delta)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "number of patches[2] exceeds maximum number of patches per delta[1]")
+	})
+
 	t.Run("invalid next update commitment hash", func(t *testing.T) {
 		delta, err := getDelta()
 		require.NoError(t, err)
@@ -86,6 +86,34 @@ func TestParseDeactivateOperation(t *testing.T) {
 		require.Contains(t, err.Error(), "missing signed data")
 		require.Nil(t, op)
 	})
+	t.Run("missing reveal value", func(t *testing.T) {
+		deactivateRequest, err := getDefaultDeactivateRequest()
+		require.NoError(t, err)
+
+		deactivateRequest.RevealValue = ""
+		request, err := json.Marshal(deactivateRequest)
+		require.NoError(t, err)
+
+		op, err := parser.ParseDeactivateOperation(request, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "reveal value is not computed with the required hash algorithms: [18]")
+	})
+	t.Run("reveal value doesn't match recovery key", func(t *testing.T) {
+		deactivateRequest, err := getDefaultDeactivateRequest()
+		require.NoError(t, err)
+
+		deactivateRequest.RevealValue, err = commitment.GetRevealValue(&jws.JWK{Kty: "kty", Crv: "crv", X: "other"}, sha2_256)
+		require.NoError(t, err)
+
+		request, err := json.Marshal(deactivateRequest)
+		require.NoError(t, err)
+
+		op, err := parser.ParseDeactivateOperation(request, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "canonicalized recovery public key hash doesn't match reveal value")
+	})
 	t.Run("parse request", func(t *testing.T) {
 		request, err := json.Marshal("invalidJSON")
 		require.NoError(t, err)
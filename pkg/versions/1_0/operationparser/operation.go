@@ -72,6 +72,37 @@ var ErrOperationExpired = errors.New("operation expired")
 // ErrOperationEarly is thrown if anchor from time is greater then reference time(e.g. server time or anchoring time).
 var ErrOperationEarly = errors.New("operation early")
 
+// ErrMalformedRequest is returned if operation request is malformed (e.g. invalid JSON, missing or invalid fields).
+var ErrMalformedRequest = errors.New("malformed request")
+
+// ErrInvalidSignedData is returned if operation signed data cannot be parsed or validated.
+var ErrInvalidSignedData = errors.New("invalid signed data")
+
+// ErrPatchValidation is returned if operation delta (patches, update commitment) fails validation.
+var ErrPatchValidation = errors.New("patch validation failed")
+
+// categorizedError is an error that matches (errors.Is) its category while keeping the original message.
+type categorizedError struct {
+	category error
+	err      error
+}
+
+func newCategorizedError(category, err error) error {
+	return &categorizedError{category: category, err: err}
+}
+
+func (e *categorizedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categorizedError) Is(target error) bool {
+	return target == e.category
+}
+
+func (e *categorizedError) Unwrap() error {
+	return e.err
+}
+
 // TimeValidator validates earliest and expiry time for an operation against server time.
 type TimeValidator interface {
 	Validate(from, until int64) error
@@ -33,6 +33,22 @@ func TestAddServiceEndpointsPatch(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "service id is missing")
 	})
+	t.Run("error - service is missing type", func(t *testing.T) {
+		p, err := patch.NewAddServiceEndpointsPatch(`[{"id": "sds1", "serviceEndpoint": "http://some-cloud.com/hub"}]`)
+		require.NoError(t, err)
+
+		err = NewAddServicesValidator().Validate(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service type is missing")
+	})
+	t.Run("error - service is missing service endpoint", func(t *testing.T) {
+		p, err := patch.NewAddServiceEndpointsPatch(`[{"id": "sds1", "type": "SecureDataStore"}]`)
+		require.NoError(t, err)
+
+		err = NewAddServicesValidator().Validate(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service endpoint is missing")
+	})
 }
 
 const addServiceEndpoints = `{
@@ -17,6 +17,9 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/patch"
 )
 
+// operations defined by RFC 6902.
+var jsonPatchOps = []string{"add", "remove", "replace", "move", "copy", "test"}
+
 // NewJSONValidator creates new validator.
 func NewJSONValidator() *JSONValidator {
 	return &JSONValidator{}
@@ -53,6 +56,17 @@ func validateJSONPatches(patches []byte) error {
 	}
 
 	for _, p := range jsonPatches {
+		var op string
+		if opMsg, ok := p["op"]; ok {
+			if err := json.Unmarshal(*opMsg, &op); err != nil {
+				return fmt.Errorf("%s: invalid op", patch.JSONPatch)
+			}
+		}
+
+		if !contains(jsonPatchOps, op) {
+			return fmt.Errorf("%s: invalid op '%s'", patch.JSONPatch, op)
+		}
+
 		pathMsg, ok := p["path"]
 		if !ok {
 			return fmt.Errorf("%s: path not found", patch.JSONPatch)
@@ -63,6 +77,11 @@ func validateJSONPatches(patches []byte) error {
 			return fmt.Errorf("%s: invalid path", patch.JSONPatch)
 		}
 
+		// path is JSON pointer (RFC 6901): empty string or string starting with '/'
+		if path != "" && !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("%s: invalid path '%s'", patch.JSONPatch, path)
+		}
+
 		if strings.HasPrefix(path, "/"+document.ServiceProperty) {
 			return fmt.Errorf("%s: cannot modify services", patch.JSONPatch)
 		}
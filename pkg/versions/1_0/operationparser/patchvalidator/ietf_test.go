@@ -40,6 +40,41 @@ func TestIETFPatch(t *testing.T) {
 		require.Error(t, err)
 		require.Equal(t, err.Error(), "ietf-json-patch: cannot modify public keys")
 	})
+	t.Run("success - add, remove and replace ops", func(t *testing.T) {
+		p, err := patch.NewJSONPatch(`[
+			{"op": "add", "path": "/name", "value": "value"},
+			{"op": "replace", "path": "/name", "value": "new value"},
+			{"op": "remove", "path": "/name"}
+		]`)
+		require.NoError(t, err)
+
+		err = NewJSONValidator().Validate(p)
+		require.NoError(t, err)
+	})
+	t.Run("error - invalid op", func(t *testing.T) {
+		p, err := patch.NewJSONPatch(`[{"op": "invalid", "path": "/name", "value": "value"}]`)
+		require.NoError(t, err)
+
+		err = NewJSONValidator().Validate(p)
+		require.Error(t, err)
+		require.Equal(t, err.Error(), "ietf-json-patch: invalid op 'invalid'")
+	})
+	t.Run("error - op is not a string", func(t *testing.T) {
+		p, err := patch.NewJSONPatch(`[{"op": 1, "path": "/name", "value": "value"}]`)
+		require.NoError(t, err)
+
+		err = NewJSONValidator().Validate(p)
+		require.Error(t, err)
+		require.Equal(t, err.Error(), "ietf-json-patch: invalid op")
+	})
+	t.Run("error - malformed path", func(t *testing.T) {
+		p, err := patch.NewJSONPatch(`[{"op": "replace", "path": "name", "value": "value"}]`)
+		require.NoError(t, err)
+
+		err = NewJSONValidator().Validate(p)
+		require.Error(t, err)
+		require.Equal(t, err.Error(), "ietf-json-patch: invalid path 'name'")
+	})
 	t.Run("error missing patches", func(t *testing.T) {
 		p := make(patch.Patch)
 		p[patch.ActionKey] = patch.JSONPatch
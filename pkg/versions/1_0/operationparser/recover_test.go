@@ -73,6 +73,32 @@ func TestParseRecoverOperation(t *testing.T) {
 		require.Nil(t, op)
 		require.Contains(t, err.Error(), "missing did suffix")
 	})
+	t.Run("missing reveal value", func(t *testing.T) {
+		recoverRequest, err := getDefaultRecoverRequest()
+		require.NoError(t, err)
+
+		recoverRequest.RevealValue = ""
+		request, err := json.Marshal(recoverRequest)
+		require.NoError(t, err)
+
+		op, err := parser.ParseRecoverOperation(request, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "reveal value is not computed with the required hash algorithms: [18]")
+	})
+	t.Run("missing delta", func(t *testing.T) {
+		recoverRequest, err := getDefaultRecoverRequest()
+		require.NoError(t, err)
+
+		recoverRequest.Delta = nil
+		request, err := json.Marshal(recoverRequest)
+		require.NoError(t, err)
+
+		op, err := parser.ParseRecoverOperation(request, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "missing delta")
+	})
 	t.Run("parse patch data error", func(t *testing.T) {
 		recoverRequest, err := getDefaultRecoverRequest()
 		require.NoError(t, err)
@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
@@ -17,15 +19,30 @@ import (
 )
 
 // ParseUpdateOperation will parse update operation.
+// Returned errors (except for anchor time validation errors) match (errors.Is) ErrMalformedRequest,
+// ErrInvalidSignedData or ErrPatchValidation.
 func (p *Parser) ParseUpdateOperation(request []byte, batch bool) (*model.Operation, error) {
+	// check maximum operation size against protocol before parsing
+	if len(request) > int(p.MaxOperationSize) {
+		return nil, newCategorizedError(ErrMalformedRequest,
+			fmt.Errorf("operation size[%d] exceeds maximum operation size[%d]", len(request), p.MaxOperationSize))
+	}
+
 	schema, err := p.parseUpdateRequest(request)
 	if err != nil {
-		return nil, err
+		return nil, newCategorizedError(ErrMalformedRequest, err)
+	}
+
+	// check maximum delta size against protocol before parsing signed data
+	if schema.Delta != nil {
+		if err := p.validateDeltaSize(schema.Delta); err != nil {
+			return nil, newCategorizedError(ErrPatchValidation, err)
+		}
 	}
 
 	signedData, err := p.ParseSignedDataForUpdate(schema.SignedData)
 	if err != nil {
-		return nil, err
+		return nil, newCategorizedError(ErrInvalidSignedData, err)
 	}
 
 	if !batch {
@@ -38,18 +55,20 @@ func (p *Parser) ParseUpdateOperation(request []byte, batch bool) (*model.Operat
 
 		err = p.ValidateDelta(schema.Delta)
 		if err != nil {
-			return nil, err
+			return nil, newCategorizedError(ErrPatchValidation, err)
 		}
 
 		err = p.validateCommitment(signedData.UpdateKey, schema.Delta.UpdateCommitment)
 		if err != nil {
-			return nil, fmt.Errorf("calculate current commitment: %s", err.Error())
+			return nil, newCategorizedError(ErrPatchValidation,
+				fmt.Errorf("calculate current commitment: %s", err.Error()))
 		}
 	}
 
 	err = hashing.IsValidModelMultihash(signedData.UpdateKey, schema.RevealValue)
 	if err != nil {
-		return nil, fmt.Errorf("canonicalized update public key hash doesn't match reveal value: %s", err.Error())
+		return nil, newCategorizedError(ErrMalformedRequest,
+			fmt.Errorf("canonicalized update public key hash doesn't match reveal value: %s", err.Error()))
 	}
 
 	return &model.Operation{
@@ -62,6 +81,23 @@ func (p *Parser) ParseUpdateOperation(request []byte, batch bool) (*model.Operat
 	}, nil
 }
 
+// ParseUpdateOperationReader will read update operation request from the given reader and parse it.
+// At most maximum operation size bytes are read; larger requests are rejected. Request is buffered
+// since original request is part of the parsed operation.
+func (p *Parser) ParseUpdateOperationReader(r io.Reader, batch bool) (*model.Operation, error) {
+	request, err := ioutil.ReadAll(io.LimitReader(r, int64(p.MaxOperationSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read update request: %s", err.Error())
+	}
+
+	if len(request) > int(p.MaxOperationSize) {
+		return nil, newCategorizedError(ErrMalformedRequest,
+			fmt.Errorf("operation size exceeds maximum operation size[%d]", p.MaxOperationSize))
+	}
+
+	return p.ParseUpdateOperation(request, batch)
+}
+
 func (p *Parser) parseUpdateRequest(payload []byte) (*model.UpdateRequest, error) {
 	schema := &model.UpdateRequest{}
 	err := json.Unmarshal(payload, schema)
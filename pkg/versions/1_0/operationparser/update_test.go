@@ -7,14 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package operationparser
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
 	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-core-go/pkg/internal/signutil"
 	"github.com/trustbloc/sidetree-core-go/pkg/jws"
@@ -22,10 +28,13 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
 )
 
+const sha2_512 = 19
+
 func TestParseUpdateOperation(t *testing.T) {
 	p := protocol.Protocol{
 		MaxOperationHashLength: maxHashLength,
 		MaxDeltaSize:           maxDeltaSize,
+		MaxOperationSize:       maxOperationSize,
 		MultihashAlgorithms:    []uint{sha2_256},
 		SignatureAlgorithms:    []string{"alg"},
 		KeyAlgorithms:          []string{"crv"},
@@ -53,6 +62,7 @@ func TestParseUpdateOperation(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, schema)
 		require.Contains(t, err.Error(), "unexpected end of JSON input")
+		require.True(t, errors.Is(err, ErrMalformedRequest))
 	})
 	t.Run("validate update request error", func(t *testing.T) {
 		req, err := getDefaultUpdateRequest()
@@ -66,6 +76,7 @@ func TestParseUpdateOperation(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, schema)
 		require.Contains(t, err.Error(), "missing did suffix")
+		require.True(t, errors.Is(err, ErrMalformedRequest))
 	})
 	t.Run("invalid next update commitment hash", func(t *testing.T) {
 		delta, err := getUpdateDelta()
@@ -82,6 +93,61 @@ func TestParseUpdateOperation(t *testing.T) {
 		require.Nil(t, schema)
 		require.Contains(t, err.Error(),
 			"update commitment is not computed with the required hash algorithms: [18]")
+		require.True(t, errors.Is(err, ErrPatchValidation))
+	})
+	t.Run("success - valid next update commitment hash", func(t *testing.T) {
+		delta, err := getUpdateDelta()
+		require.NoError(t, err)
+		delta.UpdateCommitment = computeMultihash([]byte("nextUpdateReveal"))
+
+		req, err := getUpdateRequest(delta)
+		require.NoError(t, err)
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		op, err := parser.ParseUpdateOperation(payload, false)
+		require.NoError(t, err)
+		require.Equal(t, delta.UpdateCommitment, op.Delta.UpdateCommitment)
+	})
+	t.Run("error - truncated next update commitment hash", func(t *testing.T) {
+		mh, err := hashing.ComputeMultihash(sha2_256, []byte("nextUpdateReveal"))
+		require.NoError(t, err)
+
+		delta, err := getUpdateDelta()
+		require.NoError(t, err)
+		delta.UpdateCommitment = encoder.EncodeToString(mh[:len(mh)-1])
+
+		req, err := getUpdateRequest(delta)
+		require.NoError(t, err)
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		op, err := parser.ParseUpdateOperation(payload, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(),
+			"update commitment is not computed with the required hash algorithms: [18]")
+		require.True(t, errors.Is(err, ErrPatchValidation))
+	})
+	t.Run("error - next update commitment hash computed with wrong algorithm", func(t *testing.T) {
+		mh, err := hashing.ComputeMultihash(sha2_512, []byte("nextUpdateReveal"))
+		require.NoError(t, err)
+
+		delta, err := getUpdateDelta()
+		require.NoError(t, err)
+		delta.UpdateCommitment = encoder.EncodeToString(mh)
+
+		req, err := getUpdateRequest(delta)
+		require.NoError(t, err)
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		op, err := parser.ParseUpdateOperation(payload, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(),
+			"update commitment is not computed with the required hash algorithms: [18]")
+		require.True(t, errors.Is(err, ErrPatchValidation))
 	})
 	t.Run("invalid signed data", func(t *testing.T) {
 		delta, err := getUpdateDelta()
@@ -98,6 +164,7 @@ func TestParseUpdateOperation(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, schema)
 		require.Contains(t, err.Error(), "invalid JWS compact format")
+		require.True(t, errors.Is(err, ErrInvalidSignedData))
 	})
 	t.Run("parse signed data error - unmarshal failed", func(t *testing.T) {
 		req, err := getDefaultUpdateRequest()
@@ -113,6 +180,7 @@ func TestParseUpdateOperation(t *testing.T) {
 		op, err := parser.ParseUpdateOperation(request, false)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to unmarshal signed data model for update")
+		require.True(t, errors.Is(err, ErrInvalidSignedData))
 		require.Nil(t, op)
 	})
 
@@ -135,6 +203,209 @@ func TestParseUpdateOperation(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, schema)
 		require.Contains(t, err.Error(), "re-using public keys for commitment is not allowed")
+		require.True(t, errors.Is(err, ErrPatchValidation))
+	})
+
+	t.Run("error - patch validation", func(t *testing.T) {
+		delta, err := getUpdateDelta()
+		require.NoError(t, err)
+
+		delta.Patches = nil
+
+		req, err := getUpdateRequest(delta)
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		schema, err := parser.ParseUpdateOperation(payload, false)
+		require.Error(t, err)
+		require.Nil(t, schema)
+		require.Contains(t, err.Error(), "missing patches")
+		require.True(t, errors.Is(err, ErrPatchValidation))
+		require.False(t, errors.Is(err, ErrMalformedRequest))
+	})
+
+	t.Run("error - request exceeds maximum operation size", func(t *testing.T) {
+		payload, err := getUpdateRequestBytes()
+		require.NoError(t, err)
+
+		pc := p
+		pc.MaxOperationSize = uint(len(payload) - 1)
+
+		schema, err := New(pc).ParseUpdateOperation(payload, true)
+		require.Error(t, err)
+		require.Nil(t, schema)
+		require.Contains(t, err.Error(),
+			fmt.Sprintf("operation size[%d] exceeds maximum operation size[%d]", len(payload), len(payload)-1))
+		require.True(t, errors.Is(err, ErrMalformedRequest))
+	})
+
+	t.Run("error - delta exceeds maximum delta size", func(t *testing.T) {
+		payload, err := getUpdateRequestBytes()
+		require.NoError(t, err)
+
+		pc := p
+		pc.MaxDeltaSize = 50
+
+		schema, err := New(pc).ParseUpdateOperation(payload, true)
+		require.Error(t, err)
+		require.Nil(t, schema)
+		require.Contains(t, err.Error(), "exceeds maximum delta size[50]")
+		require.True(t, errors.Is(err, ErrPatchValidation))
+	})
+
+	t.Run("error - anchor time validation error is not wrapped", func(t *testing.T) {
+		payload, err := getUpdateRequestBytes()
+		require.NoError(t, err)
+
+		timeParser := New(p, WithAnchorTimeValidator(&mockTimeValidator{Err: ErrOperationExpired}))
+
+		schema, err := timeParser.ParseUpdateOperation(payload, false)
+		require.Error(t, err)
+		require.Nil(t, schema)
+		require.Equal(t, ErrOperationExpired, err)
+	})
+}
+
+func TestParseUpdateOperationSignedData(t *testing.T) {
+	p := protocol.Protocol{
+		MaxOperationHashLength: maxHashLength,
+		MaxDeltaSize:           maxDeltaSize,
+		MaxOperationSize:       maxOperationSize,
+		MultihashAlgorithms:    []uint{sha2_256},
+		SignatureAlgorithms:    []string{"alg"},
+		KeyAlgorithms:          []string{"crv"},
+		Patches:                []string{"add-public-keys", "remove-public-keys", "add-services", "remove-services", "ietf-json-patch"},
+	}
+
+	parser := New(p)
+
+	parseWithSignedModel := func(signedModel interface{}) (*model.Operation, error) {
+		req, err := getDefaultUpdateRequest()
+		require.NoError(t, err)
+
+		req.SignedData, err = signutil.SignModel(signedModel, NewMockSigner())
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		return parser.ParseUpdateOperation(payload, false)
+	}
+
+	t.Run("success - structurally valid signed data", func(t *testing.T) {
+		req, err := getDefaultUpdateRequest()
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		op, err := parser.ParseUpdateOperation(payload, false)
+		require.NoError(t, err)
+		require.Equal(t, req.SignedData, op.SignedData)
+	})
+
+	t.Run("error - missing signed data", func(t *testing.T) {
+		req, err := getDefaultUpdateRequest()
+		require.NoError(t, err)
+
+		req.SignedData = ""
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		op, err := parser.ParseUpdateOperation(payload, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "missing signed data")
+		require.True(t, errors.Is(err, ErrMalformedRequest))
+	})
+
+	t.Run("error - signed data is not compact JWS", func(t *testing.T) {
+		req, err := getDefaultUpdateRequest()
+		require.NoError(t, err)
+
+		req.SignedData = "not-a-jws"
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		op, err := parser.ParseUpdateOperation(payload, false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "failed to parse signed data: invalid JWS compact format")
+		require.True(t, errors.Is(err, ErrInvalidSignedData))
+	})
+
+	t.Run("error - signed data payload is missing update key", func(t *testing.T) {
+		delta, err := getUpdateDelta()
+		require.NoError(t, err)
+
+		deltaHash, err := hashing.CalculateModelMultihash(delta, sha2_256)
+		require.NoError(t, err)
+
+		op, err := parseWithSignedModel(model.UpdateSignedDataModel{DeltaHash: deltaHash})
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "validate signed data for update: missing signing key")
+		require.True(t, errors.Is(err, ErrInvalidSignedData))
+	})
+
+	t.Run("error - signed data payload is missing delta hash", func(t *testing.T) {
+		op, err := parseWithSignedModel(model.UpdateSignedDataModel{UpdateKey: testJWK})
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "validate signed data for update: delta hash is not computed with the required hash algorithms")
+		require.True(t, errors.Is(err, ErrInvalidSignedData))
+	})
+}
+
+func TestParseUpdateOperationReader(t *testing.T) {
+	payload, err := getUpdateRequestBytes()
+	require.NoError(t, err)
+
+	p := protocol.Protocol{
+		MaxOperationHashLength: maxHashLength,
+		MaxDeltaSize:           maxDeltaSize,
+		MaxOperationSize:       uint(len(payload)),
+		MultihashAlgorithms:    []uint{sha2_256},
+		SignatureAlgorithms:    []string{"alg"},
+		KeyAlgorithms:          []string{"crv"},
+		Patches:                []string{"add-public-keys", "remove-public-keys", "add-services", "remove-services", "ietf-json-patch"},
+	}
+
+	parser := New(p)
+
+	t.Run("success", func(t *testing.T) {
+		op, err := parser.ParseUpdateOperationReader(bytes.NewReader(payload), false)
+		require.NoError(t, err)
+		require.Equal(t, operation.TypeUpdate, op.Type)
+		require.Equal(t, payload, op.OperationBuffer)
+
+		expected, err := parser.ParseUpdateOperation(payload, false)
+		require.NoError(t, err)
+		require.Equal(t, expected, op)
+	})
+
+	t.Run("error - request exceeds maximum operation size", func(t *testing.T) {
+		op, err := parser.ParseUpdateOperationReader(bytes.NewReader(append(payload, ' ')), false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), fmt.Sprintf("operation size exceeds maximum operation size[%d]", len(payload)))
+		require.True(t, errors.Is(err, ErrMalformedRequest))
+	})
+
+	t.Run("error - read error", func(t *testing.T) {
+		op, err := parser.ParseUpdateOperationReader(iotest.ErrReader(errors.New("read error")), false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "failed to read update request: read error")
+	})
+
+	t.Run("error - invalid request", func(t *testing.T) {
+		op, err := parser.ParseUpdateOperationReader(strings.NewReader("{}"), false)
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.True(t, errors.Is(err, ErrMalformedRequest))
 	})
 }
 
@@ -0,0 +1,54 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprocessor
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+// NewShardedOperationStore returns operation store that routes each operation to the store chosen by
+// the given shard function (index of the store for operation unique suffix). Operations are stored
+// with one Put per shard; note that persisting operations to multiple shards is not atomic.
+func NewShardedOperationStore(shard func(uniqueSuffix string) int, stores ...OperationStore) OperationStore {
+	return &shardedStore{shard: shard, stores: stores}
+}
+
+type shardedStore struct {
+	shard  func(uniqueSuffix string) int
+	stores []OperationStore
+}
+
+// Put stores operations in the shards chosen for their unique suffixes. Operations are validated
+// against shard range before any of them are stored.
+func (s *shardedStore) Put(ops []*operation.AnchoredOperation) error {
+	opsByShard := make([][]*operation.AnchoredOperation, len(s.stores))
+
+	for _, op := range ops {
+		i := s.shard(op.UniqueSuffix)
+		if i < 0 || i >= len(s.stores) {
+			return fmt.Errorf("shard[%d] for unique suffix[%s] is out of range (number of shards: %d)",
+				i, op.UniqueSuffix, len(s.stores))
+		}
+
+		opsByShard[i] = append(opsByShard[i], op)
+	}
+
+	for i, shardOps := range opsByShard {
+		if len(shardOps) == 0 {
+			continue
+		}
+
+		err := s.stores[i].Put(shardOps)
+		if err != nil {
+			return fmt.Errorf("store operations in shard[%d]: %s", i, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprocessor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+)
+
+func TestNewShardedOperationStore(t *testing.T) {
+	sidetreeTxn := txn.SidetreeTxn{AnchorString: anchorString, TransactionTime: 10, TransactionNumber: 1}
+
+	ops := []*operation.AnchoredOperation{
+		{UniqueSuffix: "suffix0"},
+		{UniqueSuffix: "suffix1"},
+		{UniqueSuffix: "other0"},
+	}
+
+	shard := func(uniqueSuffix string) int {
+		return int(uniqueSuffix[len(uniqueSuffix)-1] - '0')
+	}
+
+	t.Run("success - operations land in shard chosen by shard function", func(t *testing.T) {
+		shard0, puts0 := newShardStore(nil)
+		shard1, puts1 := newShardStore(nil)
+
+		p := New(&Providers{
+			OpStore:                   NewShardedOperationStore(shard, shard0, shard1),
+			OperationProtocolProvider: &mockTxnOpsProvider{ops: ops},
+		})
+
+		require.NoError(t, p.Process(sidetreeTxn))
+
+		require.Len(t, *puts0, 1)
+		require.Len(t, (*puts0)[0], 2)
+		require.Equal(t, "suffix0", (*puts0)[0][0].UniqueSuffix)
+		require.Equal(t, "other0", (*puts0)[0][1].UniqueSuffix)
+
+		require.Len(t, *puts1, 1)
+		require.Len(t, (*puts1)[0], 1)
+		require.Equal(t, "suffix1", (*puts1)[0][0].UniqueSuffix)
+		require.Equal(t, sidetreeTxn.TransactionNumber, (*puts1)[0][0].TransactionNumber)
+	})
+
+	t.Run("success - shard without operations is not called", func(t *testing.T) {
+		shard0, puts0 := newShardStore(nil)
+		shard1, puts1 := newShardStore(errors.New("shard 1 error"))
+
+		store := NewShardedOperationStore(shard, shard0, shard1)
+
+		require.NoError(t, store.Put([]*operation.AnchoredOperation{{UniqueSuffix: "suffix0"}}))
+		require.Len(t, *puts0, 1)
+		require.Empty(t, *puts1)
+	})
+
+	t.Run("error - shard out of range", func(t *testing.T) {
+		shard0, puts0 := newShardStore(nil)
+
+		err := NewShardedOperationStore(shard, shard0).Put(ops)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "shard[1] for unique suffix[suffix1] is out of range (number of shards: 1)")
+		require.Empty(t, *puts0)
+	})
+
+	t.Run("error - shard error", func(t *testing.T) {
+		shard0, _ := newShardStore(nil)
+		shard1, _ := newShardStore(errors.New("shard 1 error"))
+
+		p := New(&Providers{
+			OpStore:                   NewShardedOperationStore(shard, shard0, shard1),
+			OperationProtocolProvider: &mockTxnOpsProvider{ops: ops},
+		})
+
+		err := p.Process(sidetreeTxn)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "store operations in shard[1]: shard 1 error")
+	})
+}
+
+func newShardStore(putErr error) (*mockOperationStore, *[][]*operation.AnchoredOperation) {
+	var puts [][]*operation.AnchoredOperation
+
+	return &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+		if putErr != nil {
+			return putErr
+		}
+
+		puts = append(puts, ops)
+
+		return nil
+	}}, &puts
+}
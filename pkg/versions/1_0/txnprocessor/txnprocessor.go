@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package txnprocessor
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/trustbloc/edge-core/pkg/log"
@@ -24,66 +26,424 @@ type OperationStore interface {
 	Put(ops []*operation.AnchoredOperation) error
 }
 
+// ErrNoMoreTransactions is returned by transaction source when there are no more transactions to process.
+var ErrNoMoreTransactions = errors.New("no more transactions")
+
+// TransactionSource provides Sidetree transactions to be processed.
+type TransactionSource interface {
+	// Next returns next transaction; it should block until transaction is available or context is done
+	Next(ctx context.Context) (txn.SidetreeTxn, error)
+}
+
+// ProcessedTxnStore records Sidetree transactions that have been processed.
+type ProcessedTxnStore interface {
+	// IsProcessed returns true if transaction has already been processed
+	IsProcessed(sidetreeTxn txn.SidetreeTxn) (bool, error)
+	// MarkProcessed records transaction as processed
+	MarkProcessed(sidetreeTxn txn.SidetreeTxn) error
+}
+
+// LedgerTimeProvider provides current time of the ledger (anchoring system).
+type LedgerTimeProvider interface {
+	// LedgerTime returns transaction time (seconds since epoch) of the ledger head, e.g. time of the latest block
+	LedgerTime(ctx context.Context) (uint64, error)
+}
+
+// Metrics receives transaction processor metrics.
+type Metrics interface {
+	// OperationsProcessed is invoked with number of operations of the given type that have been processed
+	OperationsProcessed(opType operation.Type, count int)
+}
+
+type noopMetrics struct{}
+
+func (m *noopMetrics) OperationsProcessed(operation.Type, int) {}
+
+// Logger is used by transaction processor for logging (e.g. discarded operations).
+type Logger interface {
+	Debugf(msg string, args ...interface{})
+	Infof(msg string, args ...interface{})
+	Warnf(msg string, args ...interface{})
+}
+
 // Providers contains the providers required by the TxnProcessor.
 type Providers struct {
 	OpStore                   OperationStore
 	OperationProtocolProvider protocol.OperationProvider
+
+	// ProcessedTxnStore is optional; if set, transactions that have already been processed are skipped
+	ProcessedTxnStore ProcessedTxnStore
+
+	// OnProcessed is optional; if set, it is invoked after transaction operations have been stored
+	OnProcessed func(sidetreeTxn txn.SidetreeTxn, ops []*operation.AnchoredOperation)
+
+	// Metrics is optional; metrics are not collected by default
+	Metrics Metrics
+
+	// Logger is optional; default logger is used if not set
+	Logger Logger
+
+	// MaxTransactionTimeSkew is optional; if set, transactions with transaction time (seconds since epoch)
+	// later than ledger time (see LedgerTimeProvider) plus maximum skew are rejected
+	MaxTransactionTimeSkew time.Duration
+
+	// LedgerTimeProvider is required if MaxTransactionTimeSkew is set
+	LedgerTimeProvider LedgerTimeProvider
+
+	// SkipUnresolvableTxns is optional; if set, transactions for which operation provider returns
+	// protocol.ErrUnresolvableTxn (e.g. batch files have been removed from CAS) are skipped instead of failing
+	SkipUnresolvableTxns bool
+
+	// OnUnresolvable is optional; if set, it is invoked for every skipped unresolvable transaction
+	OnUnresolvable func(sidetreeTxn txn.SidetreeTxn, err error)
+
+	// StoreRetryPolicy is optional; if set, failed operation store writes are retried without
+	// retrieving transaction operations (CAS files) again
+	StoreRetryPolicy StoreRetryPolicy
+}
+
+// StoreRetryPolicy defines retry policy for transient operation store write failures.
+type StoreRetryPolicy struct {
+	// MaxAttempts is maximum number of write attempts (including the first one); zero or one disables retries
+	MaxAttempts int
+	// BaseDelay is delay before the first retry; delay doubles for every subsequent retry
+	BaseDelay time.Duration
+	// IsTransient reports whether store error may go away on retry; if not set, all errors are retried
+	IsTransient func(err error) bool
 }
 
 // TxnProcessor processes Sidetree transactions by persisting them to an operation store.
 type TxnProcessor struct {
 	*Providers
+
+	metrics Metrics
+	logger  Logger
 }
 
 // New returns a new document operation processor.
 func New(providers *Providers) *TxnProcessor {
+	var metrics Metrics = &noopMetrics{}
+	if providers.Metrics != nil {
+		metrics = providers.Metrics
+	}
+
+	var l Logger = logger
+	if providers.Logger != nil {
+		l = providers.Logger
+	}
+
 	return &TxnProcessor{
 		Providers: providers,
+		metrics:   metrics,
+		logger:    l,
 	}
 }
 
 // Process persists all of the operations for the given anchor.
 func (p *TxnProcessor) Process(sidetreeTxn txn.SidetreeTxn, suffixes ...string) error {
-	logger.Debugf("processing sidetree txn:%+v, suffixes: %s", sidetreeTxn, suffixes)
+	return p.ProcessContext(context.Background(), sidetreeTxn, suffixes...)
+}
 
-	txnOps, err := p.OperationProtocolProvider.GetTxnOperations(&sidetreeTxn)
+// ProcessContext is the same as Process but it stops retrieving transaction operations once the given context is done.
+func (p *TxnProcessor) ProcessContext(ctx context.Context, sidetreeTxn txn.SidetreeTxn, suffixes ...string) error {
+	p.logger.Debugf("processing sidetree txn:%+v, suffixes: %s", sidetreeTxn, suffixes)
+
+	txnOps, processed, err := p.getTxnOperations(ctx, sidetreeTxn)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve operations for anchor string[%s]: %s", sidetreeTxn.AnchorString, err)
+		if p.skipUnresolvable(sidetreeTxn, err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if processed {
+		return nil
+	}
+
+	err = p.processTxnOperations(ctx, txnOps, sidetreeTxn)
+	if err != nil {
+		return err
+	}
+
+	if p.ProcessedTxnStore != nil {
+		err = p.ProcessedTxnStore.MarkProcessed(sidetreeTxn)
+		if err != nil {
+			return errors.Wrapf(err, "failed to mark transaction[%d] as processed", sidetreeTxn.TransactionNumber)
+		}
 	}
 
-	return p.processTxnOperations(txnOps, sidetreeTxn)
+	return nil
 }
 
-func (p *TxnProcessor) processTxnOperations(txnOps []*operation.AnchoredOperation, sidetreeTxn txn.SidetreeTxn) error {
-	logger.Debugf("processing %d transaction operations", len(txnOps))
+// ProcessBatch persists operations for all of the given transactions with a single operation store call
+// (operation store is expected to persist operations atomically). No operations are persisted if operations
+// for any of the transactions cannot be retrieved. Transactions that have already been processed are skipped.
+func (p *TxnProcessor) ProcessBatch(txns []txn.SidetreeTxn) error {
+	return p.ProcessBatchContext(context.Background(), txns)
+}
 
-	batchSuffixes := make(map[string]bool)
+// ProcessBatchContext is the same as ProcessBatch but it stops retrieving transaction operations once the given
+// context is done.
+func (p *TxnProcessor) ProcessBatchContext(ctx context.Context, txns []txn.SidetreeTxn) error {
+	p.logger.Debugf("processing batch of %d sidetree txns", len(txns))
 
-	var ops []*operation.AnchoredOperation
-	for _, op := range txnOps {
-		_, ok := batchSuffixes[op.UniqueSuffix]
-		if ok {
-			logger.Warnf("[%s] duplicate suffix[%s] found in transaction operations: discarding operation %v", sidetreeTxn.Namespace, op.UniqueSuffix, op)
+	var (
+		batchTxns []txn.SidetreeTxn
+		txnOps    [][]*operation.AnchoredOperation
+		batchOps  []*operation.AnchoredOperation
+	)
+
+	for _, sidetreeTxn := range txns {
+		ops, processed, err := p.getTxnOperations(ctx, sidetreeTxn)
+		if err != nil {
+			if p.skipUnresolvable(sidetreeTxn, err) {
+				continue
+			}
+
+			return errors.Wrapf(err, "failed to process transaction[%d] in batch", sidetreeTxn.TransactionNumber)
+		}
 
+		if processed {
 			continue
 		}
 
-		updatedOp := updateAnchoredOperation(op, sidetreeTxn)
+		preparedOps := p.prepareTxnOperations(ops, sidetreeTxn)
 
-		logger.Debugf("updated operation with anchoring time: %s", updatedOp.UniqueSuffix)
-		ops = append(ops, updatedOp)
+		batchTxns = append(batchTxns, sidetreeTxn)
+		txnOps = append(txnOps, preparedOps)
+		batchOps = append(batchOps, preparedOps...)
+	}
 
-		batchSuffixes[op.UniqueSuffix] = true
+	if len(batchTxns) == 0 {
+		return nil
 	}
 
-	err := p.OpStore.Put(ops)
+	err := p.putOperations(ctx, batchOps)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store operations for batch of %d transactions", len(batchTxns))
+	}
+
+	p.reportOperationsProcessed(batchOps)
+
+	for i, sidetreeTxn := range batchTxns {
+		if p.OnProcessed != nil {
+			p.OnProcessed(sidetreeTxn, txnOps[i])
+		}
+
+		if p.ProcessedTxnStore != nil {
+			err = p.ProcessedTxnStore.MarkProcessed(sidetreeTxn)
+			if err != nil {
+				return errors.Wrapf(err, "failed to mark transaction[%d] as processed", sidetreeTxn.TransactionNumber)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getTxnOperations retrieves transaction operations; returns true if transaction has already been processed.
+func (p *TxnProcessor) getTxnOperations(ctx context.Context, sidetreeTxn txn.SidetreeTxn) ([]*operation.AnchoredOperation, bool, error) {
+	if err := p.validateTransactionTime(ctx, sidetreeTxn); err != nil {
+		return nil, false, err
+	}
+
+	if p.ProcessedTxnStore != nil {
+		processed, err := p.ProcessedTxnStore.IsProcessed(sidetreeTxn)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to check whether transaction[%d] has been processed", sidetreeTxn.TransactionNumber)
+		}
+
+		if processed {
+			p.logger.Infof("[%s] skipping transaction[%d] at time[%d] since it has already been processed",
+				sidetreeTxn.Namespace, sidetreeTxn.TransactionNumber, sidetreeTxn.TransactionTime)
+
+			return nil, true, nil
+		}
+	}
+
+	txnOps, err := p.OperationProtocolProvider.GetTxnOperationsContext(ctx, &sidetreeTxn)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to retrieve operations for anchor string[%s]", sidetreeTxn.AnchorString)
+	}
+
+	return txnOps, false, nil
+}
+
+// skipUnresolvable returns true if the given error is protocol.ErrUnresolvableTxn and unresolvable transactions
+// should be skipped.
+func (p *TxnProcessor) skipUnresolvable(sidetreeTxn txn.SidetreeTxn, err error) bool {
+	if !p.SkipUnresolvableTxns || !errors.Is(err, protocol.ErrUnresolvableTxn) {
+		return false
+	}
+
+	p.logger.Warnf("[%s] skipping unresolvable transaction[%d] at time[%d]: %s",
+		sidetreeTxn.Namespace, sidetreeTxn.TransactionNumber, sidetreeTxn.TransactionTime, err)
+
+	if p.OnUnresolvable != nil {
+		p.OnUnresolvable(sidetreeTxn, err)
+	}
+
+	return true
+}
+
+// Run pulls transactions from the given source and processes them in order until the context is done,
+// the source returns ErrNoMoreTransactions or an error occurs.
+func (p *TxnProcessor) Run(ctx context.Context, source TransactionSource) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		sidetreeTxn, err := source.Next(ctx)
+		if err != nil {
+			if errors.Is(err, ErrNoMoreTransactions) {
+				p.logger.Debugf("transaction source has no more transactions")
+
+				return nil
+			}
+
+			return errors.Wrap(err, "failed to retrieve next transaction from source")
+		}
+
+		err = p.ProcessContext(ctx, sidetreeTxn)
+		if err != nil {
+			return errors.Wrapf(err, "failed to process transaction[%d]", sidetreeTxn.TransactionNumber)
+		}
+	}
+}
+
+// validateTransactionTime rejects transaction with transaction time later than ledger time (beyond maximum
+// skew) since such transaction could be used to manipulate operation ordering. Transaction time is compared
+// against ledger time (rather than node clock) so that the result doesn't depend on when transaction is
+// processed (e.g. when catching up after downtime).
+func (p *TxnProcessor) validateTransactionTime(ctx context.Context, sidetreeTxn txn.SidetreeTxn) error {
+	if p.MaxTransactionTimeSkew <= 0 {
+		return nil
+	}
+
+	if p.LedgerTimeProvider == nil {
+		return errors.New("ledger time provider is required for maximum transaction time skew")
+	}
+
+	ledgerTime, err := p.LedgerTimeProvider.LedgerTime(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve ledger time")
+	}
+
+	maxTime := ledgerTime + uint64(p.MaxTransactionTimeSkew/time.Second)
+
+	if sidetreeTxn.TransactionTime > maxTime {
+		return fmt.Errorf("transaction[%d] time[%d] is later than maximum allowed time[%d]",
+			sidetreeTxn.TransactionNumber, sidetreeTxn.TransactionTime, maxTime)
+	}
+
+	return nil
+}
+
+func (p *TxnProcessor) processTxnOperations(ctx context.Context, txnOps []*operation.AnchoredOperation, sidetreeTxn txn.SidetreeTxn) error {
+	ops := p.prepareTxnOperations(txnOps, sidetreeTxn)
+
+	err := p.putOperations(ctx, ops)
 	if err != nil {
 		return errors.Wrapf(err, "failed to store operation from anchor string[%s]", sidetreeTxn.AnchorString)
 	}
 
+	p.reportOperationsProcessed(ops)
+
+	if p.OnProcessed != nil {
+		p.OnProcessed(sidetreeTxn, ops)
+	}
+
 	return nil
 }
 
+// putOperations stores operations; transient store errors are retried according to store retry policy.
+func (p *TxnProcessor) putOperations(ctx context.Context, ops []*operation.AnchoredOperation) error {
+	policy := p.StoreRetryPolicy
+	delay := policy.BaseDelay
+
+	for attempt := 1; ; attempt++ {
+		err := p.OpStore.Put(ops)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= policy.MaxAttempts || (policy.IsTransient != nil && !policy.IsTransient(err)) || ctx.Err() != nil {
+			return err
+		}
+
+		p.logger.Debugf("failed to store %d operations on attempt %d; retrying in %s: %s", len(ops), attempt, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// prepareTxnOperations removes operations with duplicate suffix and updates operations with anchoring information.
+func (p *TxnProcessor) prepareTxnOperations(txnOps []*operation.AnchoredOperation, sidetreeTxn txn.SidetreeTxn) []*operation.AnchoredOperation {
+	p.logger.Debugf("processing %d transaction operations", len(txnOps))
+
+	uniqueOps, discarded := removeDuplicateSuffixes(txnOps, p.logger)
+	if discarded > 0 {
+		p.logger.Warnf("[%s] discarded %d operation(s) with duplicate suffix in transaction[%d]",
+			sidetreeTxn.Namespace, discarded, sidetreeTxn.TransactionNumber)
+	}
+
+	var ops []*operation.AnchoredOperation
+	for _, op := range uniqueOps {
+		updatedOp := updateAnchoredOperation(op, sidetreeTxn)
+
+		p.logger.Debugf("updated operation with anchoring time: %s", updatedOp.UniqueSuffix)
+		ops = append(ops, updatedOp)
+	}
+
+	return ops
+}
+
+func (p *TxnProcessor) reportOperationsProcessed(ops []*operation.AnchoredOperation) {
+	counts := make(map[operation.Type]int)
+	for _, op := range ops {
+		counts[op.Type]++
+	}
+
+	for _, opType := range []operation.Type{operation.TypeCreate, operation.TypeUpdate, operation.TypeRecover, operation.TypeDeactivate} {
+		if count := counts[opType]; count > 0 {
+			p.metrics.OperationsProcessed(opType, count)
+		}
+	}
+}
+
+// removeDuplicateSuffixes keeps the first operation (in the given order) for each suffix and discards
+// subsequent operations with the same suffix. It returns unique operations (in the given order)
+// and the number of discarded operations.
+func removeDuplicateSuffixes(txnOps []*operation.AnchoredOperation, l Logger) ([]*operation.AnchoredOperation, int) {
+	batchSuffixes := make(map[string]bool)
+
+	var ops []*operation.AnchoredOperation
+
+	for _, op := range txnOps {
+		if batchSuffixes[op.UniqueSuffix] {
+			l.Debugf("duplicate suffix[%s] found in transaction operations: discarding operation %v", op.UniqueSuffix, op)
+
+			continue
+		}
+
+		ops = append(ops, op)
+
+		batchSuffixes[op.UniqueSuffix] = true
+	}
+
+	return ops, len(txnOps) - len(ops)
+}
+
 func updateAnchoredOperation(op *operation.AnchoredOperation, sidetreeTxn txn.SidetreeTxn) *operation.AnchoredOperation {
 	//  The logical anchoring time that this operation was anchored on
 	op.TransactionTime = sidetreeTxn.TransactionTime
@@ -7,13 +7,22 @@ SPDX-License-Identifier: Apache-2.0
 package txnprocessor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
 )
 
 const anchorString = "1.coreIndexURI"
@@ -38,6 +47,815 @@ func TestTxnProcessor_Process(t *testing.T) {
 	})
 }
 
+func TestTxnProcessor_ProcessContext(t *testing.T) {
+	t.Run("error - cancelled context", func(t *testing.T) {
+		providers := &Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := New(providers)
+		err := p.ProcessContext(ctx, txn.SidetreeTxn{AnchorString: anchorString})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), context.Canceled.Error())
+	})
+}
+
+func TestTxnProcessor_MaxTransactionTimeSkew(t *testing.T) {
+	const (
+		skew       = time.Minute
+		ledgerTime = 1000000
+	)
+
+	newProcessor := func(store *mockOperationStore) *TxnProcessor {
+		return New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			MaxTransactionTimeSkew:    skew,
+			LedgerTimeProvider:        &mockLedgerTimeProvider{time: ledgerTime},
+		})
+	}
+
+	newStore := func(puts *int) *mockOperationStore {
+		return &mockOperationStore{putFunc: func([]*operation.AnchoredOperation) error {
+			*puts++
+
+			return nil
+		}}
+	}
+
+	t.Run("success - transaction at ledger time", func(t *testing.T) {
+		puts := 0
+
+		err := newProcessor(newStore(&puts)).Process(txn.SidetreeTxn{
+			AnchorString:    anchorString,
+			TransactionTime: ledgerTime,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, puts)
+	})
+
+	t.Run("success - historical transaction (e.g. when catching up)", func(t *testing.T) {
+		puts := 0
+
+		err := newProcessor(newStore(&puts)).Process(txn.SidetreeTxn{
+			AnchorString:    anchorString,
+			TransactionTime: 10,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, puts)
+	})
+
+	t.Run("success - transaction within skew", func(t *testing.T) {
+		puts := 0
+
+		err := newProcessor(newStore(&puts)).Process(txn.SidetreeTxn{
+			AnchorString:    anchorString,
+			TransactionTime: ledgerTime + uint64(skew/time.Second),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, puts)
+	})
+
+	t.Run("success - skew check is disabled by default", func(t *testing.T) {
+		puts := 0
+
+		p := New(&Providers{
+			OpStore:                   newStore(&puts),
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+		})
+
+		err := p.Process(txn.SidetreeTxn{
+			AnchorString:    anchorString,
+			TransactionTime: uint64(time.Now().Add(time.Hour).Unix()),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, puts)
+	})
+
+	t.Run("error - transaction beyond skew", func(t *testing.T) {
+		puts := 0
+
+		err := newProcessor(newStore(&puts)).Process(txn.SidetreeTxn{
+			AnchorString:      anchorString,
+			TransactionTime:   ledgerTime + uint64(skew/time.Second) + 1,
+			TransactionNumber: 5,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "transaction[5] time")
+		require.Contains(t, err.Error(), "is later than maximum allowed time[1000060]")
+		require.Equal(t, 0, puts)
+	})
+
+	t.Run("error - ledger time error", func(t *testing.T) {
+		puts := 0
+
+		p := New(&Providers{
+			OpStore:                   newStore(&puts),
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			MaxTransactionTimeSkew:    skew,
+			LedgerTimeProvider:        &mockLedgerTimeProvider{err: errors.New("ledger error")},
+		})
+
+		err := p.Process(txn.SidetreeTxn{AnchorString: anchorString, TransactionTime: ledgerTime})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to retrieve ledger time: ledger error")
+		require.Equal(t, 0, puts)
+	})
+
+	t.Run("error - missing ledger time provider", func(t *testing.T) {
+		puts := 0
+
+		p := New(&Providers{
+			OpStore:                   newStore(&puts),
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			MaxTransactionTimeSkew:    skew,
+		})
+
+		err := p.Process(txn.SidetreeTxn{AnchorString: anchorString, TransactionTime: ledgerTime})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ledger time provider is required for maximum transaction time skew")
+		require.Equal(t, 0, puts)
+	})
+}
+
+func TestTxnProcessor_ProcessedTxnStore(t *testing.T) {
+	sidetreeTxn := txn.SidetreeTxn{
+		Namespace:         "did:sidetree",
+		AnchorString:      anchorString,
+		TransactionTime:   10,
+		TransactionNumber: 1,
+	}
+
+	t.Run("success - transaction is processed only once", func(t *testing.T) {
+		putCount := 0
+
+		providers := &Providers{
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				putCount++
+
+				return nil
+			}},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         newMockProcessedTxnStore(),
+		}
+
+		p := New(providers)
+
+		require.NoError(t, p.Process(sidetreeTxn))
+		require.NoError(t, p.Process(sidetreeTxn))
+		require.Equal(t, 1, putCount)
+
+		// same transaction number in different namespace is processed
+		otherTxn := sidetreeTxn
+		otherTxn.Namespace = "did:other"
+
+		require.NoError(t, p.Process(otherTxn))
+		require.Equal(t, 2, putCount)
+	})
+
+	t.Run("success - failed transaction is not marked as processed", func(t *testing.T) {
+		putErr := fmt.Errorf("put error")
+		putCount := 0
+
+		providers := &Providers{
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				putCount++
+
+				return putErr
+			}},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         newMockProcessedTxnStore(),
+		}
+
+		p := New(providers)
+
+		require.Error(t, p.Process(sidetreeTxn))
+
+		putErr = nil
+
+		require.NoError(t, p.Process(sidetreeTxn))
+		require.Equal(t, 2, putCount)
+	})
+
+	t.Run("error - is processed error", func(t *testing.T) {
+		store := newMockProcessedTxnStore()
+		store.isProcessedErr = fmt.Errorf("is processed error")
+
+		p := New(&Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         store,
+		})
+
+		err := p.Process(sidetreeTxn)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to check whether transaction[1] has been processed: is processed error")
+	})
+
+	t.Run("error - mark processed error", func(t *testing.T) {
+		store := newMockProcessedTxnStore()
+		store.markProcessedErr = fmt.Errorf("mark processed error")
+
+		p := New(&Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         store,
+		})
+
+		err := p.Process(sidetreeTxn)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to mark transaction[1] as processed: mark processed error")
+	})
+}
+
+func TestTxnProcessor_OnProcessed(t *testing.T) {
+	sidetreeTxn := txn.SidetreeTxn{
+		Namespace:           "did:sidetree",
+		AnchorString:        anchorString,
+		TransactionTime:     10,
+		TransactionNumber:   1,
+		ProtocolGenesisTime: 5,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			calls       int
+			receivedTxn txn.SidetreeTxn
+			receivedOps []*operation.AnchoredOperation
+		)
+
+		p := New(&Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			OnProcessed: func(sidetreeTxn txn.SidetreeTxn, ops []*operation.AnchoredOperation) {
+				calls++
+				receivedTxn = sidetreeTxn
+				receivedOps = ops
+			},
+		})
+
+		require.NoError(t, p.Process(sidetreeTxn))
+
+		require.Equal(t, 1, calls)
+		require.Equal(t, sidetreeTxn, receivedTxn)
+		require.Len(t, receivedOps, 1)
+		require.Equal(t, "abc", receivedOps[0].UniqueSuffix)
+		require.Equal(t, sidetreeTxn.TransactionTime, receivedOps[0].TransactionTime)
+		require.Equal(t, sidetreeTxn.TransactionNumber, receivedOps[0].TransactionNumber)
+		require.Equal(t, sidetreeTxn.ProtocolGenesisTime, receivedOps[0].ProtocolGenesisTime)
+	})
+
+	t.Run("error - callback is not invoked if put fails", func(t *testing.T) {
+		calls := 0
+
+		p := New(&Providers{
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				return fmt.Errorf("put error")
+			}},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			OnProcessed: func(txn.SidetreeTxn, []*operation.AnchoredOperation) {
+				calls++
+			},
+		})
+
+		require.Error(t, p.Process(sidetreeTxn))
+		require.Equal(t, 0, calls)
+	})
+}
+
+func TestTxnProcessor_Metrics(t *testing.T) {
+	batchOps := []*operation.AnchoredOperation{
+		{UniqueSuffix: "create-1", Type: operation.TypeCreate},
+		{UniqueSuffix: "create-2", Type: operation.TypeCreate},
+		{UniqueSuffix: "update-1", Type: operation.TypeUpdate},
+		{UniqueSuffix: "recover-1", Type: operation.TypeRecover},
+		{UniqueSuffix: "create-1", Type: operation.TypeCreate},
+		{UniqueSuffix: "create-3", Type: operation.TypeCreate},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		metrics := &mockMetrics{counts: make(map[operation.Type]int)}
+
+		p := New(&Providers{
+			OpStore: &mockOperationStore{},
+			Metrics: metrics,
+		})
+
+		err := p.processTxnOperations(context.Background(), batchOps, txn.SidetreeTxn{AnchorString: anchorString})
+		require.NoError(t, err)
+
+		require.Equal(t, map[operation.Type]int{
+			operation.TypeCreate:  3,
+			operation.TypeUpdate:  1,
+			operation.TypeRecover: 1,
+		}, metrics.counts)
+	})
+
+	t.Run("success - no metrics if put fails", func(t *testing.T) {
+		metrics := &mockMetrics{counts: make(map[operation.Type]int)}
+
+		p := New(&Providers{
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				return fmt.Errorf("put error")
+			}},
+			Metrics: metrics,
+		})
+
+		err := p.processTxnOperations(context.Background(), batchOps, txn.SidetreeTxn{AnchorString: anchorString})
+		require.Error(t, err)
+		require.Empty(t, metrics.counts)
+	})
+
+	t.Run("success - default no-op metrics", func(t *testing.T) {
+		p := New(&Providers{OpStore: &mockOperationStore{}})
+
+		err := p.processTxnOperations(context.Background(), batchOps, txn.SidetreeTxn{AnchorString: anchorString})
+		require.NoError(t, err)
+	})
+}
+
+func TestTxnProcessor_Logger(t *testing.T) {
+	l := &capturingLogger{}
+
+	p := New(&Providers{OpStore: &mockOperationStore{}, Logger: l})
+
+	batchOps := []*operation.AnchoredOperation{
+		{UniqueSuffix: "suffix", Type: operation.TypeUpdate},
+		{UniqueSuffix: "suffix", Type: operation.TypeUpdate},
+	}
+
+	err := p.processTxnOperations(context.Background(), batchOps, txn.SidetreeTxn{Namespace: "ns", TransactionNumber: 5})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, l.messages("debug"))
+	require.Equal(t, []string{"[ns] discarded 1 operation(s) with duplicate suffix in transaction[5]"}, l.messages("warn"))
+}
+
+func TestTxnProcessor_ProcessBatch(t *testing.T) {
+	txns := []txn.SidetreeTxn{
+		{Namespace: "did:sidetree", AnchorString: "1.uri1", TransactionTime: 10, TransactionNumber: 1},
+		{Namespace: "did:sidetree", AnchorString: "1.uri2", TransactionTime: 10, TransactionNumber: 2},
+		{Namespace: "did:sidetree", AnchorString: "1.uri3", TransactionTime: 10, TransactionNumber: 3},
+	}
+
+	newRecordingStore := func(putErr error) (*mockOperationStore, *[][]*operation.AnchoredOperation) {
+		var puts [][]*operation.AnchoredOperation
+
+		return &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+			if putErr != nil {
+				return putErr
+			}
+
+			puts = append(puts, ops)
+
+			return nil
+		}}, &puts
+	}
+
+	t.Run("success - operations for all transactions are stored with single put", func(t *testing.T) {
+		store, puts := newRecordingStore(nil)
+		processedStore := newMockProcessedTxnStore()
+
+		var processed []txn.SidetreeTxn
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         processedStore,
+			OnProcessed: func(sidetreeTxn txn.SidetreeTxn, ops []*operation.AnchoredOperation) {
+				require.Len(t, ops, 1)
+				require.Equal(t, sidetreeTxn.TransactionNumber, ops[0].TransactionNumber)
+
+				processed = append(processed, sidetreeTxn)
+			},
+		})
+
+		require.NoError(t, p.ProcessBatch(txns))
+
+		require.Len(t, *puts, 1)
+		require.Len(t, (*puts)[0], len(txns))
+
+		for i, op := range (*puts)[0] {
+			require.Equal(t, txns[i].TransactionNumber, op.TransactionNumber)
+			require.Equal(t, txns[i].TransactionTime, op.TransactionTime)
+		}
+
+		require.Equal(t, txns, processed)
+
+		for _, sidetreeTxn := range txns {
+			require.True(t, processedStore.processed[txnKey(sidetreeTxn)])
+		}
+	})
+
+	t.Run("success - processed transactions are skipped", func(t *testing.T) {
+		store, puts := newRecordingStore(nil)
+
+		processedStore := newMockProcessedTxnStore()
+		processedStore.processed[txnKey(txns[0])] = true
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         processedStore,
+		})
+
+		require.NoError(t, p.ProcessBatch(txns))
+		require.Len(t, *puts, 1)
+		require.Len(t, (*puts)[0], len(txns)-1)
+
+		// all transactions have already been processed
+		require.NoError(t, p.ProcessBatch(txns))
+		require.Len(t, *puts, 1)
+	})
+
+	t.Run("success - empty batch", func(t *testing.T) {
+		store, puts := newRecordingStore(nil)
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+		})
+
+		require.NoError(t, p.ProcessBatch(nil))
+		require.Empty(t, *puts)
+	})
+
+	t.Run("error - later transaction fails", func(t *testing.T) {
+		store, puts := newRecordingStore(nil)
+		processedStore := newMockProcessedTxnStore()
+
+		calls := 0
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{errAnchorString: txns[2].AnchorString},
+			ProcessedTxnStore:         processedStore,
+			OnProcessed: func(txn.SidetreeTxn, []*operation.AnchoredOperation) {
+				calls++
+			},
+		})
+
+		err := p.ProcessBatch(txns)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to process transaction[3] in batch")
+		require.Contains(t, err.Error(), "txn operations provider error for anchor string[1.uri3]")
+
+		// none of the operations have been persisted
+		require.Empty(t, *puts)
+		require.Empty(t, processedStore.processed)
+		require.Equal(t, 0, calls)
+	})
+
+	t.Run("error - store error", func(t *testing.T) {
+		store, _ := newRecordingStore(fmt.Errorf("put error"))
+		processedStore := newMockProcessedTxnStore()
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         processedStore,
+		})
+
+		err := p.ProcessBatch(txns)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to store operations for batch of 3 transactions: put error")
+		require.Empty(t, processedStore.processed)
+	})
+
+	t.Run("error - mark processed error", func(t *testing.T) {
+		store, puts := newRecordingStore(nil)
+
+		processedStore := newMockProcessedTxnStore()
+		processedStore.markProcessedErr = fmt.Errorf("mark error")
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			ProcessedTxnStore:         processedStore,
+		})
+
+		err := p.ProcessBatch(txns)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to mark transaction[1] as processed: mark error")
+		require.Len(t, *puts, 1)
+	})
+
+	t.Run("error - cancelled context", func(t *testing.T) {
+		store, puts := newRecordingStore(nil)
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := p.ProcessBatchContext(ctx, txns)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), context.Canceled.Error())
+		require.Empty(t, *puts)
+	})
+}
+
+func TestTxnProcessor_StoreRetry(t *testing.T) {
+	sidetreeTxn := txn.SidetreeTxn{AnchorString: anchorString, TransactionNumber: 1}
+
+	errTransient := errors.New("transient store error")
+
+	newFailingStore := func(failures int, storeErr error) (*mockOperationStore, *int) {
+		putCalls := 0
+
+		return &mockOperationStore{putFunc: func([]*operation.AnchoredOperation) error {
+			putCalls++
+
+			if putCalls <= failures {
+				return storeErr
+			}
+
+			return nil
+		}}, &putCalls
+	}
+
+	policy := StoreRetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		IsTransient: func(err error) bool {
+			return errors.Is(err, errTransient)
+		},
+	}
+
+	t.Run("success - transient store error is retried without reading CAS again", func(t *testing.T) {
+		store, putCalls := newFailingStore(2, errTransient)
+		opp := &mockTxnOpsProvider{}
+		processedStore := newMockProcessedTxnStore()
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: opp,
+			ProcessedTxnStore:         processedStore,
+			StoreRetryPolicy:          policy,
+		})
+
+		require.NoError(t, p.Process(sidetreeTxn))
+		require.Equal(t, 3, *putCalls)
+		require.Equal(t, 1, opp.calls)
+		require.Len(t, processedStore.processed, 1)
+	})
+
+	t.Run("success - transient store error is retried for batch", func(t *testing.T) {
+		store, putCalls := newFailingStore(1, errTransient)
+		opp := &mockTxnOpsProvider{}
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: opp,
+			StoreRetryPolicy:          policy,
+		})
+
+		require.NoError(t, p.ProcessBatch([]txn.SidetreeTxn{sidetreeTxn, {AnchorString: "1.uri2", TransactionNumber: 2}}))
+		require.Equal(t, 2, *putCalls)
+		require.Equal(t, 2, opp.calls)
+	})
+
+	t.Run("error - retries exhausted", func(t *testing.T) {
+		store, putCalls := newFailingStore(3, errTransient)
+		opp := &mockTxnOpsProvider{}
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: opp,
+			StoreRetryPolicy:          policy,
+		})
+
+		err := p.Process(sidetreeTxn)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errTransient))
+		require.Equal(t, 3, *putCalls)
+		require.Equal(t, 1, opp.calls)
+	})
+
+	t.Run("error - non-transient error fails immediately", func(t *testing.T) {
+		store, putCalls := newFailingStore(1, errors.New("constraint violation"))
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			StoreRetryPolicy:          policy,
+		})
+
+		err := p.Process(sidetreeTxn)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "constraint violation")
+		require.Equal(t, 1, *putCalls)
+	})
+
+	t.Run("error - retries are disabled by default", func(t *testing.T) {
+		store, putCalls := newFailingStore(1, errTransient)
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+		})
+
+		require.Error(t, p.Process(sidetreeTxn))
+		require.Equal(t, 1, *putCalls)
+	})
+
+	t.Run("success - all errors are retried if transient check is not set", func(t *testing.T) {
+		store, putCalls := newFailingStore(1, errors.New("store error"))
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			StoreRetryPolicy:          StoreRetryPolicy{MaxAttempts: 2},
+		})
+
+		require.NoError(t, p.Process(sidetreeTxn))
+		require.Equal(t, 2, *putCalls)
+	})
+
+	t.Run("error - context is done during backoff", func(t *testing.T) {
+		store, putCalls := newFailingStore(3, errTransient)
+
+		p := New(&Providers{
+			OpStore:                   store,
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			StoreRetryPolicy:          StoreRetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := p.processTxnOperations(ctx, []*operation.AnchoredOperation{{UniqueSuffix: "abc"}}, sidetreeTxn)
+		require.True(t, errors.Is(err, errTransient))
+		require.Equal(t, 1, *putCalls)
+	})
+}
+
+func TestTxnProcessor_SkipUnresolvableTxns(t *testing.T) {
+	p := mocks.NewMockProtocolClient().Protocol
+
+	// core index file is permanently missing from CAS
+	unresolvableTxn := txn.SidetreeTxn{Namespace: "did:sidetree", AnchorString: "1.coreIndexURI", TransactionNumber: 1}
+
+	newProvider := func() protocol.OperationProvider {
+		return txnprovider.NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(nil),
+			compression.New(compression.WithDefaultAlgorithms()))
+	}
+
+	t.Run("success - unresolvable transaction is skipped", func(t *testing.T) {
+		processedStore := newMockProcessedTxnStore()
+
+		var skipped []txn.SidetreeTxn
+
+		putCalls := 0
+
+		processor := New(&Providers{
+			OpStore: &mockOperationStore{putFunc: func([]*operation.AnchoredOperation) error {
+				putCalls++
+
+				return nil
+			}},
+			OperationProtocolProvider: newProvider(),
+			ProcessedTxnStore:         processedStore,
+			SkipUnresolvableTxns:      true,
+			OnUnresolvable: func(sidetreeTxn txn.SidetreeTxn, err error) {
+				require.True(t, errors.Is(err, protocol.ErrUnresolvableTxn))
+
+				skipped = append(skipped, sidetreeTxn)
+			},
+		})
+
+		require.NoError(t, processor.Process(unresolvableTxn))
+		require.Equal(t, []txn.SidetreeTxn{unresolvableTxn}, skipped)
+		require.Equal(t, 0, putCalls)
+		require.Empty(t, processedStore.processed)
+	})
+
+	t.Run("success - unresolvable transaction is skipped in batch", func(t *testing.T) {
+		var stored []*operation.AnchoredOperation
+
+		processor := New(&Providers{
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				stored = append(stored, ops...)
+
+				return nil
+			}},
+			OperationProtocolProvider: &mockTxnOpsProvider{
+				errAnchorString: "1.uri1",
+				errFunc: func(err error) error {
+					return fmt.Errorf("%w: %s", protocol.ErrUnresolvableTxn, err.Error())
+				},
+			},
+			SkipUnresolvableTxns: true,
+		})
+
+		err := processor.ProcessBatch([]txn.SidetreeTxn{
+			{AnchorString: "1.uri1", TransactionNumber: 1},
+			{AnchorString: "1.uri2", TransactionNumber: 2},
+		})
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		require.Equal(t, uint64(2), stored[0].TransactionNumber)
+	})
+
+	t.Run("error - unresolvable transaction is not skipped by default", func(t *testing.T) {
+		processor := New(&Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: newProvider(),
+		})
+
+		err := processor.Process(unresolvableTxn)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, protocol.ErrUnresolvableTxn))
+		require.Contains(t, err.Error(), "failed to retrieve operations for anchor string[1.coreIndexURI]")
+	})
+
+	t.Run("error - other errors are not skipped", func(t *testing.T) {
+		processor := New(&Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: &mockTxnOpsProvider{err: fmt.Errorf("txn operations provider error")},
+			SkipUnresolvableTxns:      true,
+		})
+
+		err := processor.Process(unresolvableTxn)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "txn operations provider error")
+	})
+}
+
+func TestTxnProcessor_Run(t *testing.T) {
+	txns := []txn.SidetreeTxn{
+		{TransactionNumber: 1, AnchorString: "1.uri1"},
+		{TransactionNumber: 2, AnchorString: "1.uri2"},
+		{TransactionNumber: 3, AnchorString: "1.uri3"},
+	}
+
+	t.Run("success - transactions processed in order", func(t *testing.T) {
+		var processed []uint64
+
+		providers := &Providers{
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				for _, op := range ops {
+					processed = append(processed, op.TransactionNumber)
+				}
+
+				return nil
+			}},
+		}
+
+		p := New(providers)
+		err := p.Run(context.Background(), &mockTxnSource{txns: txns})
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1, 2, 3}, processed)
+	})
+
+	t.Run("error - context done", func(t *testing.T) {
+		providers := &Providers{
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			OpStore:                   &mockOperationStore{},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := New(providers)
+		err := p.Run(ctx, &mockTxnSource{txns: txns})
+		require.Error(t, err)
+		require.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("error - source error", func(t *testing.T) {
+		providers := &Providers{
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+			OpStore:                   &mockOperationStore{},
+		}
+
+		p := New(providers)
+		err := p.Run(context.Background(), &mockTxnSource{err: fmt.Errorf("source error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to retrieve next transaction from source: source error")
+	})
+
+	t.Run("error - process error", func(t *testing.T) {
+		providers := &Providers{
+			OperationProtocolProvider: &mockTxnOpsProvider{err: fmt.Errorf("txn operations provider error")},
+			OpStore:                   &mockOperationStore{},
+		}
+
+		p := New(providers)
+		err := p.Run(context.Background(), &mockTxnSource{txns: txns})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to process transaction[1]")
+	})
+}
+
 func TestProcessTxnOperations(t *testing.T) {
 	t.Run("test error from operationStore Put", func(t *testing.T) {
 		providers := &Providers{
@@ -47,7 +865,7 @@ func TestProcessTxnOperations(t *testing.T) {
 		}
 
 		p := New(providers)
-		err := p.processTxnOperations([]*operation.AnchoredOperation{{UniqueSuffix: "abc"}}, txn.SidetreeTxn{AnchorString: anchorString})
+		err := p.processTxnOperations(context.Background(), []*operation.AnchoredOperation{{UniqueSuffix: "abc"}}, txn.SidetreeTxn{AnchorString: anchorString})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to store operation from anchor string")
 	})
@@ -62,7 +880,7 @@ func TestProcessTxnOperations(t *testing.T) {
 		batchOps, err := p.OperationProtocolProvider.GetTxnOperations(&txn.SidetreeTxn{AnchorString: anchorString})
 		require.NoError(t, err)
 
-		err = p.processTxnOperations(batchOps, txn.SidetreeTxn{AnchorString: anchorString})
+		err = p.processTxnOperations(context.Background(), batchOps, txn.SidetreeTxn{AnchorString: anchorString})
 		require.NoError(t, err)
 	})
 
@@ -80,8 +898,80 @@ func TestProcessTxnOperations(t *testing.T) {
 		// only first operation will be processed, subsequent operations will be discarded
 		batchOps = append(batchOps, batchOps...)
 
-		err = p.processTxnOperations(batchOps, txn.SidetreeTxn{AnchorString: anchorString})
+		err = p.processTxnOperations(context.Background(), batchOps, txn.SidetreeTxn{AnchorString: anchorString})
+		require.NoError(t, err)
+	})
+
+	t.Run("success - de-duplicated operations are stored with single put", func(t *testing.T) {
+		var putCalls [][]*operation.AnchoredOperation
+
+		providers := &Providers{
+			OpStore: &mockOperationStore{putFunc: func(ops []*operation.AnchoredOperation) error {
+				putCalls = append(putCalls, ops)
+
+				return nil
+			}},
+		}
+
+		batchOps := []*operation.AnchoredOperation{
+			{UniqueSuffix: "abc", Type: operation.TypeCreate},
+			{UniqueSuffix: "def", Type: operation.TypeUpdate},
+			{UniqueSuffix: "abc", Type: operation.TypeUpdate},
+			{UniqueSuffix: "ghi", Type: operation.TypeDeactivate},
+		}
+
+		p := New(providers)
+
+		err := p.processTxnOperations(context.Background(), batchOps, txn.SidetreeTxn{AnchorString: anchorString, TransactionNumber: 5})
 		require.NoError(t, err)
+
+		require.Len(t, putCalls, 1)
+		require.Len(t, putCalls[0], 3)
+
+		for i, suffix := range []string{"abc", "def", "ghi"} {
+			require.Equal(t, suffix, putCalls[0][i].UniqueSuffix)
+			require.Equal(t, uint64(5), putCalls[0][i].TransactionNumber)
+		}
+
+		require.Equal(t, operation.TypeCreate, putCalls[0][0].Type)
+	})
+}
+
+func TestRemoveDuplicateSuffixes(t *testing.T) {
+	t.Run("success - first operation in order wins", func(t *testing.T) {
+		txnOps := []*operation.AnchoredOperation{
+			{UniqueSuffix: "abc", Type: operation.TypeUpdate, OperationBuffer: []byte("first")},
+			{UniqueSuffix: "def", Type: operation.TypeCreate},
+			{UniqueSuffix: "abc", Type: operation.TypeRecover, OperationBuffer: []byte("second")},
+			{UniqueSuffix: "abc", Type: operation.TypeDeactivate, OperationBuffer: []byte("third")},
+			{UniqueSuffix: "ghi", Type: operation.TypeCreate},
+			{UniqueSuffix: "def", Type: operation.TypeUpdate},
+		}
+
+		for i := 0; i < 10; i++ {
+			ops, discarded := removeDuplicateSuffixes(txnOps, logger)
+			require.Equal(t, 3, discarded)
+			require.Len(t, ops, 3)
+
+			require.Equal(t, txnOps[0], ops[0])
+			require.Equal(t, []byte("first"), ops[0].OperationBuffer)
+			require.Equal(t, txnOps[1], ops[1])
+			require.Equal(t, txnOps[4], ops[2])
+		}
+	})
+
+	t.Run("success - no duplicates", func(t *testing.T) {
+		txnOps := []*operation.AnchoredOperation{{UniqueSuffix: "abc"}, {UniqueSuffix: "def"}}
+
+		ops, discarded := removeDuplicateSuffixes(txnOps, logger)
+		require.Equal(t, 0, discarded)
+		require.Equal(t, txnOps, ops)
+	})
+
+	t.Run("success - no operations", func(t *testing.T) {
+		ops, discarded := removeDuplicateSuffixes(nil, logger)
+		require.Equal(t, 0, discarded)
+		require.Empty(t, ops)
 	})
 }
 
@@ -117,16 +1007,153 @@ func (m *mockOperationStore) Get(suffix string) ([]*operation.AnchoredOperation,
 
 type mockTxnOpsProvider struct {
 	err error
+
+	// errAnchorString causes error only for transaction with the given anchor string
+	errAnchorString string
+	// errFunc is optional; it is applied to error returned for errAnchorString
+	errFunc func(err error) error
+	// ops is optional; if set, these operations are returned instead of default operation
+	ops []*operation.AnchoredOperation
+
+	// calls is the number of times transaction operations (CAS files) have been retrieved
+	calls int
 }
 
 func (m *mockTxnOpsProvider) GetTxnOperations(txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	return m.GetTxnOperationsContext(context.Background(), txn)
+}
+
+func (m *mockTxnOpsProvider) GetTxnOperationsContext(ctx context.Context, txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	m.calls++
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if m.err != nil {
 		return nil, m.err
 	}
 
+	if m.errAnchorString != "" && txn.AnchorString == m.errAnchorString {
+		err := fmt.Errorf("txn operations provider error for anchor string[%s]", txn.AnchorString)
+		if m.errFunc != nil {
+			err = m.errFunc(err)
+		}
+
+		return nil, err
+	}
+
+	if m.ops != nil {
+		return m.ops, nil
+	}
+
 	op := &operation.AnchoredOperation{
 		UniqueSuffix: "abc",
 	}
 
 	return []*operation.AnchoredOperation{op}, nil
 }
+
+type mockTxnSource struct {
+	txns []txn.SidetreeTxn
+	err  error
+}
+
+func (m *mockTxnSource) Next(_ context.Context) (txn.SidetreeTxn, error) {
+	if m.err != nil {
+		return txn.SidetreeTxn{}, m.err
+	}
+
+	if len(m.txns) == 0 {
+		return txn.SidetreeTxn{}, ErrNoMoreTransactions
+	}
+
+	next := m.txns[0]
+	m.txns = m.txns[1:]
+
+	return next, nil
+}
+
+type mockProcessedTxnStore struct {
+	processed        map[string]bool
+	isProcessedErr   error
+	markProcessedErr error
+}
+
+func newMockProcessedTxnStore() *mockProcessedTxnStore {
+	return &mockProcessedTxnStore{processed: make(map[string]bool)}
+}
+
+func (m *mockProcessedTxnStore) IsProcessed(sidetreeTxn txn.SidetreeTxn) (bool, error) {
+	if m.isProcessedErr != nil {
+		return false, m.isProcessedErr
+	}
+
+	return m.processed[txnKey(sidetreeTxn)], nil
+}
+
+func (m *mockProcessedTxnStore) MarkProcessed(sidetreeTxn txn.SidetreeTxn) error {
+	if m.markProcessedErr != nil {
+		return m.markProcessedErr
+	}
+
+	m.processed[txnKey(sidetreeTxn)] = true
+
+	return nil
+}
+
+func txnKey(sidetreeTxn txn.SidetreeTxn) string {
+	return fmt.Sprintf("%s-%d-%d", sidetreeTxn.Namespace, sidetreeTxn.TransactionTime, sidetreeTxn.TransactionNumber)
+}
+
+type mockMetrics struct {
+	counts map[operation.Type]int
+}
+
+func (m *mockMetrics) OperationsProcessed(opType operation.Type, count int) {
+	m.counts[opType] += count
+}
+
+type capturingLogger struct {
+	mutex sync.Mutex
+	logs  map[string][]string
+}
+
+func (l *capturingLogger) Debugf(msg string, args ...interface{}) {
+	l.log("debug", msg, args...)
+}
+
+func (l *capturingLogger) Infof(msg string, args ...interface{}) {
+	l.log("info", msg, args...)
+}
+
+func (l *capturingLogger) Warnf(msg string, args ...interface{}) {
+	l.log("warn", msg, args...)
+}
+
+func (l *capturingLogger) log(level, msg string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.logs == nil {
+		l.logs = make(map[string][]string)
+	}
+
+	l.logs[level] = append(l.logs[level], fmt.Sprintf(msg, args...))
+}
+
+func (l *capturingLogger) messages(level string) []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.logs[level]
+}
+
+type mockLedgerTimeProvider struct {
+	time uint64
+	err  error
+}
+
+func (m *mockLedgerTimeProvider) LedgerTime(context.Context) (uint64, error) {
+	return m.time, m.err
+}
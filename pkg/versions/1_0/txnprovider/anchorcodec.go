@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprovider
+
+// AnchorCodec encodes anchor data into anchor string (that is written to anchoring system)
+// and decodes anchor string back into anchor data.
+type AnchorCodec interface {
+	Encode(ad *AnchorData) string
+	Decode(anchorString string) (*AnchorData, error)
+}
+
+// DefaultAnchorCodec implements Sidetree anchor string format (count.uri or prefix.count.manifestURI).
+type DefaultAnchorCodec struct {
+	opts []AnchorDataOption
+}
+
+// NewDefaultAnchorCodec returns Sidetree anchor string codec that decodes anchor strings
+// using the given anchor data options (e.g. WithMaxOperationCount).
+func NewDefaultAnchorCodec(opts ...AnchorDataOption) *DefaultAnchorCodec {
+	return &DefaultAnchorCodec{opts: opts}
+}
+
+// Encode returns anchor string for the given anchor data.
+func (c *DefaultAnchorCodec) Encode(ad *AnchorData) string {
+	return ad.GetAnchorString()
+}
+
+// Decode parses anchor string into anchor data.
+func (c *DefaultAnchorCodec) Decode(anchorString string) (*AnchorData, error) {
+	return ParseAnchorData(anchorString, c.opts...)
+}
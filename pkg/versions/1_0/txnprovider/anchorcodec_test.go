@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprovider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+)
+
+func TestDefaultAnchorCodec(t *testing.T) {
+	codec := &DefaultAnchorCodec{}
+
+	ad, err := NewAnchorData(2, "coreIndexURI")
+	require.NoError(t, err)
+
+	anchorString := codec.Encode(ad)
+	require.Equal(t, "2.coreIndexURI", anchorString)
+
+	decoded, err := codec.Decode(anchorString)
+	require.NoError(t, err)
+	require.Equal(t, ad, decoded)
+
+	decoded, err = codec.Decode("invalid")
+	require.Error(t, err)
+	require.Nil(t, decoded)
+
+	t.Run("error - number of operations exceeds maximum operation count", func(t *testing.T) {
+		limited := NewDefaultAnchorCodec(WithMaxOperationCount(1))
+
+		decoded, err := limited.Decode(anchorString)
+		require.Error(t, err)
+		require.Nil(t, decoded)
+		require.Contains(t, err.Error(), "number of operations[2] exceeds maximum number of operations[1]")
+	})
+}
+
+func TestAnchorCodec(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := newMockProtocolClient().Protocol
+	parser := operationparser.New(p)
+
+	ops := getTestOperations(2, 1, 1, 1)
+
+	t.Run("success - custom codec round-trips through prepare and assemble", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		handler := NewOperationHandler(p, cas, cp, parser, WithHandlerAnchorCodec(&base64AnchorCodec{}))
+
+		anchorString, _, refs, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		decoded, err := base64.URLEncoding.DecodeString(anchorString)
+		require.NoError(t, err)
+		require.Contains(t, string(decoded), "5.")
+
+		provider := NewOperationProvider(p, parser, cas, cp, WithAnchorCodec(&base64AnchorCodec{}))
+
+		anchoredOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+		require.NoError(t, err)
+		require.Len(t, anchoredOps, len(refs))
+
+		require.NoError(t, handler.ValidatePreparedBatch(anchorString))
+
+		uris, err := provider.CollectCASURIs(&txn.SidetreeTxn{AnchorString: anchorString})
+		require.NoError(t, err)
+		require.NotEmpty(t, uris)
+	})
+
+	t.Run("error - provider with default codec", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		anchorString, _, _, err := NewOperationHandler(p, cas, cp, parser, WithHandlerAnchorCodec(&base64AnchorCodec{})).
+			PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		anchoredOps, err := NewOperationProvider(p, parser, cas, cp).
+			GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+		require.Error(t, err)
+		require.Nil(t, anchoredOps)
+		require.Contains(t, err.Error(), "parse anchor data")
+	})
+
+	t.Run("error - decode error", func(t *testing.T) {
+		anchoredOps, err := NewOperationProvider(p, parser, mocks.NewMockCasClient(nil), cp, WithAnchorCodec(&base64AnchorCodec{})).
+			GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: "!"})
+		require.Error(t, err)
+		require.Nil(t, anchoredOps)
+		require.Contains(t, err.Error(), "decode base64 anchor string")
+	})
+
+	t.Run("error - number of operations exceeds maximum", func(t *testing.T) {
+		ad, err := NewAnchorData(int(p.MaxOperationCount)+1, "coreIndexURI")
+		require.NoError(t, err)
+
+		codec := &base64AnchorCodec{DefaultAnchorCodec: *NewDefaultAnchorCodec(WithMaxOperationCount(p.MaxOperationCount))}
+		anchorString := codec.Encode(ad)
+
+		anchoredOps, err := NewOperationProvider(p, parser, mocks.NewMockCasClient(nil), cp, WithAnchorCodec(codec)).
+			GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+		require.Error(t, err)
+		require.Nil(t, anchoredOps)
+		require.Contains(t, err.Error(), fmt.Sprintf("number of operations[%d] exceeds maximum number of operations[%d]",
+			p.MaxOperationCount+1, p.MaxOperationCount))
+	})
+}
+
+// base64AnchorCodec wraps default anchor string format in base64url encoding.
+type base64AnchorCodec struct {
+	DefaultAnchorCodec
+}
+
+func (c *base64AnchorCodec) Encode(ad *AnchorData) string {
+	return base64.URLEncoding.EncodeToString([]byte(c.DefaultAnchorCodec.Encode(ad)))
+}
+
+func (c *base64AnchorCodec) Decode(anchorString string) (*AnchorData, error) {
+	decoded, err := base64.URLEncoding.DecodeString(anchorString)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 anchor string[%s]: %s", anchorString, err.Error())
+	}
+
+	return c.DefaultAnchorCodec.Decode(string(decoded))
+}
@@ -16,6 +16,12 @@ import (
 const (
 	delimiter    = "."
 	allowedParts = 2
+
+	// ManifestFormatPrefix is the prefix of versioned anchor string format
+	// (prefix.count.manifestURI) that references manifest file instead of core index file.
+	ManifestFormatPrefix = "v1"
+
+	versionedParts = 3
 )
 
 // nolint:gochecknoglobals
@@ -27,33 +33,144 @@ var (
 type AnchorData struct {
 	NumberOfOperations int
 	CoreIndexFileURI   string
+
+	// ManifestURI is set (instead of core index file URI) for versioned anchor string format;
+	// core index file URI is resolved from manifest file
+	ManifestURI string
+}
+
+// NewAnchorData creates anchor data from number of operations and core index file URI.
+// It can be used to compute expected anchor string for a known set of operations without writing batch files.
+func NewAnchorData(numberOfOperations int, coreIndexFileURI string) (*AnchorData, error) {
+	if numberOfOperations <= 0 {
+		return nil, fmt.Errorf("create anchor data failed: number of operations must be positive integer, got [%d]", numberOfOperations)
+	}
+
+	if coreIndexFileURI == "" {
+		return nil, fmt.Errorf("create anchor data failed: missing core index file URI")
+	}
+
+	if strings.Contains(coreIndexFileURI, delimiter) {
+		return nil, fmt.Errorf("create anchor data failed: core index file URI[%s] must not contain '%s'", coreIndexFileURI, delimiter)
+	}
+
+	return &AnchorData{
+		NumberOfOperations: numberOfOperations,
+		CoreIndexFileURI:   coreIndexFileURI,
+	}, nil
+}
+
+// NewManifestAnchorData creates anchor data from number of operations and manifest file URI.
+// Anchor string for this anchor data is in versioned format (prefix.count.manifestURI).
+func NewManifestAnchorData(numberOfOperations int, manifestURI string) (*AnchorData, error) {
+	// manifest URI has the same constraints as core index file URI
+	ad, err := NewAnchorData(numberOfOperations, manifestURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnchorData{
+		NumberOfOperations: ad.NumberOfOperations,
+		ManifestURI:        ad.CoreIndexFileURI,
+	}, nil
+}
+
+// AnchorDataOption is an option for parsing anchor data.
+type AnchorDataOption func(opts *anchorDataOptions)
+
+type anchorDataOptions struct {
+	maxOperationCount uint
+}
+
+// WithMaxOperationCount sets maximum number of operations allowed in anchor string (e.g. protocol MaxOperationCount).
+// Zero means that number of operations is not limited.
+func WithMaxOperationCount(max uint) AnchorDataOption {
+	return func(opts *anchorDataOptions) {
+		opts.maxOperationCount = max
+	}
 }
 
 // ParseAnchorData will parse anchor string into anchor data model.
-func ParseAnchorData(data string) (*AnchorData, error) {
+func ParseAnchorData(data string, opts ...AnchorDataOption) (*AnchorData, error) {
+	options := &anchorDataOptions{}
+
+	// apply options
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// reject empty, whitespace-only and delimiter-only anchor strings up-front with a clear message
+	if strings.TrimSpace(strings.ReplaceAll(data, delimiter, "")) == "" {
+		return nil, fmt.Errorf("parse anchor data[%s] failed: anchor string is empty", data)
+	}
+
 	parts := strings.Split(data, delimiter)
 
+	// legacy format (count.uri) starts with number of operations; otherwise first part is format prefix
+	if len(parts) == versionedParts && !integerRegex.MatchString(parts[0]) {
+		return parseVersionedAnchorData(data, parts, options)
+	}
+
 	if len(parts) != allowedParts {
 		return nil, fmt.Errorf("parse anchor data[%s] failed: expecting [%d] parts, got [%d] parts", data, allowedParts, len(parts))
 	}
 
-	ok := integerRegex.MatchString(parts[0])
-	if !ok {
-		return nil, fmt.Errorf("parse anchor data[%s] failed: number of operations must be positive integer", data)
+	opsNum, err := parseNumberOfOperations(data, parts[0], options)
+	if err != nil {
+		return nil, err
 	}
 
-	opsNum, err := strconv.Atoi(parts[0])
+	return &AnchorData{
+		NumberOfOperations: opsNum,
+		CoreIndexFileURI:   parts[1],
+	}, nil
+}
+
+func parseVersionedAnchorData(data string, parts []string, options *anchorDataOptions) (*AnchorData, error) {
+	if parts[0] != ManifestFormatPrefix {
+		return nil, fmt.Errorf("parse anchor data[%s] failed: unknown anchor string format prefix[%s]", data, parts[0])
+	}
+
+	opsNum, err := parseNumberOfOperations(data, parts[1], options)
 	if err != nil {
-		return nil, fmt.Errorf("parse anchor data[%s] failed: %s", data, err.Error())
+		return nil, err
+	}
+
+	if parts[2] == "" {
+		return nil, fmt.Errorf("parse anchor data[%s] failed: missing manifest URI", data)
 	}
 
 	return &AnchorData{
 		NumberOfOperations: opsNum,
-		CoreIndexFileURI:   parts[1],
+		ManifestURI:        parts[2],
 	}, nil
 }
 
+func parseNumberOfOperations(data, value string, options *anchorDataOptions) (int, error) {
+	ok := integerRegex.MatchString(value)
+	if !ok {
+		return 0, fmt.Errorf("parse anchor data[%s] failed: number of operations must be positive integer", data)
+	}
+
+	opsNum, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse anchor data[%s] failed: %s", data, err.Error())
+	}
+
+	if options.maxOperationCount > 0 && uint(opsNum) > options.maxOperationCount {
+		return 0, fmt.Errorf("parse anchor data[%s] failed: number of operations[%d] exceeds maximum number of operations[%d]",
+			data, opsNum, options.maxOperationCount)
+	}
+
+	return opsNum, nil
+}
+
 // GetAnchorString will create anchor string from anchor data.
+// Versioned format (prefix.count.manifestURI) is used if manifest URI is set.
 func (ad *AnchorData) GetAnchorString() string {
+	if ad.ManifestURI != "" {
+		return ManifestFormatPrefix + delimiter + fmt.Sprintf("%d", ad.NumberOfOperations) + delimiter + ad.ManifestURI
+	}
+
 	return fmt.Sprintf("%d", ad.NumberOfOperations) + delimiter + ad.CoreIndexFileURI
 }
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package txnprovider
 
 import (
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,6 +24,62 @@ func TestParseAnchorData(t *testing.T) {
 		require.Equal(t, ad.CoreIndexFileURI, "coreIndexURI")
 	})
 
+	t.Run("success - legacy format", func(t *testing.T) {
+		ad, err := ParseAnchorData("1.coreIndexURI")
+		require.NoError(t, err)
+		require.Equal(t, &AnchorData{NumberOfOperations: 1, CoreIndexFileURI: "coreIndexURI"}, ad)
+		require.Equal(t, "1.coreIndexURI", ad.GetAnchorString())
+	})
+
+	t.Run("success - manifest format", func(t *testing.T) {
+		ad, err := ParseAnchorData("v1.101.manifestURI")
+		require.NoError(t, err)
+		require.Equal(t, &AnchorData{NumberOfOperations: 101, ManifestURI: "manifestURI"}, ad)
+		require.Equal(t, "v1.101.manifestURI", ad.GetAnchorString())
+	})
+
+	t.Run("error - unknown format prefix", func(t *testing.T) {
+		for _, anchorString := range []string{"v2.1.manifestURI", "abc.1.manifestURI", ".1.manifestURI"} {
+			ad, err := ParseAnchorData(anchorString)
+			require.Error(t, err)
+			require.Nil(t, ad)
+			require.Contains(t, err.Error(), "unknown anchor string format prefix")
+		}
+
+		_, err := ParseAnchorData("v2.1.manifestURI")
+		require.EqualError(t, err, "parse anchor data[v2.1.manifestURI] failed: unknown anchor string format prefix[v2]")
+	})
+
+	t.Run("error - manifest format with invalid number of operations", func(t *testing.T) {
+		ad, err := ParseAnchorData("v1.abc.manifestURI")
+		require.Error(t, err)
+		require.Nil(t, ad)
+		require.Contains(t, err.Error(), "number of operations must be positive integer")
+	})
+
+	t.Run("error - manifest format with number of operations that exceeds maximum", func(t *testing.T) {
+		ad, err := ParseAnchorData("v1.11.manifestURI", WithMaxOperationCount(10))
+		require.Error(t, err)
+		require.Nil(t, ad)
+		require.Contains(t, err.Error(), "number of operations[11] exceeds maximum number of operations[10]")
+	})
+
+	t.Run("error - manifest format with missing manifest URI", func(t *testing.T) {
+		ad, err := ParseAnchorData("v1.1.")
+		require.Error(t, err)
+		require.Nil(t, ad)
+		require.Contains(t, err.Error(), "parse anchor data[v1.1.] failed: missing manifest URI")
+	})
+
+	t.Run("error - empty anchor string", func(t *testing.T) {
+		for _, anchorString := range []string{"", " ", "\t\n", ".", "..", " . ", "..."} {
+			ad, err := ParseAnchorData(anchorString)
+			require.Error(t, err)
+			require.Nil(t, ad)
+			require.EqualError(t, err, fmt.Sprintf("parse anchor data[%s] failed: anchor string is empty", anchorString))
+		}
+	})
+
 	t.Run("error - invalid number of parts", func(t *testing.T) {
 		ad, err := ParseAnchorData("1.coreIndexURI.other")
 		require.Error(t, err)
@@ -53,4 +111,98 @@ func TestParseAnchorData(t *testing.T) {
 
 		require.Contains(t, err.Error(), "number of operations must be positive integer")
 	})
+
+	t.Run("error - number of operations is zero", func(t *testing.T) {
+		ad, err := ParseAnchorData("0.coreIndexURI")
+		require.Error(t, err)
+		require.Nil(t, ad)
+
+		require.Contains(t, err.Error(), "number of operations must be positive integer")
+	})
+
+	t.Run("error - number of operations overflows", func(t *testing.T) {
+		ad, err := ParseAnchorData("99999999999999999999999.coreIndexURI")
+		require.Error(t, err)
+		require.Nil(t, ad)
+
+		require.Contains(t, err.Error(), "parse anchor data[99999999999999999999999.coreIndexURI] failed")
+	})
+
+	t.Run("success - number of operations equals maximum", func(t *testing.T) {
+		ad, err := ParseAnchorData("10.coreIndexURI", WithMaxOperationCount(10))
+		require.NoError(t, err)
+		require.Equal(t, 10, ad.NumberOfOperations)
+	})
+
+	t.Run("error - number of operations exceeds maximum", func(t *testing.T) {
+		ad, err := ParseAnchorData("11.coreIndexURI", WithMaxOperationCount(10))
+		require.Error(t, err)
+		require.Nil(t, ad)
+
+		require.Contains(t, err.Error(),
+			"parse anchor data[11.coreIndexURI] failed: number of operations[11] exceeds maximum number of operations[10]")
+	})
+}
+
+func TestNewAnchorData(t *testing.T) {
+	t.Run("success - round trip", func(t *testing.T) {
+		for _, numOps := range []int{1, 2, 10, 101, 10000, math.MaxInt32} {
+			ad, err := NewAnchorData(numOps, "coreIndexURI")
+			require.NoError(t, err)
+
+			anchorString := ad.GetAnchorString()
+			require.Equal(t, fmt.Sprintf("%d.coreIndexURI", numOps), anchorString)
+
+			parsed, err := ParseAnchorData(anchorString)
+			require.NoError(t, err)
+			require.Equal(t, ad, parsed)
+		}
+	})
+
+	t.Run("success - manifest anchor data round trip", func(t *testing.T) {
+		ad, err := NewManifestAnchorData(5, "manifestURI")
+		require.NoError(t, err)
+
+		anchorString := ad.GetAnchorString()
+		require.Equal(t, "v1.5.manifestURI", anchorString)
+
+		parsed, err := ParseAnchorData(anchorString)
+		require.NoError(t, err)
+		require.Equal(t, ad, parsed)
+	})
+
+	t.Run("error - invalid manifest anchor data", func(t *testing.T) {
+		ad, err := NewManifestAnchorData(0, "manifestURI")
+		require.Error(t, err)
+		require.Nil(t, ad)
+		require.Contains(t, err.Error(), "number of operations must be positive integer")
+
+		ad, err = NewManifestAnchorData(1, "manifest.URI")
+		require.Error(t, err)
+		require.Nil(t, ad)
+		require.Contains(t, err.Error(), "must not contain '.'")
+	})
+
+	t.Run("error - number of operations is not positive", func(t *testing.T) {
+		for _, numOps := range []int{0, -1} {
+			ad, err := NewAnchorData(numOps, "coreIndexURI")
+			require.Error(t, err)
+			require.Nil(t, ad)
+			require.Contains(t, err.Error(), "number of operations must be positive integer")
+		}
+	})
+
+	t.Run("error - missing core index file URI", func(t *testing.T) {
+		ad, err := NewAnchorData(1, "")
+		require.Error(t, err)
+		require.Nil(t, ad)
+		require.Contains(t, err.Error(), "missing core index file URI")
+	})
+
+	t.Run("error - core index file URI contains delimiter", func(t *testing.T) {
+		ad, err := NewAnchorData(1, "core.IndexURI")
+		require.Error(t, err)
+		require.Nil(t, ad)
+		require.Contains(t, err.Error(), "core index file URI[core.IndexURI] must not contain '.'")
+	})
 }
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+// ErrBatchFull is returned by BatchBuilder.Add if operation cannot be added to the batch without exceeding
+// maximum number of operations or maximum size of any of the batch files; batch should be finalized.
+var ErrBatchFull = errors.New("batch is full")
+
+// BatchBuilder accumulates operations into a batch that fits into a single chunk file and doesn't exceed
+// protocol maximum sizes of index and proof files. Projected sizes of batch files are tracked as operations
+// are added (batch files are not written to CAS until Finalize).
+type BatchBuilder struct {
+	handler  *OperationHandler
+	ops      []*operation.QueuedOperation
+	suffixes map[string]bool
+	sizes    *TxnFileSizes
+}
+
+// NewBatchBuilder returns new batch builder that uses the given operation handler to prepare batch files.
+func NewBatchBuilder(handler *OperationHandler) *BatchBuilder {
+	return &BatchBuilder{
+		handler:  handler,
+		suffixes: make(map[string]bool),
+		sizes:    &TxnFileSizes{},
+	}
+}
+
+// Add adds operation to the batch. ErrBatchFull is returned (and operation is not added) if batch would
+// exceed protocol maximum number of operations, if deltas would not fit into a single chunk file or if
+// any other batch file would exceed protocol maximum file size. Each call prepares all batch files of
+// the projected batch (without writing them to CAS) since compressed file sizes cannot be summed up.
+func (b *BatchBuilder) Add(op *operation.QueuedOperation) error {
+	if b.suffixes[op.UniqueSuffix] {
+		return fmt.Errorf("operation for suffix[%s] is already in the batch", op.UniqueSuffix)
+	}
+
+	maxOperationCount := b.handler.protocol.MaxOperationCount
+	if maxOperationCount > 0 && uint(len(b.ops)) >= maxOperationCount {
+		return fmt.Errorf("%w: maximum number of operations[%d] reached", ErrBatchFull, maxOperationCount)
+	}
+
+	ops := append(append([]*operation.QueuedOperation{}, b.ops...), op)
+
+	sizes, err := b.handler.PrepareTxnFilesDryRun(ops)
+	if err != nil {
+		// operation that doesn't fit into an empty batch cannot be batched at all
+		if errors.Is(err, ErrMaxFileSizeExceeded) && len(b.ops) > 0 {
+			return fmt.Errorf("%w: operation for suffix[%s]: %s", ErrBatchFull, op.UniqueSuffix, err.Error())
+		}
+
+		return fmt.Errorf("add operation for suffix[%s] to the batch: %s", op.UniqueSuffix, err.Error())
+	}
+
+	if len(sizes.Chunks) > 1 {
+		return fmt.Errorf("%w: operation for suffix[%s] would exceed maximum chunk file size[%d]",
+			ErrBatchFull, op.UniqueSuffix, b.handler.protocol.MaxChunkFileSize)
+	}
+
+	b.ops = ops
+	b.suffixes[op.UniqueSuffix] = true
+	b.sizes = sizes
+
+	return nil
+}
+
+// Len returns number of operations in the batch.
+func (b *BatchBuilder) Len() int {
+	return len(b.ops)
+}
+
+// Sizes returns projected sizes of batch files for operations added so far.
+func (b *BatchBuilder) Sizes() *TxnFileSizes {
+	return b.sizes
+}
+
+// Finalize writes batch files to CAS and returns anchor string, batch files information and operations
+// (same as OperationHandler.PrepareTxnFiles). Builder is reset after successful finalization.
+func (b *BatchBuilder) Finalize() (string, []*protocol.AnchorDocument, []*operation.Reference, error) {
+	return b.FinalizeContext(context.Background())
+}
+
+// FinalizeContext is the same as Finalize but it stops writing batch files to CAS once the given context is done.
+func (b *BatchBuilder) FinalizeContext(ctx context.Context) (string, []*protocol.AnchorDocument, []*operation.Reference, error) {
+	if len(b.ops) == 0 {
+		return "", nil, nil, errors.New("finalize batch: batch is empty")
+	}
+
+	anchorString, artifacts, refs, err := b.handler.PrepareTxnFilesContext(ctx, b.ops)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	b.ops = nil
+	b.suffixes = make(map[string]bool)
+	b.sizes = &TxnFileSizes{}
+
+	return anchorString, artifacts, refs, nil
+}
@@ -0,0 +1,263 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txnprovider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+)
+
+func TestBatchBuilder(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	ops := generateOperations(20, operation.TypeCreate)
+
+	t.Run("success - add until chunk file size limit and finalize", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+		p.MaxOperationCount = 100
+
+		// maximum chunk file size fits chunk file with deltas of (at least) the first few operations
+		sizes, err := NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)).
+			PrepareTxnFilesDryRun(ops[:5])
+		require.NoError(t, err)
+
+		p.MaxChunkFileSize = uint(sizes.Chunks[0])
+
+		cas := mocks.NewMockCasClient(nil)
+		builder := NewBatchBuilder(NewOperationHandler(p, cas, cp, operationparser.New(p)))
+
+		var added int
+
+		for _, op := range ops {
+			err = builder.Add(op)
+			if err != nil {
+				break
+			}
+
+			added++
+
+			require.Equal(t, added, builder.Len())
+			require.Len(t, builder.Sizes().Chunks, 1)
+			require.LessOrEqual(t, builder.Sizes().Chunks[0], int(p.MaxChunkFileSize))
+		}
+
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrBatchFull))
+		require.Contains(t, err.Error(), "would exceed maximum chunk file size")
+		require.Greater(t, added, 1)
+		require.Less(t, added, len(ops))
+
+		// rejected operation would cause deltas to be split into multiple chunk files
+		sizes, err = builder.handler.PrepareTxnFilesDryRun(ops[:added+1])
+		require.NoError(t, err)
+		require.Greater(t, len(sizes.Chunks), 1)
+
+		projected := builder.Sizes()
+
+		anchorString, artifacts, refs, err := builder.Finalize()
+		require.NoError(t, err)
+		require.Len(t, refs, added)
+		require.Equal(t, 0, builder.Len())
+
+		for _, artifact := range artifacts {
+			if artifact.Desc == "chunk file" {
+				bytes, err := cas.Read(artifact.ID)
+				require.NoError(t, err)
+				require.Equal(t, projected.Chunks[0], len(bytes))
+			}
+		}
+
+		anchoredOps, err := NewOperationProvider(p, operationparser.New(p), cas, cp).
+			GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+		require.NoError(t, err)
+		require.Len(t, anchoredOps, added)
+
+		// operation that didn't fit can be added to the next batch
+		require.NoError(t, builder.Add(ops[added]))
+		require.Equal(t, 1, builder.Len())
+	})
+
+	t.Run("error - maximum file sizes", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			opType operation.Type
+			file   string
+			size   func(sizes *TxnFileSizes) int
+			limit  func(p *protocol.Protocol, size uint)
+		}{
+			{
+				name:   "core index file",
+				opType: operation.TypeCreate,
+				file:   "core index file",
+				size:   func(sizes *TxnFileSizes) int { return sizes.CoreIndex },
+				limit:  func(p *protocol.Protocol, size uint) { p.MaxCoreIndexFileSize = size },
+			},
+			{
+				name:   "core proof file",
+				opType: operation.TypeDeactivate,
+				file:   "core proof file",
+				size:   func(sizes *TxnFileSizes) int { return sizes.CoreProof },
+				limit:  func(p *protocol.Protocol, size uint) { p.MaxProofFileSize = size },
+			},
+			{
+				name:   "provisional index file",
+				opType: operation.TypeUpdate,
+				file:   "provisional index file",
+				size:   func(sizes *TxnFileSizes) int { return sizes.ProvisionalIndex },
+				limit:  func(p *protocol.Protocol, size uint) { p.MaxProvisionalIndexFileSize = size },
+			},
+			{
+				name:   "provisional proof file",
+				opType: operation.TypeUpdate,
+				file:   "provisional proof file",
+				size:   func(sizes *TxnFileSizes) int { return sizes.ProvisionalProof },
+				limit:  func(p *protocol.Protocol, size uint) { p.MaxProofFileSize = size },
+			},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				p := newMockProtocolClient().Protocol
+
+				typeOps := generateOperations(10, tc.opType)
+
+				// maximum file size fits the file for the first few operations
+				sizes, err := NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)).
+					PrepareTxnFilesDryRun(typeOps[:3])
+				require.NoError(t, err)
+
+				tc.limit(&p, uint(tc.size(sizes)))
+
+				cas := mocks.NewMockCasClient(nil)
+				builder := NewBatchBuilder(NewOperationHandler(p, cas, cp, operationparser.New(p)))
+
+				var added int
+
+				for _, op := range typeOps {
+					err = builder.Add(op)
+					if err != nil {
+						break
+					}
+
+					added++
+
+					require.LessOrEqual(t, tc.size(builder.Sizes()), int(tc.size(sizes)))
+				}
+
+				require.Error(t, err)
+				require.True(t, errors.Is(err, ErrBatchFull))
+				require.Contains(t, err.Error(), tc.file+" size")
+				require.Contains(t, err.Error(), "exceeds maximum file size")
+				require.GreaterOrEqual(t, added, 3)
+				require.Less(t, added, len(typeOps))
+
+				// handler rejects the batch that builder rejected
+				_, _, _, err = builder.handler.PrepareTxnFiles(typeOps[:added+1])
+				require.True(t, errors.Is(err, ErrMaxFileSizeExceeded))
+
+				// batch accepted by builder is accepted by observers
+				anchorString, _, _, err := builder.Finalize()
+				require.NoError(t, err)
+
+				anchoredOps, err := NewOperationProvider(p, operationparser.New(p), cas, cp).
+					GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+				require.NoError(t, err)
+				require.Len(t, anchoredOps, added)
+			})
+		}
+	})
+
+	t.Run("error - operation exceeds maximum file size of empty batch", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+		p.MaxCoreIndexFileSize = 10
+
+		builder := NewBatchBuilder(NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)))
+
+		err := builder.Add(ops[0])
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ErrBatchFull))
+		require.Contains(t, err.Error(), "core index file size")
+		require.Equal(t, 0, builder.Len())
+	})
+
+	t.Run("error - maximum number of operations", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+		p.MaxOperationCount = 2
+
+		builder := NewBatchBuilder(NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)))
+
+		require.NoError(t, builder.Add(ops[0]))
+		require.NoError(t, builder.Add(ops[1]))
+
+		err := builder.Add(ops[2])
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrBatchFull))
+		require.Contains(t, err.Error(), "maximum number of operations[2] reached")
+		require.Equal(t, 2, builder.Len())
+	})
+
+	t.Run("error - operation for the same suffix", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+
+		builder := NewBatchBuilder(NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)))
+
+		require.NoError(t, builder.Add(ops[0]))
+
+		err := builder.Add(ops[0])
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is already in the batch")
+		require.Equal(t, 1, builder.Len())
+	})
+
+	t.Run("error - invalid operation", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+
+		builder := NewBatchBuilder(NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)))
+
+		err := builder.Add(&operation.QueuedOperation{UniqueSuffix: "suffix", OperationBuffer: []byte("invalid")})
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ErrBatchFull))
+		require.Contains(t, err.Error(), "add operation for suffix[suffix] to the batch")
+		require.Equal(t, 0, builder.Len())
+	})
+
+	t.Run("error - finalize empty batch", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+
+		builder := NewBatchBuilder(NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)))
+
+		anchorString, artifacts, refs, err := builder.Finalize()
+		require.EqualError(t, err, "finalize batch: batch is empty")
+		require.Empty(t, anchorString)
+		require.Nil(t, artifacts)
+		require.Nil(t, refs)
+	})
+
+	t.Run("error - CAS error", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+
+		builder := NewBatchBuilder(NewOperationHandler(p, mocks.NewMockCasClient(errors.New("CAS error")), cp, operationparser.New(p)))
+
+		require.NoError(t, builder.Add(ops[0]))
+
+		_, _, _, err := builder.Finalize()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "CAS error")
+		require.Equal(t, 1, builder.Len())
+	})
+}
@@ -7,18 +7,28 @@ SPDX-License-Identifier: Apache-2.0
 package txnprovider
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider/models"
 )
 
+// ErrMaxFileSizeExceeded is returned if batch file would exceed protocol maximum file size (observers would
+// reject such batch file).
+var ErrMaxFileSizeExceeded = errors.New("maximum file size exceeded")
+
 type compressionProvider interface {
 	Compress(alg string, data []byte) ([]byte, error)
 }
@@ -29,41 +39,134 @@ type OperationHandler struct {
 	protocol protocol.Protocol
 	parser   OperationParser
 	cp       compressionProvider
+
+	anchorCodec  AnchorCodec
+	skipExisting bool
+}
+
+// HandlerOption is an operation handler instance option.
+type HandlerOption func(opts *OperationHandler)
+
+// WithHandlerAnchorCodec sets codec for encoding anchor strings (default is Sidetree anchor string format).
+// Operation provider has to be configured with the same codec (see WithAnchorCodec).
+func WithHandlerAnchorCodec(codec AnchorCodec) HandlerOption {
+	return func(opts *OperationHandler) {
+		opts.anchorCodec = codec
+	}
+}
+
+// WithSkipExistingContent enables probing CAS before writing batch files; files that CAS already holds are not
+// written again. Probing requires CAS client that implements cas.SizeClient; it costs an additional CAS
+// round trip for each batch file so it only pays off if batch files (e.g. proof files) are often repeated.
+func WithSkipExistingContent() HandlerOption {
+	return func(opts *OperationHandler) {
+		opts.skipExisting = true
+	}
 }
 
 // NewOperationHandler returns new operations handler.
-func NewOperationHandler(p protocol.Protocol, cas cas.Client, cp compressionProvider, parser OperationParser) *OperationHandler {
-	return &OperationHandler{cas: cas, protocol: p, cp: cp, parser: parser}
+// Protocol parameters are expected to be validated by the caller (see protocol.Protocol.Validate).
+func NewOperationHandler(p protocol.Protocol, cas cas.Client, cp compressionProvider, parser OperationParser, opts ...HandlerOption) *OperationHandler {
+	h := &OperationHandler{cas: cas, protocol: p, cp: cp, parser: parser, anchorCodec: &DefaultAnchorCodec{}}
+
+	// apply options
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // PrepareTxnFiles will create batch files(core index, core proof, provisional index, provisional proof and chunk)
 // from batch operation and return anchor string, batch files information and operations.
-func (h *OperationHandler) PrepareTxnFiles(ops []*operation.QueuedOperation) (string, []*protocol.AnchorDocument, []*operation.Reference, error) { //nolint:funlen
+// Operations of each type are ordered by unique suffix within batch files (and returned operation references
+// are ordered by unique suffix) so that the same set of operations always results in the same batch files
+// and anchor string regardless of the order of queued operations. If there are multiple operations for
+// the same unique suffix only the first one is included.
+// If WithSkipExistingContent option is set, batch files that CAS already holds are not written again.
+func (h *OperationHandler) PrepareTxnFiles(ops []*operation.QueuedOperation) (string, []*protocol.AnchorDocument, []*operation.Reference, error) {
+	return h.PrepareTxnFilesContext(context.Background(), ops)
+}
+
+// PrepareOption is an option for preparing batch files.
+type PrepareOption func(opts *prepareOptions)
+
+type prepareOptions struct {
+	compressionAlgorithm string
+}
+
+// WithCompressionAlgorithm overrides protocol compression algorithm for batch files; algorithm has to be
+// registered with compression provider. Operation provider decompresses batch files using compression algorithm
+// from protocol so batch files are readable only by operation provider configured with the same algorithm.
+func WithCompressionAlgorithm(alg string) PrepareOption {
+	return func(opts *prepareOptions) {
+		opts.compressionAlgorithm = alg
+	}
+}
+
+// PrepareTxnFilesContext is the same as PrepareTxnFiles but it stops writing batch files to CAS
+// once the given context is done. Options (e.g. compression algorithm override) apply to this call only.
+func (h *OperationHandler) PrepareTxnFilesContext(ctx context.Context, ops []*operation.QueuedOperation, opts ...PrepareOption) (string, []*protocol.AnchorDocument, []*operation.Reference, error) {
+	options := &prepareOptions{}
+
+	// apply options
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.compressionAlgorithm == "" || options.compressionAlgorithm == h.protocol.CompressionAlgorithm {
+		return h.prepareTxnFiles(ctx, ops)
+	}
+
+	if registry, ok := h.cp.(protocol.CompressionRegistry); ok && !registry.IsSupported(options.compressionAlgorithm) {
+		return "", nil, nil, fmt.Errorf("compression algorithm[%s] is not supported", options.compressionAlgorithm)
+	}
+
+	handler := *h
+	handler.protocol.CompressionAlgorithm = options.compressionAlgorithm
+
+	return handler.prepareTxnFiles(ctx, ops)
+}
+
+func (h *OperationHandler) prepareTxnFiles(ctx context.Context, ops []*operation.QueuedOperation) (string, []*protocol.AnchorDocument, []*operation.Reference, error) {
 	parsedOps, dids, err := h.parseOperations(ops)
 	if err != nil {
 		return "", nil, nil, err
 	}
 
+	anchorString, artifacts, err := h.createTxnFiles(ctx, parsedOps, len(ops))
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return anchorString, artifacts, dids, nil
+}
+
+// createTxnFiles creates batch files from parsed operations and writes them to CAS; numOps is the number of
+// queued operations (including discarded ones). Returns anchor string and batch file artifacts.
+func (h *OperationHandler) createTxnFiles(ctx context.Context, parsedOps *models.SortedOperations, numOps int) (string, []*protocol.AnchorDocument, error) { //nolint:funlen
 	var artifacts []*protocol.AnchorDocument
 
 	// special case: if all ops are deactivate don't create chunk and provisional files
 	provisionalIndexURI := ""
-	if len(parsedOps.Deactivate) != len(ops) {
-		chunkURI, innerErr := h.createChunkFile(parsedOps)
+	if len(parsedOps.Deactivate) != numOps {
+		chunkURIs, innerErr := h.createChunkFiles(ctx, parsedOps)
 		if innerErr != nil {
-			return "", nil, nil, innerErr
+			return "", nil, innerErr
 		}
 
-		artifacts = append(artifacts,
-			&protocol.AnchorDocument{
-				ID:   chunkURI,
-				Desc: "chunk file",
-				Type: protocol.TypeProvisional,
-			})
+		for _, chunkURI := range chunkURIs {
+			artifacts = append(artifacts,
+				&protocol.AnchorDocument{
+					ID:   chunkURI,
+					Desc: "chunk file",
+					Type: protocol.TypeProvisional,
+				})
+		}
 
-		provisionalProofURI, innerErr := h.createProvisionalProofFile(parsedOps.Update)
+		provisionalProofURI, innerErr := h.createProvisionalProofFile(ctx, parsedOps.Update)
 		if innerErr != nil {
-			return "", nil, nil, innerErr
+			return "", nil, innerErr
 		}
 
 		if provisionalProofURI != "" {
@@ -75,9 +178,9 @@ func (h *OperationHandler) PrepareTxnFiles(ops []*operation.QueuedOperation) (st
 				})
 		}
 
-		provisionalIndexURI, innerErr = h.createProvisionalIndexFile([]string{chunkURI}, provisionalProofURI, parsedOps.Update)
+		provisionalIndexURI, innerErr = h.createProvisionalIndexFile(ctx, chunkURIs, provisionalProofURI, parsedOps.Update)
 		if innerErr != nil {
-			return "", nil, nil, innerErr
+			return "", nil, innerErr
 		}
 
 		artifacts = append(artifacts,
@@ -88,9 +191,9 @@ func (h *OperationHandler) PrepareTxnFiles(ops []*operation.QueuedOperation) (st
 			})
 	}
 
-	coreProofURI, err := h.createCoreProofFile(parsedOps.Recover, parsedOps.Deactivate)
+	coreProofURI, err := h.createCoreProofFile(ctx, parsedOps.Recover, parsedOps.Deactivate)
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, err
 	}
 
 	if coreProofURI != "" {
@@ -102,9 +205,9 @@ func (h *OperationHandler) PrepareTxnFiles(ops []*operation.QueuedOperation) (st
 			})
 	}
 
-	coreIndexURI, err := h.createCoreIndexFile(coreProofURI, provisionalIndexURI, parsedOps)
+	coreIndexURI, err := h.createCoreIndexFile(ctx, coreProofURI, provisionalIndexURI, parsedOps)
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, err
 	}
 
 	artifacts = append(artifacts,
@@ -114,12 +217,136 @@ func (h *OperationHandler) PrepareTxnFiles(ops []*operation.QueuedOperation) (st
 			Type: protocol.TypePermanent,
 		})
 
-	ad := AnchorData{
-		NumberOfOperations: parsedOps.Size(),
-		CoreIndexFileURI:   coreIndexURI,
+	ad, err := NewAnchorData(parsedOps.Size(), coreIndexURI)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return h.anchorCodec.Encode(ad), artifacts, nil
+}
+
+// TxnFileSizes contains sizes (in bytes) of compressed batch files; zero size means that file is not created.
+type TxnFileSizes struct {
+	CoreIndex        int
+	CoreProof        int
+	ProvisionalIndex int
+	ProvisionalProof int
+	Chunks           []int
+}
+
+// Total returns total size of batch files.
+func (s *TxnFileSizes) Total() int {
+	total := s.CoreIndex + s.CoreProof + s.ProvisionalIndex + s.ProvisionalProof
+
+	for _, size := range s.Chunks {
+		total += size
 	}
 
-	return ad.GetAnchorString(), artifacts, dids, nil
+	return total
+}
+
+// PrepareTxnFilesDryRun will create batch files from batch operations (same as PrepareTxnFiles) without
+// writing them to CAS and return sizes of created files. CAS URIs referenced from index files are
+// calculated by CAS client if it implements cas.AddressClient; otherwise they are calculated as base64url
+// encoded multihash (using the first of protocol multihash algorithms) of file content.
+func (h *OperationHandler) PrepareTxnFilesDryRun(ops []*operation.QueuedOperation) (*TxnFileSizes, error) {
+	parsedOps, _, err := h.parseOperations(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.dryRun(parsedOps, len(ops))
+}
+
+// dryRun creates batch files from parsed operations without writing them to CAS and returns sizes of
+// created files (as recorded by file type by dry run CAS client).
+func (h *OperationHandler) dryRun(parsedOps *models.SortedOperations, numOps int) (*TxnFileSizes, error) {
+	dryRunCAS := &dryRunCASClient{address: h.contentAddress, sizes: &TxnFileSizes{}}
+
+	handler := *h
+	handler.cas = dryRunCAS
+
+	_, _, err := handler.createTxnFiles(context.Background(), parsedOps, numOps)
+	if err != nil {
+		return nil, err
+	}
+
+	return dryRunCAS.sizes, nil
+}
+
+// SizeEstimate contains estimated sizes (in bytes) of compressed batch files for a set of operations.
+type SizeEstimate struct {
+	// Files contains sizes of batch files prepared for all operations
+	Files *TxnFileSizes
+	// Total is the total size of batch files prepared for all operations
+	Total int
+	// Operations contains (per operation type) total size of batch files prepared for operations of that type only;
+	// because of compression and per-file overhead sum of sizes per operation type may differ from total size
+	Operations map[operation.Type]int
+}
+
+// EstimateSizes estimates sizes of batch files for the given operations (see PrepareTxnFilesDryRun) together
+// with breakdown by batch file type and by operation type. Batch files are not written to CAS.
+// Operations are parsed once; batch files for each operation type are created from the parsed operations.
+func (h *OperationHandler) EstimateSizes(ops []*operation.QueuedOperation) (*SizeEstimate, error) {
+	parsedOps, _, err := h.parseOperations(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := h.dryRun(parsedOps, len(ops))
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &SizeEstimate{
+		Files:      files,
+		Total:      files.Total(),
+		Operations: make(map[operation.Type]int),
+	}
+
+	opsByType := map[operation.Type]*models.SortedOperations{
+		operation.TypeCreate:     {Create: parsedOps.Create},
+		operation.TypeUpdate:     {Update: parsedOps.Update},
+		operation.TypeRecover:    {Recover: parsedOps.Recover},
+		operation.TypeDeactivate: {Deactivate: parsedOps.Deactivate},
+	}
+
+	for opType, typeOps := range opsByType {
+		if typeOps.Size() == 0 {
+			continue
+		}
+
+		typeFiles, err := h.dryRun(typeOps, typeOps.Size())
+		if err != nil {
+			return nil, fmt.Errorf("estimate sizes for %s operations: %s", opType, err.Error())
+		}
+
+		estimate.Operations[opType] = typeFiles.Total()
+	}
+
+	return estimate, nil
+}
+
+// ValidatePreparedBatch reads batch files referenced by the given anchor string (as returned by PrepareTxnFiles)
+// from CAS and assembles operations from those files using operation provider. Error is returned if batch
+// files cannot be assembled or don't pass operation provider checks. It is intended to be called before
+// the anchor string is written to anchoring system. Note that batch files are decompressed using
+// protocol compression algorithm (see WithCompressionAlgorithm).
+func (h *OperationHandler) ValidatePreparedBatch(anchorString string) error {
+	dp, ok := h.cp.(decompressionProvider)
+	if !ok {
+		return errors.New("validate prepared batch: compression provider doesn't support decompression")
+	}
+
+	provider := NewOperationProvider(h.protocol, h.parser, h.cas, dp, WithAnchorCodec(h.anchorCodec))
+
+	_, err := provider.GetTxnOperations(&txn.SidetreeTxn{AnchorString: anchorString})
+	if err != nil {
+		return fmt.Errorf("validate prepared batch for anchor string[%s]: %s", anchorString, err.Error())
+	}
+
+	return nil
 }
 
 func (h *OperationHandler) parseOperations(ops []*operation.QueuedOperation) (*models.SortedOperations, []*operation.Reference, error) { // nolint:gocyclo,funlen
@@ -191,76 +418,294 @@ func (h *OperationHandler) parseOperations(ops []*operation.QueuedOperation) (*m
 		opRefs = append(opRefs, opRef)
 	}
 
+	sortOperations(result)
+
+	sort.Slice(opRefs, func(i, j int) bool {
+		return opRefs[i].UniqueSuffix < opRefs[j].UniqueSuffix
+	})
+
 	return result, opRefs, nil
 }
 
+// sortOperations sorts operations of each type by unique suffix so that batch files (and their CAS URIs)
+// don't depend on the order of queued operations.
+func sortOperations(ops *models.SortedOperations) {
+	for _, typeOps := range [][]*model.Operation{ops.Create, ops.Recover, ops.Deactivate, ops.Update} {
+		typeOps := typeOps
+
+		sort.Slice(typeOps, func(i, j int) bool {
+			return typeOps[i].UniqueSuffix < typeOps[j].UniqueSuffix
+		})
+	}
+}
+
 // createCoreIndexFile will create core index file from operations, proof files and provisional index file and write it to CAS
 // returns core index file address.
-func (h *OperationHandler) createCoreIndexFile(coreProofURI, mapURI string, ops *models.SortedOperations) (string, error) {
+func (h *OperationHandler) createCoreIndexFile(ctx context.Context, coreProofURI, mapURI string, ops *models.SortedOperations) (string, error) {
 	coreIndexFile := models.CreateCoreIndexFile(coreProofURI, mapURI, ops)
 
-	return h.writeModelToCAS(coreIndexFile, "core index")
+	return h.writeModelToCAS(ctx, coreIndexFile, h.protocol.CompressionAlgorithm, CoreIndexFileType, h.protocol.MaxCoreIndexFileSize)
 }
 
 // createCoreProofFile will create core proof file from recover and deactivate operations and write it to CAS
 // returns core proof file address.
-func (h *OperationHandler) createCoreProofFile(recoverOps, deactivateOps []*model.Operation) (string, error) {
+func (h *OperationHandler) createCoreProofFile(ctx context.Context, recoverOps, deactivateOps []*model.Operation) (string, error) {
 	if len(recoverOps)+len(deactivateOps) == 0 {
 		return "", nil
 	}
 
 	chunkFile := models.CreateCoreProofFile(recoverOps, deactivateOps)
 
-	return h.writeModelToCAS(chunkFile, "core proof")
+	return h.writeModelToCAS(ctx, chunkFile, h.protocol.GetProofFileCompressionAlgorithm(), CoreProofFileType, h.protocol.MaxProofFileSize)
 }
 
 // createProvisionalProofFile will create provisional proof file from update operations and write it to CAS
 // returns provisional proof file address.
-func (h *OperationHandler) createProvisionalProofFile(updateOps []*model.Operation) (string, error) {
+func (h *OperationHandler) createProvisionalProofFile(ctx context.Context, updateOps []*model.Operation) (string, error) {
 	if len(updateOps) == 0 {
 		return "", nil
 	}
 
 	chunkFile := models.CreateProvisionalProofFile(updateOps)
 
-	return h.writeModelToCAS(chunkFile, "provisional proof")
+	return h.writeModelToCAS(ctx, chunkFile, h.protocol.GetProofFileCompressionAlgorithm(), ProvisionalProofFileType, h.protocol.MaxProofFileSize)
 }
 
-// createChunkFile will create chunk file from operations and write it to CAS
-// returns chunk file address.
-func (h *OperationHandler) createChunkFile(ops *models.SortedOperations) (string, error) {
-	chunkFile := models.CreateChunkFile(ops)
+// createChunkFiles will create chunk files from operations and write them to CAS. Operation deltas are
+// split across multiple chunk files if a single chunk file would exceed maximum chunk file size.
+// returns chunk file addresses.
+func (h *OperationHandler) createChunkFiles(ctx context.Context, ops *models.SortedOperations) ([]string, error) {
+	chunkFiles, err := h.compressChunkFiles(models.CreateChunkFile(ops).Deltas)
+	if err != nil {
+		return nil, err
+	}
 
-	return h.writeModelToCAS(chunkFile, "chunk")
+	var uris []string
+
+	for _, chunkFile := range chunkFiles {
+		uri, err := h.writeToCAS(ctx, chunkFile, ChunkFileType)
+		if err != nil {
+			return nil, err
+		}
+
+		uris = append(uris, uri)
+	}
+
+	return uris, nil
+}
+
+// compressChunkFiles partitions deltas (in order) into compressed chunk files that don't exceed
+// maximum chunk file size.
+func (h *OperationHandler) compressChunkFiles(deltas []*model.DeltaModel) ([][]byte, error) {
+	bytes, size, err := h.compressModel(&models.ChunkFile{Deltas: deltas}, h.protocol.CompressionAlgorithm, "chunk")
+	if err != nil {
+		return nil, err
+	}
+
+	if h.withinFileSize(h.protocol.MaxChunkFileSize, len(bytes), size) {
+		return [][]byte{bytes}, nil
+	}
+
+	maxSize := h.protocol.MaxChunkFileSize
+
+	if len(deltas) == 1 {
+		return nil, fmt.Errorf("chunk file size %d for a single delta exceeded maximum chunk file size %d", len(bytes), maxSize)
+	}
+
+	mid := len(deltas) / 2
+
+	first, err := h.compressChunkFiles(deltas[:mid])
+	if err != nil {
+		return nil, err
+	}
+
+	second, err := h.compressChunkFiles(deltas[mid:])
+	if err != nil {
+		return nil, err
+	}
+
+	return append(first, second...), nil
+}
+
+// withinFileSize checks file size against limits that are enforced when file is read from CAS: compressed size
+// against maximum file size and size before compression against maximum decompressed size (zero means no limit).
+func (h *OperationHandler) withinFileSize(maxSize uint, compressedSize, size int) bool {
+	if maxSize == 0 {
+		return true
+	}
+
+	if compressedSize > int(maxSize) {
+		return false
+	}
+
+	maxDecompressedSize := maxSize * h.protocol.MaxMemoryDecompressionFactor
+
+	return maxDecompressedSize == 0 || size <= int(maxDecompressedSize)
 }
 
 // createProvisionalIndexFile will create provisional index file from operations, provisional proof URI
 // and chunk file URIs. The provisional index file is then written to CAS.
 // returns the address of the provisional index file in the CAS.
-func (h *OperationHandler) createProvisionalIndexFile(chunks []string, provisionalURI string, ops []*model.Operation) (string, error) {
+func (h *OperationHandler) createProvisionalIndexFile(ctx context.Context, chunks []string, provisionalURI string, ops []*model.Operation) (string, error) {
 	provisionalIndexFile := models.CreateProvisionalIndexFile(chunks, provisionalURI, ops)
 
-	return h.writeModelToCAS(provisionalIndexFile, "provisional index")
+	return h.writeModelToCAS(ctx, provisionalIndexFile, h.protocol.CompressionAlgorithm, ProvisionalIndexFileType, h.protocol.MaxProvisionalIndexFileSize)
+}
+
+func (h *OperationHandler) writeModelToCAS(ctx context.Context, model interface{}, alg, fileType string, maxSize uint) (string, error) {
+	alias := fileAlias(fileType)
+
+	compressedBytes, size, err := h.compressModel(model, alg, alias)
+	if err != nil {
+		return "", err
+	}
+
+	if !h.withinFileSize(maxSize, len(compressedBytes), size) {
+		return "", fmt.Errorf("%w: %s file size[%d] (size before compression[%d]) exceeds maximum file size[%d]",
+			ErrMaxFileSizeExceeded, alias, len(compressedBytes), size, maxSize)
+	}
+
+	return h.writeToCAS(ctx, compressedBytes, fileType)
 }
 
-func (h *OperationHandler) writeModelToCAS(model interface{}, alias string) (string, error) {
+// compressModel returns compressed model bytes and size of model bytes before compression.
+func (h *OperationHandler) compressModel(model interface{}, alg, alias string) ([]byte, int, error) {
 	bytes, err := docutil.MarshalCanonical(model)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal %s file: %s", alias, err.Error())
+		return nil, 0, fmt.Errorf("failed to marshal %s file: %s", alias, err.Error())
 	}
 
 	logger.Debugf("%s file: %s", alias, string(bytes))
 
-	compressedBytes, err := h.cp.Compress(h.protocol.CompressionAlgorithm, bytes)
+	if alg == protocol.NoCompression {
+		return bytes, len(bytes), nil
+	}
+
+	compressedBytes, err := h.cp.Compress(alg, bytes)
 	if err != nil {
-		return "", err
+		return nil, 0, err
+	}
+
+	return compressedBytes, len(bytes), nil
+}
+
+func (h *OperationHandler) writeToCAS(ctx context.Context, compressedBytes []byte, fileType string) (string, error) {
+	alias := fileAlias(fileType)
+
+	if w, ok := h.cas.(fileTypeWriter); ok {
+		address, err := w.WriteFile(fileType, compressedBytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to store %s file: %s", alias, err.Error())
+		}
+
+		return address, nil
+	}
+
+	if h.skipExisting {
+		if address, ok := h.existsInCAS(compressedBytes); ok {
+			logger.Debugf("%s file already exists in CAS at address[%s]; skipping write", alias, address)
+
+			return address, nil
+		}
 	}
 
 	// make file available in CAS
-	address, err := h.cas.Write(compressedBytes)
+	address, err := cas.NewContextClient(h.cas).WriteContext(ctx, compressedBytes)
 	if err != nil {
 		return "", fmt.Errorf("failed to store %s file: %s", alias, err.Error())
 	}
 
 	return address, nil
 }
+
+// existsInCAS returns content address and true if CAS client supports size probes and CAS already holds
+// the given content. Content is written as usual if address cannot be calculated or probe fails.
+func (h *OperationHandler) existsInCAS(content []byte) (string, bool) {
+	sizeClient, ok := h.cas.(cas.SizeClient)
+	if !ok {
+		return "", false
+	}
+
+	address, err := h.contentAddress(content)
+	if err != nil {
+		logger.Debugf("failed to calculate content address: %s", err.Error())
+
+		return "", false
+	}
+
+	size, err := sizeClient.Size(address)
+	if err != nil || size != len(content) {
+		return "", false
+	}
+
+	return address, true
+}
+
+// contentAddress returns CAS address of the given content. Address is calculated by CAS client if it implements
+// cas.AddressClient; otherwise it is base64url encoded multihash of the content using the first of protocol
+// multihash algorithms.
+func (h *OperationHandler) contentAddress(content []byte) (string, error) {
+	if addressClient, ok := h.cas.(cas.AddressClient); ok {
+		return addressClient.Address(content)
+	}
+
+	if len(h.protocol.MultihashAlgorithms) == 0 {
+		return "", errors.New("protocol doesn't define multihash algorithms")
+	}
+
+	hash, err := hashing.ComputeMultihash(h.protocol.MultihashAlgorithms[0], content)
+	if err != nil {
+		return "", err
+	}
+
+	return encoder.EncodeToString(hash), nil
+}
+
+// fileAlias returns batch file type in human readable form (e.g. "core index" for core index file type).
+func fileAlias(fileType string) string {
+	return strings.ReplaceAll(fileType, "_", " ")
+}
+
+// fileTypeWriter is implemented by CAS clients that need to know the batch file type of written content.
+type fileTypeWriter interface {
+	WriteFile(fileType string, content []byte) (string, error)
+}
+
+// dryRunCASClient calculates content addresses and records content sizes by batch file type without
+// storing content.
+type dryRunCASClient struct {
+	address func(content []byte) (string, error)
+	sizes   *TxnFileSizes
+}
+
+func (c *dryRunCASClient) WriteFile(fileType string, content []byte) (string, error) {
+	address, err := c.address(content)
+	if err != nil {
+		return "", err
+	}
+
+	switch fileType {
+	case CoreIndexFileType:
+		c.sizes.CoreIndex = len(content)
+	case CoreProofFileType:
+		c.sizes.CoreProof = len(content)
+	case ProvisionalIndexFileType:
+		c.sizes.ProvisionalIndex = len(content)
+	case ProvisionalProofFileType:
+		c.sizes.ProvisionalProof = len(content)
+	case ChunkFileType:
+		c.sizes.Chunks = append(c.sizes.Chunks, len(content))
+	default:
+		return "", fmt.Errorf("unsupported file type[%s] in dry run mode", fileType)
+	}
+
+	return address, nil
+}
+
+func (c *dryRunCASClient) Write(content []byte) (string, error) {
+	return "", errors.New("write without file type is not supported in dry run mode")
+}
+
+func (c *dryRunCASClient) Read(address string) ([]byte, error) {
+	return nil, fmt.Errorf("read is not supported in dry run mode: %s", address)
+}
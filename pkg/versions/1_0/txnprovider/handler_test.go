@@ -7,20 +7,27 @@ SPDX-License-Identifier: Apache-2.0
 package txnprovider
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
 	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	internaljws "github.com/trustbloc/sidetree-core-go/pkg/internal/jws"
 	"github.com/trustbloc/sidetree-core-go/pkg/jws"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
@@ -36,15 +43,17 @@ import (
 //go:generate counterfeiter -o operationparser.gen.go --fake-name MockOperationParser . OperationParser
 
 const (
-	sha2_256  = 18
 	defaultNS = "did:sidetree"
 
+	sha2_256 = 18
+	sha2_512 = 19
+
 	createAnchorOrigin  = "create-anchor-origin"
 	recoverAnchorOrigin = "recover-anchor-origin"
 )
 
 func TestNewOperationHandler(t *testing.T) {
-	protocol := mocks.NewMockProtocolClient().Protocol
+	protocol := newMockProtocolClient().Protocol
 
 	handler := NewOperationHandler(
 		protocol,
@@ -62,7 +71,7 @@ func TestOperationHandler_PrepareTxnFiles(t *testing.T) {
 
 	compression := compression.New(compression.WithDefaultAlgorithms())
 
-	protocol := mocks.NewMockProtocolClient().Protocol
+	protocol := newMockProtocolClient().Protocol
 
 	t.Run("success", func(t *testing.T) {
 		ops := getTestOperations(createOpsNum, updateOpsNum, deactivateOpsNum, recoverOpsNum)
@@ -263,6 +272,153 @@ func TestOperationHandler_PrepareTxnFiles(t *testing.T) {
 		require.Equal(t, createOpsNum+zeroRecoverOps+zeroUpdateOps, len(cf.Deltas))
 	})
 
+	t.Run("success - artifacts contain all written CAS URIs", func(t *testing.T) {
+		ops := getTestOperations(createOpsNum, updateOpsNum, deactivateOpsNum, recoverOpsNum)
+
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+		handler := NewOperationHandler(protocol, cas, compression, operationparser.New(protocol))
+
+		anchorString, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		anchorData, err := ParseAnchorData(anchorString)
+		require.NoError(t, err)
+
+		var uris []string
+		for _, artifact := range artifacts {
+			uris = append(uris, artifact.ID)
+		}
+
+		require.Len(t, uris, 5)
+		require.ElementsMatch(t, cas.writtenURIs(), uris)
+		require.Contains(t, uris, anchorData.CoreIndexFileURI)
+	})
+
+	t.Run("success - proof files are not written for create only batch", func(t *testing.T) {
+		ops := getTestOperations(createOpsNum, 0, 0, 0)
+
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+		handler := NewOperationHandler(protocol, cas, compression, operationparser.New(protocol))
+
+		anchorString, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		// chunk, provisional index and core index file
+		require.Len(t, cas.writtenURIs(), 3)
+		require.Len(t, artifacts, 3)
+
+		for i, artifact := range artifacts {
+			require.Equal(t, cas.writtenURIs()[i], artifact.ID)
+			require.NotContains(t, artifact.Desc, "proof")
+		}
+
+		provider := NewOperationProvider(protocol, operationparser.New(protocol), cas, compression)
+
+		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      anchorString,
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		})
+		require.NoError(t, err)
+		require.Len(t, txnOps, createOpsNum)
+	})
+
+	t.Run("success - deltas are split across multiple chunk files", func(t *testing.T) {
+		const createOpsNum = 10
+
+		ops := getTestOperations(createOpsNum, updateOpsNum, deactivateOpsNum, recoverOpsNum)
+
+		cas := mocks.NewMockCasClient(nil)
+
+		// determine chunk file size for the batch
+		handler := NewOperationHandler(protocol, cas, compression, operationparser.New(protocol))
+
+		parsedOps, _, err := handler.parseOperations(ops)
+		require.NoError(t, err)
+
+		chunkFiles, err := handler.compressChunkFiles(models.CreateChunkFile(parsedOps).Deltas)
+		require.NoError(t, err)
+		require.Len(t, chunkFiles, 1)
+
+		p := protocol
+		p.MaxChunkFileSize = uint(len(chunkFiles[0]) - 1)
+
+		handler = NewOperationHandler(p, cas, compression, operationparser.New(p))
+
+		anchorString, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		anchorData, err := ParseAnchorData(anchorString)
+		require.NoError(t, err)
+
+		bytes, err := cas.Read(anchorData.CoreIndexFileURI)
+		require.NoError(t, err)
+
+		cifContent, err := compression.Decompress(compressionAlgorithm, bytes)
+		require.NoError(t, err)
+
+		cif, err := models.ParseCoreIndexFile(cifContent)
+		require.NoError(t, err)
+
+		bytes, err = cas.Read(cif.ProvisionalIndexFileURI)
+		require.NoError(t, err)
+
+		pifContent, err := compression.Decompress(compressionAlgorithm, bytes)
+		require.NoError(t, err)
+
+		pif, err := models.ParseProvisionalIndexFile(pifContent)
+		require.NoError(t, err)
+		require.True(t, len(pif.Chunks) > 1)
+		require.Len(t, artifacts, 4+len(pif.Chunks))
+
+		deltaNum := 0
+
+		for _, chunk := range pif.Chunks {
+			bytes, err := cas.Read(chunk.ChunkFileURI)
+			require.NoError(t, err)
+			require.True(t, len(bytes) <= int(p.MaxChunkFileSize))
+
+			content, err := compression.Decompress(compressionAlgorithm, bytes)
+			require.NoError(t, err)
+
+			cf, err := models.ParseChunkFile(content)
+			require.NoError(t, err)
+
+			deltaNum += len(cf.Deltas)
+		}
+
+		require.Equal(t, createOpsNum+recoverOpsNum+updateOpsNum, deltaNum)
+
+		// round-trip through operation provider
+		provider := NewOperationProvider(p, operationparser.New(p), cas, compression)
+
+		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      anchorString,
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		})
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("error - single delta exceeds maximum chunk file size", func(t *testing.T) {
+		p := protocol
+		p.MaxChunkFileSize = 10
+
+		handler := NewOperationHandler(p, mocks.NewMockCasClient(nil), compression, operationparser.New(p))
+
+		anchorString, artifacts, refs, err := handler.PrepareTxnFiles(getTestOperations(2, 0, 0, 0))
+		require.Error(t, err)
+		require.Empty(t, anchorString)
+		require.Nil(t, refs)
+		require.Nil(t, artifacts)
+		require.Contains(t, err.Error(), "for a single delta exceeded maximum chunk file size 10")
+	})
+
 	t.Run("error - no operations provided", func(t *testing.T) {
 		handler := NewOperationHandler(
 			protocol,
@@ -334,8 +490,764 @@ func TestOperationHandler_PrepareTxnFiles(t *testing.T) {
 	})
 }
 
+func TestOperationHandler_PrepareTxnFilesDryRun(t *testing.T) {
+	compression := compression.New(compression.WithDefaultAlgorithms())
+
+	protocol := newMockProtocolClient().Protocol
+
+	sizeOf := func(cas *recordingCasClient, uri string) int {
+		bytes, err := cas.Read(uri)
+		require.NoError(t, err)
+
+		return len(bytes)
+	}
+
+	t.Run("success - dry run sizes match written sizes", func(t *testing.T) {
+		ops := getTestOperations(2, 1, 1, 1)
+
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+		handler := NewOperationHandler(protocol, cas, compression, operationparser.New(protocol))
+
+		sizes, err := handler.PrepareTxnFilesDryRun(ops)
+		require.NoError(t, err)
+		require.Empty(t, cas.writtenURIs())
+
+		_, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Len(t, artifacts, 5)
+
+		expected := &TxnFileSizes{}
+
+		for _, artifact := range artifacts {
+			size := sizeOf(cas, artifact.ID)
+
+			switch artifact.Desc {
+			case "core index file":
+				expected.CoreIndex = size
+			case "core proof file":
+				expected.CoreProof = size
+			case "provisional index file":
+				expected.ProvisionalIndex = size
+			case "provisional proof file":
+				expected.ProvisionalProof = size
+			case "chunk file":
+				expected.Chunks = append(expected.Chunks, size)
+			}
+		}
+
+		require.Equal(t, expected, sizes)
+		require.NotZero(t, sizes.CoreIndex)
+		require.NotZero(t, sizes.CoreProof)
+		require.NotZero(t, sizes.ProvisionalIndex)
+		require.NotZero(t, sizes.ProvisionalProof)
+		require.Len(t, sizes.Chunks, 1)
+	})
+
+	t.Run("success - create only batch", func(t *testing.T) {
+		handler := NewOperationHandler(protocol, mocks.NewMockCasClient(nil), compression, operationparser.New(protocol))
+
+		sizes, err := handler.PrepareTxnFilesDryRun(getTestOperations(2, 0, 0, 0))
+		require.NoError(t, err)
+		require.NotZero(t, sizes.CoreIndex)
+		require.NotZero(t, sizes.ProvisionalIndex)
+		require.Zero(t, sizes.CoreProof)
+		require.Zero(t, sizes.ProvisionalProof)
+		require.Len(t, sizes.Chunks, 1)
+	})
+
+	t.Run("success - sizes are recorded by file type", func(t *testing.T) {
+		client := &dryRunCASClient{address: func(content []byte) (string, error) { return string(content), nil }, sizes: &TxnFileSizes{}}
+
+		for fileType, content := range map[string]string{
+			CoreIndexFileType:        "a",
+			CoreProofFileType:        "bb",
+			ProvisionalIndexFileType: "ccc",
+			ProvisionalProofFileType: "dddd",
+		} {
+			address, err := client.WriteFile(fileType, []byte(content))
+			require.NoError(t, err)
+			require.Equal(t, content, address)
+		}
+
+		for _, content := range []string{"eeeee", "ffffff"} {
+			_, err := client.WriteFile(ChunkFileType, []byte(content))
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, &TxnFileSizes{
+			CoreIndex:        1,
+			CoreProof:        2,
+			ProvisionalIndex: 3,
+			ProvisionalProof: 4,
+			Chunks:           []int{5, 6},
+		}, client.sizes)
+	})
+
+	t.Run("error - unsupported file type", func(t *testing.T) {
+		client := &dryRunCASClient{address: func(content []byte) (string, error) { return "address", nil }, sizes: &TxnFileSizes{}}
+
+		address, err := client.WriteFile(ManifestFileType, []byte("content"))
+		require.Error(t, err)
+		require.Empty(t, address)
+		require.Contains(t, err.Error(), "unsupported file type[manifest] in dry run mode")
+
+		address, err = client.Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, address)
+		require.Contains(t, err.Error(), "write without file type is not supported in dry run mode")
+	})
+
+	t.Run("error - no operations provided", func(t *testing.T) {
+		handler := NewOperationHandler(protocol, mocks.NewMockCasClient(nil), compression, operationparser.New(protocol))
+
+		sizes, err := handler.PrepareTxnFilesDryRun(nil)
+		require.Error(t, err)
+		require.Nil(t, sizes)
+		require.Contains(t, err.Error(), "prepare txn operations called without operations")
+	})
+}
+
+func TestOperationHandler_DeterministicOrdering(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := newMockProtocolClient().Protocol
+
+	ops := getTestOperations(5, 3, 2, 2)
+
+	reversed := make([]*operation.QueuedOperation, len(ops))
+	for i, op := range ops {
+		reversed[len(ops)-1-i] = op
+	}
+
+	// interleave operations of different types
+	interleaved := make([]*operation.QueuedOperation, 0, len(ops))
+	for i := 0; i < len(ops); i += 2 {
+		interleaved = append(interleaved, ops[i])
+	}
+
+	for i := 1; i < len(ops); i += 2 {
+		interleaved = append(interleaved, ops[i])
+	}
+
+	cas := mocks.NewMockCasClient(nil)
+	handler := NewOperationHandler(p, cas, cp, operationparser.New(p))
+
+	anchorString, artifacts, refs, err := handler.PrepareTxnFiles(ops)
+	require.NoError(t, err)
+
+	for i := 1; i < len(refs); i++ {
+		require.True(t, refs[i-1].UniqueSuffix < refs[i].UniqueSuffix)
+	}
+
+	for _, shuffled := range [][]*operation.QueuedOperation{reversed, interleaved} {
+		shuffledAnchorString, shuffledArtifacts, shuffledRefs, err := handler.PrepareTxnFiles(shuffled)
+		require.NoError(t, err)
+		require.Equal(t, anchorString, shuffledAnchorString)
+		require.Equal(t, artifacts, shuffledArtifacts)
+		require.Equal(t, refs, shuffledRefs)
+	}
+
+	ad, err := ParseAnchorData(anchorString)
+	require.NoError(t, err)
+
+	var cif models.CoreIndexFile
+	readCompressedModel(t, cp, cas, ad.CoreIndexFileURI, &cif)
+
+	for _, typeRefs := range [][]models.OperationReference{cif.Operations.Recover, cif.Operations.Deactivate} {
+		for i := 1; i < len(typeRefs); i++ {
+			require.True(t, typeRefs[i-1].DidSuffix < typeRefs[i].DidSuffix)
+		}
+	}
+
+	sizes, err := handler.PrepareTxnFilesDryRun(reversed)
+	require.NoError(t, err)
+
+	expectedSizes, err := handler.PrepareTxnFilesDryRun(ops)
+	require.NoError(t, err)
+	require.Equal(t, expectedSizes, sizes)
+}
+
+func TestOperationHandler_EstimateSizes(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := newMockProtocolClient().Protocol
+
+	// actualSize prepares batch files and returns total size of files written to CAS
+	actualSize := func(ops []*operation.QueuedOperation) (int, map[string]int) {
+		cas := mocks.NewMockCasClient(nil)
+
+		_, artifacts, _, err := NewOperationHandler(p, cas, cp, operationparser.New(p)).PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		total := 0
+		sizes := make(map[string]int)
+
+		for _, artifact := range artifacts {
+			bytes, err := cas.Read(artifact.ID)
+			require.NoError(t, err)
+
+			total += len(bytes)
+			sizes[artifact.Desc] += len(bytes)
+		}
+
+		return total, sizes
+	}
+
+	t.Run("success - mixed batch", func(t *testing.T) {
+		creates := generateOperations(2, operation.TypeCreate)
+		updates := generateOperations(1, operation.TypeUpdate)
+		recovers := generateOperations(1, operation.TypeRecover)
+		deactivates := generateOperations(1, operation.TypeDeactivate)
+
+		var ops []*operation.QueuedOperation
+		ops = append(ops, creates...)
+		ops = append(ops, updates...)
+		ops = append(ops, recovers...)
+		ops = append(ops, deactivates...)
+
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+		estimate, err := NewOperationHandler(p, cas, cp, operationparser.New(p)).EstimateSizes(ops)
+		require.NoError(t, err)
+		require.Empty(t, cas.writtenURIs())
+
+		total, sizes := actualSize(ops)
+		require.Equal(t, total, estimate.Total)
+		require.Equal(t, sizes["core index file"], estimate.Files.CoreIndex)
+		require.Equal(t, sizes["core proof file"], estimate.Files.CoreProof)
+		require.Equal(t, sizes["provisional index file"], estimate.Files.ProvisionalIndex)
+		require.Equal(t, sizes["provisional proof file"], estimate.Files.ProvisionalProof)
+		require.Len(t, estimate.Files.Chunks, 1)
+		require.Equal(t, sizes["chunk file"], estimate.Files.Chunks[0])
+
+		require.Len(t, estimate.Operations, 4)
+
+		for opType, typeOps := range map[operation.Type][]*operation.QueuedOperation{
+			operation.TypeCreate:     creates,
+			operation.TypeUpdate:     updates,
+			operation.TypeRecover:    recovers,
+			operation.TypeDeactivate: deactivates,
+		} {
+			typeTotal, _ := actualSize(typeOps)
+			require.Equal(t, typeTotal, estimate.Operations[opType], opType)
+			require.Less(t, estimate.Operations[opType], estimate.Total)
+		}
+	})
+
+	t.Run("success - single operation type", func(t *testing.T) {
+		ops := generateOperations(3, operation.TypeCreate)
+
+		estimate, err := NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)).EstimateSizes(ops)
+		require.NoError(t, err)
+
+		total, _ := actualSize(ops)
+		require.Equal(t, total, estimate.Total)
+		require.Equal(t, map[operation.Type]int{operation.TypeCreate: total}, estimate.Operations)
+		require.Zero(t, estimate.Files.CoreProof)
+		require.Zero(t, estimate.Files.ProvisionalProof)
+	})
+
+	t.Run("success - operations are parsed once", func(t *testing.T) {
+		ops := getTestOperations(2, 1, 1, 1)
+
+		parser := &countingParser{OperationParser: operationparser.New(p)}
+
+		estimate, err := NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, parser).EstimateSizes(ops)
+		require.NoError(t, err)
+		require.Len(t, estimate.Operations, 4)
+		require.Equal(t, len(ops), parser.calls)
+	})
+
+	t.Run("error - no operations provided", func(t *testing.T) {
+		estimate, err := NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p)).EstimateSizes(nil)
+		require.Error(t, err)
+		require.Nil(t, estimate)
+		require.Contains(t, err.Error(), "prepare txn operations called without operations")
+	})
+}
+
+func TestOperationHandler_PrepareTxnFilesContext(t *testing.T) {
+	protocol := newMockProtocolClient().Protocol
+
+	ops := getTestOperations(2, 1, 1, 1)
+
+	t.Run("success - context-aware CAS", func(t *testing.T) {
+		contextCAS := &contextCasClient{Client: mocks.NewMockCasClient(nil)}
+
+		handler := NewOperationHandler(protocol, contextCAS,
+			compression.New(compression.WithDefaultAlgorithms()), operationparser.New(protocol))
+
+		anchorString, artifacts, refs, err := handler.PrepareTxnFilesContext(context.Background(), ops)
+		require.NoError(t, err)
+		require.NotEmpty(t, anchorString)
+		require.Len(t, artifacts, 5)
+		require.Len(t, refs, len(ops))
+		require.Equal(t, 5, contextCAS.callCount())
+	})
+
+	t.Run("error - context is cancelled", func(t *testing.T) {
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+		handler := NewOperationHandler(protocol, cas,
+			compression.New(compression.WithDefaultAlgorithms()), operationparser.New(protocol))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		anchorString, artifacts, refs, err := handler.PrepareTxnFilesContext(ctx, ops)
+		require.Error(t, err)
+		require.Empty(t, anchorString)
+		require.Nil(t, artifacts)
+		require.Nil(t, refs)
+		require.Contains(t, err.Error(), "failed to store chunk file: context canceled")
+		require.Empty(t, cas.writtenURIs())
+	})
+
+	t.Run("error - context-aware CAS write is cancelled", func(t *testing.T) {
+		contextCAS := &contextCasClient{Client: mocks.NewMockCasClient(nil), blocking: true}
+
+		handler := NewOperationHandler(protocol, contextCAS,
+			compression.New(compression.WithDefaultAlgorithms()), operationparser.New(protocol))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		anchorString, _, _, err := handler.PrepareTxnFilesContext(ctx, ops)
+		require.Error(t, err)
+		require.Empty(t, anchorString)
+		require.Contains(t, err.Error(), "failed to store chunk file: context deadline exceeded")
+		require.Equal(t, 1, contextCAS.callCount())
+	})
+
+	t.Run("error - blocking CAS write is abandoned", func(t *testing.T) {
+		delayedCAS := &delayedCasClient{Client: mocks.NewMockCasClient(nil), delay: time.Second}
+
+		handler := NewOperationHandler(protocol, &delayedWriteCasClient{delayedCasClient: delayedCAS},
+			compression.New(compression.WithDefaultAlgorithms()), operationparser.New(protocol))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		anchorString, _, _, err := handler.PrepareTxnFilesContext(ctx, ops)
+		require.Error(t, err)
+		require.Empty(t, anchorString)
+		require.Contains(t, err.Error(), "context deadline exceeded")
+		require.True(t, time.Since(start) < time.Second)
+	})
+}
+
+func TestOperationHandler_WithCompressionAlgorithm(t *testing.T) {
+	protocol := newMockProtocolClient().Protocol
+	require.Equal(t, "GZIP", protocol.CompressionAlgorithm)
+
+	compression := compression.New(compression.WithDefaultAlgorithms())
+
+	ops := getTestOperations(2, 1, 1, 1)
+
+	getTxnOperations := func(alg string, cas *mocks.MockCasClient, anchorString string) ([]*operation.AnchoredOperation, error) {
+		p := protocol
+		p.CompressionAlgorithm = alg
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, compression)
+
+		return provider.GetTxnOperations(&txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      anchorString,
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		})
+	}
+
+	t.Run("success - batch files are written using algorithm override", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		handler := NewOperationHandler(protocol, cas, compression, operationparser.New(protocol))
+
+		anchorString, artifacts, refs, err := handler.PrepareTxnFilesContext(context.Background(), ops,
+			WithCompressionAlgorithm("ZSTD"))
+		require.NoError(t, err)
+		require.Len(t, artifacts, 5)
+		require.Len(t, refs, len(ops))
+
+		// batch files are readable by provider configured with the same algorithm
+		txnOps, err := getTxnOperations("ZSTD", cas, anchorString)
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+
+		// batch files are not readable by provider configured with protocol algorithm
+		txnOps, err = getTxnOperations(protocol.CompressionAlgorithm, cas, anchorString)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "using 'GZIP'")
+	})
+
+	t.Run("success - override with protocol algorithm", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		handler := NewOperationHandler(protocol, cas, compression, operationparser.New(protocol))
+
+		anchorString, _, _, err := handler.PrepareTxnFilesContext(context.Background(), ops,
+			WithCompressionAlgorithm(protocol.CompressionAlgorithm))
+		require.NoError(t, err)
+
+		txnOps, err := getTxnOperations(protocol.CompressionAlgorithm, cas, anchorString)
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("error - algorithm is not registered", func(t *testing.T) {
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+		handler := NewOperationHandler(protocol, cas, compression, operationparser.New(protocol))
+
+		anchorString, artifacts, refs, err := handler.PrepareTxnFilesContext(context.Background(), ops,
+			WithCompressionAlgorithm("LZ4"))
+		require.Error(t, err)
+		require.Empty(t, anchorString)
+		require.Nil(t, artifacts)
+		require.Nil(t, refs)
+		require.Contains(t, err.Error(), "compression algorithm[LZ4] is not supported")
+		require.Empty(t, cas.writtenURIs())
+	})
+}
+
+func TestOperationHandler_ProofFileCompression(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	ops := getTestOperations(2, 1, 1, 1)
+
+	// prepareProofFiles prepares batch files and returns anchor string together with raw (as stored in CAS)
+	// core proof and provisional proof files.
+	prepareProofFiles := func(t *testing.T, p protocol.Protocol, cas *mocks.MockCasClient) (string, []byte, []byte) {
+		t.Helper()
+
+		handler := NewOperationHandler(p, cas, cp, operationparser.New(p))
+
+		anchorString, _, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		ad, err := ParseAnchorData(anchorString)
+		require.NoError(t, err)
+
+		cif := &models.CoreIndexFile{}
+		readCompressedModel(t, cp, cas, ad.CoreIndexFileURI, cif)
+
+		pif := &models.ProvisionalIndexFile{}
+		readCompressedModel(t, cp, cas, cif.ProvisionalIndexFileURI, pif)
+
+		coreProof, err := cas.Read(cif.CoreProofFileURI)
+		require.NoError(t, err)
+
+		provisionalProof, err := cas.Read(pif.ProvisionalProofFileURI)
+		require.NoError(t, err)
+
+		return anchorString, coreProof, provisionalProof
+	}
+
+	getTxnOperations := func(p protocol.Protocol, cas *mocks.MockCasClient, anchorString string) ([]*operation.AnchoredOperation, error) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		return provider.GetTxnOperations(&txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      anchorString,
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		})
+	}
+
+	compressed := newMockProtocolClient().Protocol
+	require.Empty(t, compressed.ProofFileCompressionAlgorithm)
+
+	uncompressed := compressed
+	uncompressed.ProofFileCompressionAlgorithm = protocol.NoCompression
+
+	compressedCAS := mocks.NewMockCasClient(nil)
+	compressedAnchorString, compressedCoreProof, compressedProvisionalProof := prepareProofFiles(t, compressed, compressedCAS)
+
+	uncompressedCAS := mocks.NewMockCasClient(nil)
+	uncompressedAnchorString, uncompressedCoreProof, uncompressedProvisionalProof := prepareProofFiles(t, uncompressed, uncompressedCAS)
+
+	t.Run("success - compressed vs uncompressed proof file sizes", func(t *testing.T) {
+		// uncompressed proof files are stored as canonical JSON
+		_, err := models.ParseCoreProofFile(uncompressedCoreProof)
+		require.NoError(t, err)
+
+		_, err = models.ParseProvisionalProofFile(uncompressedProvisionalProof)
+		require.NoError(t, err)
+
+		decompressed, err := cp.Decompress(compressed.CompressionAlgorithm, compressedCoreProof)
+		require.NoError(t, err)
+		require.Equal(t, uncompressedCoreProof, decompressed)
+		require.NotEqual(t, len(compressedCoreProof), len(uncompressedCoreProof))
+
+		decompressed, err = cp.Decompress(compressed.CompressionAlgorithm, compressedProvisionalProof)
+		require.NoError(t, err)
+		require.Equal(t, uncompressedProvisionalProof, decompressed)
+		require.NotEqual(t, len(compressedProvisionalProof), len(uncompressedProvisionalProof))
+
+		t.Logf("core proof file size: compressed[%d] uncompressed[%d]", len(compressedCoreProof), len(uncompressedCoreProof))
+		t.Logf("provisional proof file size: compressed[%d] uncompressed[%d]",
+			len(compressedProvisionalProof), len(uncompressedProvisionalProof))
+	})
+
+	t.Run("success - round trip with compressed proof files", func(t *testing.T) {
+		txnOps, err := getTxnOperations(compressed, compressedCAS, compressedAnchorString)
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("success - round trip with uncompressed proof files", func(t *testing.T) {
+		txnOps, err := getTxnOperations(uncompressed, uncompressedCAS, uncompressedAnchorString)
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("success - round trip with different proof file compression algorithm", func(t *testing.T) {
+		zstd := compressed
+		zstd.ProofFileCompressionAlgorithm = "ZSTD"
+
+		cas := mocks.NewMockCasClient(nil)
+		anchorString, coreProof, _ := prepareProofFiles(t, zstd, cas)
+
+		_, err := cp.Decompress("ZSTD", coreProof)
+		require.NoError(t, err)
+
+		txnOps, err := getTxnOperations(zstd, cas, anchorString)
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("error - reader protocol doesn't match writer protocol", func(t *testing.T) {
+		txnOps, err := getTxnOperations(compressed, uncompressedCAS, uncompressedAnchorString)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "using 'GZIP'")
+	})
+}
+
+func TestOperationHandler_ValidatePreparedBatch(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := newMockProtocolClient().Protocol
+
+	ops := getTestOperations(2, 1, 1, 1)
+
+	t.Run("success - prepared batch is valid", func(t *testing.T) {
+		handler := NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p))
+
+		anchorString, _, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		require.NoError(t, handler.ValidatePreparedBatch(anchorString))
+	})
+
+	t.Run("error - corrupted chunk file", func(t *testing.T) {
+		casClient := &corruptingCasClient{MockCasClient: mocks.NewMockCasClient(nil), content: make(map[string][]byte)}
+
+		handler := NewOperationHandler(p, casClient, cp, operationparser.New(p))
+
+		anchorString, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		corrupted, err := cp.Compress(compressionAlgorithm, []byte(`{"deltas":[]}`))
+		require.NoError(t, err)
+
+		for _, artifact := range artifacts {
+			if artifact.Desc == "chunk file" {
+				casClient.content[artifact.ID] = corrupted
+			}
+		}
+
+		err = handler.ValidatePreparedBatch(anchorString)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), fmt.Sprintf("validate prepared batch for anchor string[%s]", anchorString))
+		require.Contains(t, err.Error(), "number of create+recover+update operations[4] doesn't match number of deltas[0]")
+	})
+
+	t.Run("error - batch files not found", func(t *testing.T) {
+		handler := NewOperationHandler(p, mocks.NewMockCasClient(nil), cp, operationparser.New(p))
+
+		err := handler.ValidatePreparedBatch("5.coreIndexURI")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "validate prepared batch for anchor string[5.coreIndexURI]")
+	})
+
+	t.Run("error - compression provider doesn't support decompression", func(t *testing.T) {
+		handler := NewOperationHandler(p, mocks.NewMockCasClient(nil), &compressOnlyProvider{cp: cp}, operationparser.New(p))
+
+		anchorString, _, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		err = handler.ValidatePreparedBatch(anchorString)
+		require.EqualError(t, err, "validate prepared batch: compression provider doesn't support decompression")
+	})
+}
+
+func TestOperationHandler_SkipExistingCASContent(t *testing.T) {
+	protocol := newMockProtocolClient().Protocol
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	ops := getTestOperations(2, 2, 2, 2)
+
+	newProbingCasClient := func() *probingCasClient {
+		return &probingCasClient{recordingCasClient: recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}}
+	}
+
+	t.Run("success - identical batch files are not written again", func(t *testing.T) {
+		cas := newProbingCasClient()
+
+		handler := NewOperationHandler(protocol, cas, cp, operationparser.New(protocol), WithSkipExistingContent())
+
+		anchorString, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Len(t, cas.writtenURIs(), len(artifacts))
+		require.Len(t, cas.probedURIs(), len(artifacts))
+
+		cas.resetWritten()
+
+		secondAnchorString, secondArtifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Equal(t, anchorString, secondAnchorString)
+		require.Equal(t, artifacts, secondArtifacts)
+		require.Empty(t, cas.writtenURIs())
+		require.Len(t, cas.probedURIs(), 2*len(artifacts))
+
+		anchoredOps, err := NewOperationProvider(protocol, operationparser.New(protocol), cas, cp).
+			GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: secondAnchorString})
+		require.NoError(t, err)
+		require.Len(t, anchoredOps, len(ops))
+	})
+
+	t.Run("success - only new content is written", func(t *testing.T) {
+		cas := newProbingCasClient()
+
+		handler := NewOperationHandler(protocol, cas, cp, operationparser.New(protocol), WithSkipExistingContent())
+
+		_, _, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		cas.resetWritten()
+
+		// same recover, deactivate and update operations (identical proof files) with different create operations
+		_, artifacts, _, err := handler.PrepareTxnFiles(append(getTestOperations(3, 0, 0, 0), ops[2:]...))
+		require.NoError(t, err)
+		require.Len(t, artifacts, 5)
+
+		written := cas.writtenURIs()
+		require.Len(t, written, 3)
+
+		for _, artifact := range artifacts {
+			if strings.Contains(artifact.Desc, "proof") {
+				require.NotContains(t, written, artifact.ID)
+			} else {
+				require.Contains(t, written, artifact.ID)
+			}
+		}
+	})
+
+	t.Run("success - content is not probed by default", func(t *testing.T) {
+		cas := newProbingCasClient()
+
+		handler := NewOperationHandler(protocol, cas, cp, operationparser.New(protocol))
+
+		_, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		_, _, _, err = handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Len(t, cas.writtenURIs(), 2*len(artifacts))
+		require.Empty(t, cas.probedURIs())
+	})
+
+	t.Run("success - address is calculated with protocol multihash algorithm", func(t *testing.T) {
+		// mock CAS stores content at sha2-256 addresses
+		sha512Protocol := newMockProtocolClient().Protocol
+		sha512Protocol.MultihashAlgorithms = []uint{sha2_512}
+
+		cas := newProbingCasClient()
+
+		handler := NewOperationHandler(sha512Protocol, cas, cp, operationparser.New(protocol), WithSkipExistingContent())
+
+		_, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		_, _, _, err = handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Len(t, cas.writtenURIs(), 2*len(artifacts))
+
+		for _, uri := range cas.probedURIs() {
+			require.NotContains(t, cas.writtenURIs(), uri)
+		}
+	})
+
+	t.Run("success - address is calculated by CAS client", func(t *testing.T) {
+		sha512Protocol := newMockProtocolClient().Protocol
+		sha512Protocol.MultihashAlgorithms = []uint{sha2_512}
+
+		cas := &addressingCasClient{probingCasClient: newProbingCasClient()}
+
+		handler := NewOperationHandler(sha512Protocol, cas, cp, operationparser.New(protocol), WithSkipExistingContent())
+
+		_, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		cas.resetWritten()
+
+		_, _, _, err = handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Empty(t, cas.writtenURIs())
+		require.Len(t, cas.probedURIs(), 2*len(artifacts))
+	})
+
+	t.Run("success - probe error falls back to write", func(t *testing.T) {
+		cas := newProbingCasClient()
+		cas.sizeErr = errors.New("size error")
+
+		handler := NewOperationHandler(protocol, cas, cp, operationparser.New(protocol), WithSkipExistingContent())
+
+		_, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		cas.resetWritten()
+
+		_, _, _, err = handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Len(t, cas.writtenURIs(), len(artifacts))
+	})
+
+	t.Run("success - CAS client without size support always writes", func(t *testing.T) {
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+		handler := NewOperationHandler(protocol, cas, cp, operationparser.New(protocol), WithSkipExistingContent())
+
+		_, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		_, _, _, err = handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Len(t, cas.writtenURIs(), 2*len(artifacts))
+	})
+
+	t.Run("success - protocol without multihash algorithms always writes", func(t *testing.T) {
+		noHashProtocol := newMockProtocolClient().Protocol
+		noHashProtocol.MultihashAlgorithms = nil
+
+		cas := newProbingCasClient()
+
+		handler := NewOperationHandler(noHashProtocol, cas, cp, operationparser.New(protocol), WithSkipExistingContent())
+
+		_, artifacts, _, err := handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+
+		_, _, _, err = handler.PrepareTxnFiles(ops)
+		require.NoError(t, err)
+		require.Len(t, cas.writtenURIs(), 2*len(artifacts))
+		require.Empty(t, cas.probedURIs())
+	})
+}
+
 func TestWriteModelToCAS(t *testing.T) {
-	protocol := mocks.NewMockProtocolClient().Protocol
+	protocol := newMockProtocolClient().Protocol
 
 	handler := NewOperationHandler(
 		protocol,
@@ -344,13 +1256,21 @@ func TestWriteModelToCAS(t *testing.T) {
 		operationparser.New(protocol))
 
 	t.Run("success", func(t *testing.T) {
-		address, err := handler.writeModelToCAS(&models.CoreIndexFile{}, "alias")
+		address, err := handler.writeModelToCAS(context.Background(), &models.CoreIndexFile{}, compressionAlgorithm, "alias", 0)
 		require.NoError(t, err)
 		require.NotEmpty(t, address)
 	})
 
+	t.Run("error - maximum file size exceeded", func(t *testing.T) {
+		address, err := handler.writeModelToCAS(context.Background(), &models.CoreIndexFile{}, compressionAlgorithm, "alias", 1)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrMaxFileSizeExceeded))
+		require.Empty(t, address)
+		require.Contains(t, err.Error(), "alias file size")
+	})
+
 	t.Run("error - marshal fails", func(t *testing.T) {
-		address, err := handler.writeModelToCAS("test", "alias")
+		address, err := handler.writeModelToCAS(context.Background(), "test", compressionAlgorithm, "alias", 0)
 		require.Error(t, err)
 		require.Empty(t, address)
 		require.Contains(t, err.Error(), "failed to marshal alias file")
@@ -363,14 +1283,14 @@ func TestWriteModelToCAS(t *testing.T) {
 			compression.New(compression.WithDefaultAlgorithms()),
 			operationparser.New(protocol))
 
-		address, err := handlerWithCASError.writeModelToCAS(&models.CoreIndexFile{}, "alias")
+		address, err := handlerWithCASError.writeModelToCAS(context.Background(), &models.CoreIndexFile{}, compressionAlgorithm, "alias", 0)
 		require.Error(t, err)
 		require.Empty(t, address)
 		require.Contains(t, err.Error(), "failed to store alias file: CAS error")
 	})
 
 	t.Run("error - compression error", func(t *testing.T) {
-		pc := mocks.NewMockProtocolClient()
+		pc := newMockProtocolClient()
 		pc.Protocol.CompressionAlgorithm = "invalid"
 
 		handlerWithProtocolError := NewOperationHandler(
@@ -380,7 +1300,7 @@ func TestWriteModelToCAS(t *testing.T) {
 			operationparser.New(pc.Protocol),
 		)
 
-		address, err := handlerWithProtocolError.writeModelToCAS(&models.CoreIndexFile{}, "alias")
+		address, err := handlerWithProtocolError.writeModelToCAS(context.Background(), &models.CoreIndexFile{}, pc.Protocol.CompressionAlgorithm, "alias", 0)
 		require.Error(t, err)
 		require.Empty(t, address)
 		require.Contains(t, err.Error(), "compression algorithm 'invalid' not supported")
@@ -429,7 +1349,7 @@ func generateOperation(num int, opType operation.Type) (*model.Operation, error)
 		return nil, err
 	}
 
-	cp, err := mocks.NewMockProtocolClient().Current()
+	cp, err := newMockProtocolClient().Current()
 	if err != nil {
 		panic(err)
 	}
@@ -707,6 +1627,147 @@ func generateQueueOperationWithAnchorTimes(opType operation.Type, suffix string,
 	}, nil
 }
 
+// recordingCasClient records addresses of content written to and read from CAS (safe for concurrent use).
+type recordingCasClient struct {
+	*mocks.MockCasClient
+	mutex   sync.Mutex
+	written []string
+	reads   []string
+}
+
+func (c *recordingCasClient) Read(address string) ([]byte, error) {
+	c.mutex.Lock()
+	c.reads = append(c.reads, address)
+	c.mutex.Unlock()
+
+	return c.MockCasClient.Read(address)
+}
+
+func (c *recordingCasClient) Write(content []byte) (string, error) {
+	address, err := c.MockCasClient.Write(content)
+	if err != nil {
+		return "", err
+	}
+
+	c.mutex.Lock()
+	c.written = append(c.written, address)
+	c.mutex.Unlock()
+
+	return address, nil
+}
+
+// writtenURIs returns a snapshot of addresses written so far.
+func (c *recordingCasClient) writtenURIs() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]string(nil), c.written...)
+}
+
+// readURIs returns a snapshot of addresses read so far.
+func (c *recordingCasClient) readURIs() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]string(nil), c.reads...)
+}
+
+func (c *recordingCasClient) resetWritten() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.written = nil
+}
+
+// probingCasClient implements cas.SizeClient and records probed addresses (safe for concurrent use).
+type probingCasClient struct {
+	recordingCasClient
+	probes  []string
+	sizeErr error
+}
+
+func (c *probingCasClient) Size(address string) (int, error) {
+	c.mutex.Lock()
+	c.probes = append(c.probes, address)
+	c.mutex.Unlock()
+
+	if c.sizeErr != nil {
+		return 0, c.sizeErr
+	}
+
+	content, err := c.MockCasClient.Read(address)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(content), nil
+}
+
+// probedURIs returns a snapshot of addresses probed so far.
+func (c *probingCasClient) probedURIs() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]string(nil), c.probes...)
+}
+
+// addressingCasClient implements cas.AddressClient using the addressing scheme of the mock CAS.
+type addressingCasClient struct {
+	*probingCasClient
+}
+
+func (c *addressingCasClient) Address(content []byte) (string, error) {
+	hash, err := hashing.ComputeMultihash(sha2_256, content)
+	if err != nil {
+		return "", err
+	}
+
+	return encoder.EncodeToString(hash), nil
+}
+
+// corruptingCasClient returns overridden content for addresses in content map.
+type corruptingCasClient struct {
+	*mocks.MockCasClient
+	content map[string][]byte
+}
+
+func (c *corruptingCasClient) Read(address string) ([]byte, error) {
+	if content, ok := c.content[address]; ok {
+		return content, nil
+	}
+
+	return c.MockCasClient.Read(address)
+}
+
+type compressOnlyProvider struct {
+	cp *compression.Registry
+}
+
+func (p *compressOnlyProvider) Compress(alg string, data []byte) ([]byte, error) {
+	return p.cp.Compress(alg, data)
+}
+
+type delayedWriteCasClient struct {
+	*delayedCasClient
+}
+
+func (c *delayedWriteCasClient) Write(content []byte) (string, error) {
+	time.Sleep(c.delay)
+
+	return c.Client.Write(content)
+}
+
+type countingParser struct {
+	OperationParser
+	calls int
+}
+
+func (p *countingParser) ParseOperation(namespace string, operationBuffer []byte, batch bool) (*model.Operation, error) {
+	p.calls++
+
+	return p.OperationParser.ParseOperation(namespace, operationBuffer, batch)
+}
+
 type mockTimeValidator struct {
 	Err error
 }
@@ -733,3 +1794,15 @@ func (mtv *mockTimeValidator) Validate(from, until int64) error {
 
 	return nil
 }
+
+func readCompressedModel(t *testing.T, cp *compression.Registry, cas *mocks.MockCasClient, uri string, model interface{}) {
+	t.Helper()
+
+	content, err := cas.Read(uri)
+	require.NoError(t, err)
+
+	decompressed, err := cp.Decompress(compressionAlgorithm, content)
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal(decompressed, model))
+}
@@ -0,0 +1,32 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// ManifestFile defines the schema of a manifest file. Manifest file is referenced by versioned
+// anchor string and it provides indirection to core index file.
+type ManifestFile struct {
+
+	// CoreIndexFileURI is core index file URI
+	CoreIndexFileURI string `json:"coreIndexFileUri"`
+}
+
+// ParseManifestFile will parse manifest file from content.
+func ParseManifestFile(content []byte) (*ManifestFile, error) {
+	file := &ManifestFile{}
+	err := json.Unmarshal(content, file)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to unmarshal manifest file")
+	}
+
+	return file, nil
+}
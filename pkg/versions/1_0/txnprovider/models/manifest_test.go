@@ -0,0 +1,28 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifestFile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		parsed, err := ParseManifestFile([]byte(`{"coreIndexFileUri":"coreIndexURI"}`))
+		require.NoError(t, err)
+		require.Equal(t, "coreIndexURI", parsed.CoreIndexFileURI)
+	})
+
+	t.Run("error - unmarshal error", func(t *testing.T) {
+		parsed, err := ParseManifestFile([]byte("not JSON"))
+		require.Error(t, err)
+		require.Nil(t, parsed)
+		require.Contains(t, err.Error(), "failed to unmarshal manifest file")
+	})
+}
@@ -7,14 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package txnprovider
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"github.com/trustbloc/edge-core/pkg/log"
 
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider/models"
 )
@@ -26,16 +36,177 @@ type DCAS interface {
 	Read(key string) ([]byte, error)
 }
 
+// ContextDCAS is context-aware interface to access content addressable storage. If CAS client implements it,
+// CAS reads are cancelled once context is done; otherwise blocking reads are abandoned.
+type ContextDCAS interface {
+	ReadContext(ctx context.Context, key string) ([]byte, error)
+}
+
+// SizeDCAS is interface for retrieving content size from content addressable storage. If CAS client implements it,
+// content that exceeds maximum file size is rejected before it is read.
+type SizeDCAS interface {
+	Size(key string) (int, error)
+}
+
 type decompressionProvider interface {
 	Decompress(alg string, data []byte) ([]byte, error)
 }
 
+// streamDecompressionProvider is implemented by decompression providers that can decompress content without
+// buffering whole decompressed content first (e.g. compression registry).
+type streamDecompressionProvider interface {
+	DecompressStream(alg string, reader io.Reader, writer io.Writer) error
+}
+
 // OperationProvider is an operation provider.
 type OperationProvider struct {
 	protocol.Protocol
 	parser OperationParser
 	cas    DCAS
 	dp     decompressionProvider
+
+	skipUnparseableOperations bool
+	contentCache              *lru.Cache
+	retryPolicy               RetryPolicy
+	metrics                   Metrics
+	fallbackCAS               []DCAS
+	verifyContentHash         bool
+	logger                    Logger
+	namespace                 string
+	anchorCodec               AnchorCodec
+}
+
+// File types reported to metrics.
+const (
+	CoreIndexFileType        = "core_index"
+	CoreProofFileType        = "core_proof"
+	ProvisionalIndexFileType = "provisional_index"
+	ProvisionalProofFileType = "provisional_proof"
+	ChunkFileType            = "chunk"
+	ManifestFileType         = "manifest"
+)
+
+// Metrics receives CAS read metrics per file type.
+type Metrics interface {
+	// CASReadDuration is invoked with the time it took to read file content from CAS
+	CASReadDuration(fileType string, duration time.Duration)
+	// CASReadBytes is invoked with the size of (compressed) file content read from CAS
+	CASReadBytes(fileType string, size int)
+}
+
+type noopMetrics struct{}
+
+func (m *noopMetrics) CASReadDuration(string, time.Duration) {}
+
+func (m *noopMetrics) CASReadBytes(string, int) {}
+
+// RetryPolicy defines retry policy for transient CAS read failures.
+type RetryPolicy struct {
+	// MaxAttempts is maximum number of read attempts (including the first one); zero or one disables retries
+	MaxAttempts int
+	// BaseDelay is delay before the first retry; delay doubles for every subsequent retry
+	BaseDelay time.Duration
+}
+
+// casContent holds decompressed CAS content together with compressed content size.
+type casContent struct {
+	content []byte
+	size    int
+}
+
+// Logger is used by operation provider for logging (e.g. skipped operations).
+type Logger interface {
+	Debugf(msg string, args ...interface{})
+	Warnf(msg string, args ...interface{})
+}
+
+// Option is an operation provider instance option.
+type Option func(opts *OperationProvider)
+
+// WithLogger sets logger for operation provider (default logger is used if not set).
+func WithLogger(l Logger) Option {
+	return func(opts *OperationProvider) {
+		opts.logger = l
+	}
+}
+
+// WithSkipUnparseableOperations instructs operation provider to skip (and log) individual operations
+// with unparseable suffix data, signed data or delta instead of failing the whole batch.
+func WithSkipUnparseableOperations(skip bool) Option {
+	return func(opts *OperationProvider) {
+		opts.skipUnparseableOperations = skip
+	}
+}
+
+// WithLenientAssembly instructs operation provider to return valid operations from a batch even if some
+// of the operations in the batch are malformed. Malformed operations are logged and skipped.
+// Strict assembly (the whole batch is rejected) is the default.
+func WithLenientAssembly() Option {
+	return WithSkipUnparseableOperations(true)
+}
+
+// WithContentCache enables LRU cache of decompressed CAS content (keyed by CAS URI and compression algorithm)
+// for up to capacity entries.
+// Zero capacity disables the cache.
+func WithContentCache(capacity int) Option {
+	return func(opts *OperationProvider) {
+		if capacity <= 0 {
+			opts.contentCache = nil
+
+			return
+		}
+
+		// error is returned for non-positive capacity only
+		opts.contentCache, _ = lru.New(capacity) //nolint:errcheck
+	}
+}
+
+// WithCASReadRetry sets retry policy for CAS reads. Content not found errors (cas.ErrContentNotFound) are not retried.
+func WithCASReadRetry(policy RetryPolicy) Option {
+	return func(opts *OperationProvider) {
+		opts.retryPolicy = policy
+	}
+}
+
+// WithFallbackCAS sets an ordered list of CAS clients that are tried (in order) if content
+// cannot be read from the primary CAS client.
+func WithFallbackCAS(clients ...DCAS) Option {
+	return func(opts *OperationProvider) {
+		opts.fallbackCAS = clients
+	}
+}
+
+// WithMetrics sets metrics for CAS reads. By default metrics are not collected.
+func WithMetrics(metrics Metrics) Option {
+	return func(opts *OperationProvider) {
+		opts.metrics = metrics
+	}
+}
+
+// WithContentHashVerification instructs operation provider to verify that the multihash of (compressed)
+// content read from CAS matches the requested CAS URI. It should be enabled only if CAS URIs are
+// encoded multihashes of the content.
+func WithContentHashVerification() Option {
+	return func(opts *OperationProvider) {
+		opts.verifyContentHash = true
+	}
+}
+
+// WithNamespace sets namespace (e.g. "did:sidetree") that transactions have to belong to; transactions
+// with different namespace are rejected before batch files are read from CAS. By default namespace is not validated.
+func WithNamespace(namespace string) Option {
+	return func(opts *OperationProvider) {
+		opts.namespace = namespace
+	}
+}
+
+// WithAnchorCodec sets codec for decoding anchor strings (default is Sidetree anchor string format).
+// Custom codec is responsible for enforcing protocol maximum operation count (e.g. by decoding anchor
+// data with ParseAnchorData and WithMaxOperationCount option).
+func WithAnchorCodec(codec AnchorCodec) Option {
+	return func(opts *OperationProvider) {
+		opts.anchorCodec = codec
+	}
 }
 
 // OperationParser defines the functions for parsing operations.
@@ -49,44 +220,201 @@ type OperationParser interface {
 }
 
 // NewOperationProvider returns a new operation provider.
-func NewOperationProvider(p protocol.Protocol, parser OperationParser, cas DCAS, dp decompressionProvider) *OperationProvider {
-	return &OperationProvider{
-		Protocol: p,
-		parser:   parser,
-		cas:      cas,
-		dp:       dp,
+// Protocol parameters are expected to be validated by the caller (see protocol.Protocol.Validate).
+func NewOperationProvider(p protocol.Protocol, parser OperationParser, cas DCAS, dp decompressionProvider, opts ...Option) *OperationProvider {
+	op := &OperationProvider{
+		Protocol:    p,
+		parser:      parser,
+		cas:         cas,
+		dp:          dp,
+		metrics:     &noopMetrics{},
+		logger:      logger,
+		anchorCodec: NewDefaultAnchorCodec(WithMaxOperationCount(p.MaxOperationCount)),
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	return op
+}
+
+// CheckCAS checks whether primary CAS is reachable (e.g. for readiness probe). CAS client has to either
+// implement cas.PingClient or support writes (sentinel content is written and read back).
+func (h *OperationProvider) CheckCAS(ctx context.Context) error {
+	switch c := h.cas.(type) {
+	case cas.PingClient:
+		return c.Ping(ctx)
+	case cas.Client:
+		return cas.NewPingClient(c).Ping(ctx)
+	default:
+		return errors.New("CAS client doesn't support health check")
 	}
 }
 
 // GetTxnOperations will read batch files(core/provisional index, proof files and chunk file)
 // and assemble batch operations from those files.
 func (h *OperationProvider) GetTxnOperations(txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
-	// parse core index file URI and number of operations from anchor string
-	anchorData, err := ParseAnchorData(txn.AnchorString)
+	return h.GetTxnOperationsContext(context.Background(), txn)
+}
+
+// GetTxnOperationsContext is the same as GetTxnOperations but it stops retrieving batch files
+// from CAS once the given context is done.
+func (h *OperationProvider) GetTxnOperationsContext(ctx context.Context, txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	txnOps, _, err := h.GetTxnOperationsWithSkipCount(ctx, txn)
+
+	return txnOps, err
+}
+
+// GetTxnOperationsWithSkipCount will read batch files and assemble batch operations from those files.
+// It also returns the number of operations that were skipped because they couldn't be parsed
+// (always zero unless WithSkipUnparseableOperations option is enabled).
+func (h *OperationProvider) GetTxnOperationsWithSkipCount(ctx context.Context, txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, int, error) {
+	ops, skipped, err := h.getTxnOperations(ctx, txn)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	txnOps, err := createAnchoredOperations(ops)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return txnOps, skipped, nil
+}
+
+// ForEachTxnOperation will read batch files, assemble batch operations from those files and invoke the given
+// function for each operation (in the same order as returned by GetTxnOperations). It is not a streaming API:
+// all batch files are read and validated (including number of operations) and all operations are assembled
+// before the first invocation, so memory usage is the same as for GetTxnOperations; only conversion to
+// anchored operations is done one operation at a time.
+// Iteration stops at the first error returned by the function and that error is returned.
+func (h *OperationProvider) ForEachTxnOperation(ctx context.Context, txn *txn.SidetreeTxn, fnc func(op *operation.AnchoredOperation) error) error {
+	ops, _, err := h.getTxnOperations(ctx, txn)
+	if err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		anchoredOp, err := model.GetAnchoredOperation(op)
+		if err != nil {
+			return err
+		}
+
+		err = fnc(anchoredOp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CollectCASURIs returns URIs of all CAS files that are (transitively) referenced by the transaction anchor string:
+// manifest file (if any), core index file, core proof file, provisional index file, provisional proof file and
+// chunk files. Only index files are read from CAS and operations are not assembled.
+func (h *OperationProvider) CollectCASURIs(txn *txn.SidetreeTxn) ([]string, error) {
+	return h.CollectCASURIsContext(context.Background(), txn)
+}
+
+// CollectCASURIsContext is the same as CollectCASURIs but it stops reading index files from CAS
+// once the given context is done.
+func (h *OperationProvider) CollectCASURIsContext(ctx context.Context, txn *txn.SidetreeTxn) ([]string, error) {
+	anchorData, err := h.anchorCodec.Decode(txn.AnchorString)
 	if err != nil {
 		return nil, err
 	}
 
-	cif, err := h.getCoreIndexFile(anchorData.CoreIndexFileURI)
+	var uris []string
+
+	if anchorData.ManifestURI != "" {
+		uris = append(uris, anchorData.ManifestURI)
+	}
+
+	coreIndexFileURI, err := h.getCoreIndexFileURI(ctx, anchorData)
 	if err != nil {
 		return nil, err
 	}
 
-	batchFiles, err := h.getBatchFiles(cif)
+	cif, err := h.getCoreIndexFile(ctx, coreIndexFileURI)
 	if err != nil {
 		return nil, err
 	}
 
-	txnOps, err := h.assembleAnchoredOperations(batchFiles, txn)
+	uris = appendURIs(uris, coreIndexFileURI, cif.CoreProofFileURI, cif.ProvisionalIndexFileURI)
+
+	if cif.ProvisionalIndexFileURI == "" {
+		return uris, nil
+	}
+
+	pif, err := h.getProvisionalIndexFile(ctx, cif.ProvisionalIndexFileURI)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(txnOps) != anchorData.NumberOfOperations {
-		return nil, fmt.Errorf("number of txn ops[%d] doesn't match anchor string num of ops[%d]", len(txnOps), anchorData.NumberOfOperations)
+	uris = appendURIs(uris, pif.ProvisionalProofFileURI)
+
+	for _, chunk := range pif.Chunks {
+		uris = appendURIs(uris, chunk.ChunkFileURI)
 	}
 
-	return txnOps, nil
+	return uris, nil
+}
+
+// appendURIs appends non-empty URIs.
+func appendURIs(uris []string, values ...string) []string {
+	for _, uri := range values {
+		if uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+
+	return uris
+}
+
+func (h *OperationProvider) getTxnOperations(ctx context.Context, txn *txn.SidetreeTxn) ([]*model.Operation, int, error) {
+	if h.namespace != "" && txn.Namespace != h.namespace {
+		return nil, 0, fmt.Errorf("transaction namespace[%s] doesn't match operation provider namespace[%s]",
+			txn.Namespace, h.namespace)
+	}
+
+	// parse core index file URI and number of operations from anchor string
+	anchorData, err := h.anchorCodec.Decode(txn.AnchorString)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	coreIndexFileURI, err := h.getCoreIndexFileURI(ctx, anchorData)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cif, err := h.getCoreIndexFile(ctx, coreIndexFileURI)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	batchFiles, err := h.getBatchFiles(ctx, cif)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = validateOperationCount(anchorData.NumberOfOperations, batchFiles)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ops, skipped, err := h.assembleValidOperations(batchFiles, txn)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if skipped > 0 {
+		h.logger.Warnf("skipped %d unparseable operations for anchor string: %s", skipped, txn.AnchorString)
+	}
+
+	return ops, skipped, nil
 }
 
 // batchFiles contains the content of all batch files that are referenced in core index file.
@@ -105,71 +433,164 @@ type provisionalFiles struct {
 }
 
 // getBatchFiles retrieves all batch files that are referenced in core index file.
-func (h *OperationProvider) getBatchFiles(cif *models.CoreIndexFile) (*batchFiles, error) {
-	var err error
-
+func (h *OperationProvider) getBatchFiles(ctx context.Context, cif *models.CoreIndexFile) (*batchFiles, error) {
 	files := &batchFiles{CoreIndex: cif}
 
+	// pending reads are cancelled as soon as one of the reads fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg          sync.WaitGroup
+		provisional *provisionalFiles
+		errs        = &firstError{cancel: cancel}
+	)
+
+	// core proof and provisional files are independent so they are retrieved concurrently
+
 	// core proof file will not exist if we have only update operations in the batch
 	if cif.CoreProofFileURI != "" {
-		files.CoreProof, err = h.getCoreProofFile(cif.CoreProofFileURI)
-		if err != nil {
-			return nil, err
-		}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var err error
+
+			files.CoreProof, err = h.getCoreProofFile(ctx, cif.CoreProofFileURI)
+			errs.set(err)
+		}()
 	}
 
 	if cif.ProvisionalIndexFileURI != "" {
-		provisionalFiles, innerErr := h.getProvisionalFiles(cif.ProvisionalIndexFileURI)
-		if innerErr != nil {
-			return nil, innerErr
-		}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var err error
+
+			provisional, err = h.getProvisionalFiles(ctx, cif.ProvisionalIndexFileURI)
+			errs.set(err)
+		}()
+	}
 
-		files.ProvisionalIndex = provisionalFiles.ProvisionalIndex
-		files.ProvisionalProof = provisionalFiles.ProvisionalProof
-		files.Chunk = provisionalFiles.Chunk
+	wg.Wait()
+
+	if errs.err != nil {
+		return nil, errs.err
+	}
+
+	if provisional != nil {
+		files.ProvisionalIndex = provisional.ProvisionalIndex
+		files.ProvisionalProof = provisional.ProvisionalProof
+		files.Chunk = provisional.Chunk
 	}
 
 	// validate batch file counts
-	err = validateBatchFileCounts(files)
+	err := validateBatchFileCounts(files)
 	if err != nil {
 		return nil, err
 	}
 
-	logger.Debugf("successfully downloaded and validated all batch files")
+	h.logger.Debugf("successfully downloaded and validated all batch files")
 
 	return files, nil
 }
 
-func (h *OperationProvider) getProvisionalFiles(provisionalIndexURI string) (*provisionalFiles, error) {
+func (h *OperationProvider) getProvisionalFiles(ctx context.Context, provisionalIndexURI string) (*provisionalFiles, error) {
 	var err error
 	files := &provisionalFiles{}
 
-	files.ProvisionalIndex, err = h.getProvisionalIndexFile(provisionalIndexURI)
+	files.ProvisionalIndex, err = h.getProvisionalIndexFile(ctx, provisionalIndexURI)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(files.ProvisionalIndex.Chunks) == 0 {
+		return nil, errors.Errorf("provisional index file is missing chunk file URI")
+	}
+
+	// pending reads are cancelled as soon as one of the reads fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		errs = &firstError{cancel: cancel}
+	)
+
+	// provisional proof and chunk files are retrieved concurrently
+
 	// provisional proof file will not exist if we don't have any update operations in the batch
 	if files.ProvisionalIndex.ProvisionalProofFileURI != "" {
-		files.ProvisionalProof, err = h.getProvisionalProofFile(files.ProvisionalIndex.ProvisionalProofFileURI)
-		if err != nil {
-			return nil, err
-		}
-	}
+		wg.Add(1)
 
-	if len(files.ProvisionalIndex.Chunks) == 0 {
-		return nil, errors.Errorf("provisional index file is missing chunk file URI")
+		go func() {
+			defer wg.Done()
+
+			var proofErr error
+
+			files.ProvisionalProof, proofErr = h.getProvisionalProofFile(ctx, files.ProvisionalIndex.ProvisionalProofFileURI)
+			errs.set(proofErr)
+		}()
 	}
 
-	chunkURI := files.ProvisionalIndex.Chunks[0].ChunkFileURI
-	files.Chunk, err = h.getChunkFile(chunkURI)
-	if err != nil {
-		return nil, err
+	var chunkErr error
+
+	files.Chunk, chunkErr = h.getChunkFiles(ctx, files.ProvisionalIndex.Chunks)
+	errs.set(chunkErr)
+
+	wg.Wait()
+
+	if errs.err != nil {
+		return nil, errs.err
 	}
 
 	return files, nil
 }
 
+// firstError records the first error of concurrent CAS reads and cancels remaining reads.
+type firstError struct {
+	mutex  sync.Mutex
+	err    error
+	cancel context.CancelFunc
+}
+
+func (e *firstError) set(err error) {
+	if err == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.err == nil {
+		e.err = err
+		e.cancel()
+	}
+}
+
+// getChunkFiles retrieves chunk files (in order) and combines their deltas into a single chunk file.
+func (h *OperationProvider) getChunkFiles(ctx context.Context, chunks []models.Chunk) (*models.ChunkFile, error) {
+	if len(chunks) == 1 {
+		return h.getChunkFile(ctx, chunks[0].ChunkFileURI)
+	}
+
+	combined := &models.ChunkFile{}
+
+	for _, chunk := range chunks {
+		cf, err := h.getChunkFile(ctx, chunk.ChunkFileURI)
+		if err != nil {
+			return nil, err
+		}
+
+		combined.Deltas = append(combined.Deltas, cf.Deltas...)
+	}
+
+	return combined, nil
+}
+
 // validateBatchFileCounts validates that operation numbers match in batch files.
 func validateBatchFileCounts(batchFiles *batchFiles) error {
 	coreCreateNum := 0
@@ -212,10 +633,11 @@ func validateBatchFileCounts(batchFiles *batchFiles) error {
 			}
 		}
 
+		// delta count implied by index files has to match actual number of deltas in chunk file
 		expectedDeltaCount := coreCreateNum + coreRecoverNum + provisionalUpdateNum
 
 		if expectedDeltaCount != len(batchFiles.Chunk.Deltas) {
-			return fmt.Errorf("number of create+recover+update operations[%d] doesn't match number of deltas[%d]",
+			return fmt.Errorf("chunk delta count mismatch: number of create+recover+update operations[%d] doesn't match number of deltas[%d]",
 				expectedDeltaCount, len(batchFiles.Chunk.Deltas))
 		}
 	}
@@ -223,6 +645,30 @@ func validateBatchFileCounts(batchFiles *batchFiles) error {
 	return nil
 }
 
+// validateOperationCount cross-checks number of operations in batch files against number of operations
+// in anchor string; returned error contains number of operations per index file. Chunk file deltas don't
+// have to be checked here since their count has already been validated against index files
+// (see validateBatchFileCounts).
+func validateOperationCount(expected int, batchFiles *batchFiles) error {
+	coreNum := 0
+	if batchFiles.CoreIndex.Operations != nil {
+		coreNum = len(batchFiles.CoreIndex.Operations.Create) + len(batchFiles.CoreIndex.Operations.Recover) +
+			len(batchFiles.CoreIndex.Operations.Deactivate)
+	}
+
+	provisionalNum := 0
+	if batchFiles.ProvisionalIndex != nil && batchFiles.ProvisionalIndex.Operations != nil {
+		provisionalNum = len(batchFiles.ProvisionalIndex.Operations.Update)
+	}
+
+	if coreNum+provisionalNum != expected {
+		return fmt.Errorf("number of txn ops[%d] doesn't match anchor string num of ops[%d]: core[%d] provisional[%d]",
+			coreNum+provisionalNum, expected, coreNum, provisionalNum)
+	}
+
+	return nil
+}
+
 func createAnchoredOperations(ops []*model.Operation) ([]*operation.AnchoredOperation, error) {
 	var anchoredOps []*operation.AnchoredOperation
 	for _, op := range ops {
@@ -236,13 +682,29 @@ func createAnchoredOperations(ops []*model.Operation) ([]*operation.AnchoredOper
 	return anchoredOps, nil
 }
 
-func (h *OperationProvider) assembleAnchoredOperations(batchFiles *batchFiles, txn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) { //nolint:funlen
+// assembleValidOperations assembles operations from batch files and filters out unparseable operations
+// if WithSkipUnparseableOperations option is enabled. returns operations and the number of skipped operations.
+func (h *OperationProvider) assembleValidOperations(batchFiles *batchFiles, txn *txn.SidetreeTxn) ([]*model.Operation, int, error) {
+	ops, err := h.assembleOperations(batchFiles, txn)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var skipped int
+	if h.skipUnparseableOperations {
+		ops, skipped = h.filterUnparseableOperations(ops)
+	}
+
+	return ops, skipped, nil
+}
+
+func (h *OperationProvider) assembleOperations(batchFiles *batchFiles, txn *txn.SidetreeTxn) ([]*model.Operation, error) { //nolint:funlen
 	cifOps, err := h.parseCoreIndexOperations(batchFiles.CoreIndex, txn)
 	if err != nil {
 		return nil, fmt.Errorf("parse core index operations: %s", err.Error())
 	}
 
-	logger.Debugf("successfully parsed core index operations: create[%d], recover[%d], deactivate[%d]",
+	h.logger.Debugf("successfully parsed core index operations: create[%d], recover[%d], deactivate[%d]",
 		len(cifOps.Create), len(cifOps.Recover), len(cifOps.Deactivate))
 
 	// add signed data from core proof file to deactivate operations
@@ -252,20 +714,25 @@ func (h *OperationProvider) assembleAnchoredOperations(batchFiles *batchFiles, t
 
 	// deactivate operations only
 	if batchFiles.CoreIndex.ProvisionalIndexFileURI == "" {
-		return createAnchoredOperations(cifOps.Deactivate)
+		return cifOps.Deactivate, nil
 	}
 
 	pifOps := parseProvisionalIndexOperations(batchFiles.ProvisionalIndex)
 
-	logger.Debugf("successfully parsed provisional index operations: update[%d]", len(pifOps.Update))
+	h.logger.Debugf("successfully parsed provisional index operations: update[%d]", len(pifOps.Update))
 
 	// check for duplicate suffixes for this combination core/provisional index files
-	txnSuffixes := append(cifOps.Suffixes, pifOps.Suffixes...)
-	err = checkForDuplicates(txnSuffixes)
+	// (e.g. DID cannot be created in core index file and updated in provisional index file in the same batch)
+	err = checkForCrossFileDuplicates(cifOps, pifOps)
 	if err != nil {
 		return nil, fmt.Errorf("check for duplicate suffixes in core/provisional index files: %s", err.Error())
 	}
 
+	err = checkForDuplicates(pifOps.Suffixes)
+	if err != nil {
+		return nil, fmt.Errorf("check for duplicate suffixes in provisional index file: %s", err.Error())
+	}
+
 	var operations []*model.Operation
 	operations = append(operations, cifOps.Create...)
 
@@ -276,6 +743,11 @@ func (h *OperationProvider) assembleAnchoredOperations(batchFiles *batchFiles, t
 		// parse signed data to extract anchor origin
 		signedDataModel, err := h.parser.ParseSignedDataForRecover(cifOps.Recover[i].SignedData)
 		if err != nil {
+			if h.skipUnparseableOperations {
+				// operation will be skipped after assembly
+				continue
+			}
+
 			return nil, fmt.Errorf("failed to validate signed data for recover[%d]: %s", i, err.Error())
 		}
 
@@ -303,7 +775,52 @@ func (h *OperationProvider) assembleAnchoredOperations(batchFiles *batchFiles, t
 
 	operations = append(operations, cifOps.Deactivate...)
 
-	return createAnchoredOperations(operations)
+	return operations, nil
+}
+
+// filterUnparseableOperations removes operations with unparseable suffix data, signed data or delta.
+// returns valid operations and the number of skipped operations.
+func (h *OperationProvider) filterUnparseableOperations(ops []*model.Operation) ([]*model.Operation, int) {
+	var validOps []*model.Operation
+
+	for _, op := range ops {
+		err := h.validateOperation(op)
+		if err != nil {
+			h.logger.Warnf("skipping unparseable %s operation for suffix[%s]: %s", op.Type, op.UniqueSuffix, err.Error())
+
+			continue
+		}
+
+		validOps = append(validOps, op)
+	}
+
+	return validOps, len(ops) - len(validOps)
+}
+
+// validateOperation validates operation content that is not validated at batch file level
+// when skipping of unparseable operations is enabled.
+func (h *OperationProvider) validateOperation(op *model.Operation) error {
+	var err error
+
+	switch op.Type {
+	case operation.TypeCreate:
+		err = h.parser.ValidateSuffixData(op.SuffixData)
+	case operation.TypeUpdate:
+		_, err = h.parser.ParseSignedDataForUpdate(op.SignedData)
+	case operation.TypeRecover:
+		_, err = h.parser.ParseSignedDataForRecover(op.SignedData)
+	case operation.TypeDeactivate:
+		_, err = h.parser.ParseSignedDataForDeactivate(op.SignedData)
+
+		// deactivate operation doesn't have delta
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return h.parser.ValidateDelta(op.Delta)
 }
 
 func checkForDuplicates(values []string) error {
@@ -326,14 +843,93 @@ func checkForDuplicates(values []string) error {
 	return nil
 }
 
+// checkForCrossFileDuplicates reports suffixes that appear in both core and provisional index files
+// together with the colliding operation types.
+func checkForCrossFileDuplicates(cifOps *coreOperations, pifOps *provisionalOperations) error {
+	coreTypes := make(map[string]operation.Type)
+
+	for _, ops := range [][]*model.Operation{cifOps.Create, cifOps.Recover, cifOps.Deactivate} {
+		for _, op := range ops {
+			coreTypes[op.UniqueSuffix] = op.Type
+		}
+	}
+
+	var duplicates []string
+
+	var details []string
+
+	for _, op := range pifOps.Update {
+		coreType, ok := coreTypes[op.UniqueSuffix]
+		if !ok {
+			continue
+		}
+
+		duplicates = append(duplicates, op.UniqueSuffix)
+		details = append(details, fmt.Sprintf("suffix[%s] has %s operation in core index file and %s operation in provisional index file",
+			op.UniqueSuffix, coreType, op.Type))
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("duplicate values found %v: %s", duplicates, strings.Join(details, "; "))
+	}
+
+	return nil
+}
+
+// getCoreIndexFileURI returns core index file URI from anchor data; for versioned anchor string format
+// core index file URI is resolved from manifest file.
+func (h *OperationProvider) getCoreIndexFileURI(ctx context.Context, anchorData *AnchorData) (string, error) {
+	if anchorData.ManifestURI == "" {
+		return anchorData.CoreIndexFileURI, nil
+	}
+
+	mf, err := h.getManifestFile(ctx, anchorData.ManifestURI)
+	if err != nil {
+		return "", err
+	}
+
+	return mf.CoreIndexFileURI, nil
+}
+
+// getManifestFile will download manifest file from cas and parse it into manifest file model.
+func (h *OperationProvider) getManifestFile(ctx context.Context, uri string) (*models.ManifestFile, error) {
+	// manifest file is expected to be much smaller than core index file it references
+	content, err := h.readFromCAS(ctx, ManifestFileType, uri, h.MaxCoreIndexFileSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading manifest file")
+	}
+
+	h.logger.Debugf("successfully downloaded manifest file uri[%s]: %s", uri, string(content))
+
+	mf, err := models.ParseManifestFile(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse content for manifest file[%s]", uri)
+	}
+
+	err = h.validateManifestFile(mf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "manifest file[%s]", uri)
+	}
+
+	return mf, nil
+}
+
+func (h *OperationProvider) validateManifestFile(mf *models.ManifestFile) error {
+	if mf.CoreIndexFileURI == "" {
+		return errors.New("missing core index file URI")
+	}
+
+	return h.validateURI(mf.CoreIndexFileURI)
+}
+
 // getCoreIndexFile will download core index file from cas and parse it into core index file model.
-func (h *OperationProvider) getCoreIndexFile(uri string) (*models.CoreIndexFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxCoreIndexFileSize)
+func (h *OperationProvider) getCoreIndexFile(ctx context.Context, uri string) (*models.CoreIndexFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, CoreIndexFileType, uri, h.MaxCoreIndexFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading core index file")
 	}
 
-	logger.Debugf("successfully downloaded core index file uri[%s]: %s", uri, string(content))
+	h.logger.Debugf("successfully downloaded core index file uri[%s]: %s", uri, string(content))
 
 	cif, err := models.ParseCoreIndexFile(content)
 	if err != nil {
@@ -349,14 +945,22 @@ func (h *OperationProvider) getCoreIndexFile(uri string) (*models.CoreIndexFile,
 }
 
 func (h *OperationProvider) validateCoreIndexFile(cif *models.CoreIndexFile) error {
+	createNum := 0
 	recoverNum := 0
 	deactivateNum := 0
 
 	if cif.Operations != nil {
+		createNum = len(cif.Operations.Create)
 		recoverNum = len(cif.Operations.Recover)
 		deactivateNum = len(cif.Operations.Deactivate)
 	}
 
+	// provisional index file can be omitted only for deactivate-only batches since
+	// create and recover operations have their deltas in chunk file(s)
+	if createNum+recoverNum > 0 && cif.ProvisionalIndexFileURI == "" {
+		return errors.New("missing provisional index file for create/recover operations")
+	}
+
 	if recoverNum+deactivateNum > 0 && cif.CoreProofFileURI == "" {
 		return errors.New("missing core proof file URI")
 	}
@@ -391,6 +995,11 @@ func (h *OperationProvider) validateCoreIndexOperations(ops *models.CoreOperatio
 	}
 
 	for i, op := range ops.Create {
+		if h.skipUnparseableOperations {
+			// suffix data will be validated per operation during assembly
+			break
+		}
+
 		err := h.parser.ValidateSuffixData(op.SuffixData)
 		if err != nil {
 			return fmt.Errorf("failed to validate suffix data for create[%d]: %s", i, err.Error())
@@ -435,13 +1044,13 @@ func (h *OperationProvider) validateRequiredMultihash(mh, alias string) error {
 }
 
 // getCoreProofFile will download core proof file from cas and parse it into core proof file model.
-func (h *OperationProvider) getCoreProofFile(uri string) (*models.CoreProofFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxProofFileSize)
+func (h *OperationProvider) getCoreProofFile(ctx context.Context, uri string) (*models.CoreProofFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, CoreProofFileType, uri, h.MaxProofFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading core proof file")
 	}
 
-	logger.Debugf("successfully downloaded core proof file uri[%s]: %s", uri, string(content))
+	h.logger.Debugf("successfully downloaded core proof file uri[%s]: %s", uri, string(content))
 
 	cpf, err := models.ParseCoreProofFile(content)
 	if err != nil {
@@ -457,6 +1066,11 @@ func (h *OperationProvider) getCoreProofFile(uri string) (*models.CoreProofFile,
 }
 
 func (h *OperationProvider) validateCoreProofFile(cpf *models.CoreProofFile) error {
+	if h.skipUnparseableOperations {
+		// signed data will be validated per operation during assembly
+		return nil
+	}
+
 	for i, signedData := range cpf.Operations.Recover {
 		_, err := h.parser.ParseSignedDataForRecover(signedData)
 		if err != nil {
@@ -475,13 +1089,13 @@ func (h *OperationProvider) validateCoreProofFile(cpf *models.CoreProofFile) err
 }
 
 // getProvisionalProofFile will download provisional proof file from cas and parse it into provisional proof file model.
-func (h *OperationProvider) getProvisionalProofFile(uri string) (*models.ProvisionalProofFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxProofFileSize)
+func (h *OperationProvider) getProvisionalProofFile(ctx context.Context, uri string) (*models.ProvisionalProofFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, ProvisionalProofFileType, uri, h.MaxProofFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading provisional proof file")
 	}
 
-	logger.Debugf("successfully downloaded provisional proof file uri[%s]: %s", uri, string(content))
+	h.logger.Debugf("successfully downloaded provisional proof file uri[%s]: %s", uri, string(content))
 
 	ppf, err := models.ParseProvisionalProofFile(content)
 	if err != nil {
@@ -497,6 +1111,11 @@ func (h *OperationProvider) getProvisionalProofFile(uri string) (*models.Provisi
 }
 
 func (h *OperationProvider) validateProvisionalProofFile(ppf *models.ProvisionalProofFile) error {
+	if h.skipUnparseableOperations {
+		// signed data will be validated per operation during assembly
+		return nil
+	}
+
 	for i, signedData := range ppf.Operations.Update {
 		_, err := h.parser.ParseSignedDataForUpdate(signedData)
 		if err != nil {
@@ -508,13 +1127,13 @@ func (h *OperationProvider) validateProvisionalProofFile(ppf *models.Provisional
 }
 
 // getProvisionalIndexFile will download provisional index file from cas and parse it into provisional index file model.
-func (h *OperationProvider) getProvisionalIndexFile(uri string) (*models.ProvisionalIndexFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxProvisionalIndexFileSize)
+func (h *OperationProvider) getProvisionalIndexFile(ctx context.Context, uri string) (*models.ProvisionalIndexFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, ProvisionalIndexFileType, uri, h.MaxProvisionalIndexFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading provisional index file")
 	}
 
-	logger.Debugf("successfully downloaded provisional index file uri[%s]: %s", uri, string(content))
+	h.logger.Debugf("successfully downloaded provisional index file uri[%s]: %s", uri, string(content))
 
 	pif, err := models.ParseProvisionalIndexFile(content)
 	if err != nil {
@@ -557,8 +1176,8 @@ func (h *OperationProvider) validateProvisionalIndexCASReferences(pif *models.Pr
 		return errors.Wrapf(err, "provisional proof URI")
 	}
 
-	if len(pif.Chunks) > 0 {
-		if err := h.validateURI(pif.Chunks[0].ChunkFileURI); err != nil {
+	for _, chunk := range pif.Chunks {
+		if err := h.validateURI(chunk.ChunkFileURI); err != nil {
 			return errors.Wrapf(err, "chunk URI")
 		}
 	}
@@ -582,13 +1201,13 @@ func (h *OperationProvider) validateProvisionalIndexOperations(ops *models.Provi
 }
 
 // getChunkFile will download chunk file from cas and parse it into chunk file model.
-func (h *OperationProvider) getChunkFile(uri string) (*models.ChunkFile, error) { //nolint:dupl
-	content, err := h.readFromCAS(uri, h.MaxChunkFileSize)
+func (h *OperationProvider) getChunkFile(ctx context.Context, uri string) (*models.ChunkFile, error) { //nolint:dupl
+	content, err := h.readFromCAS(ctx, ChunkFileType, uri, h.MaxChunkFileSize)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading chunk file")
 	}
 
-	logger.Debugf("successfully downloaded chunk file uri[%s]: %s", uri, string(content))
+	h.logger.Debugf("successfully downloaded chunk file uri[%s]: %s", uri, string(content))
 
 	cf, err := models.ParseChunkFile(content)
 	if err != nil {
@@ -604,6 +1223,11 @@ func (h *OperationProvider) getChunkFile(uri string) (*models.ChunkFile, error)
 }
 
 func (h *OperationProvider) validateChunkFile(cf *models.ChunkFile) error {
+	if h.skipUnparseableOperations {
+		// deltas will be validated per operation during assembly
+		return nil
+	}
+
 	for i, delta := range cf.Deltas {
 		err := h.parser.ValidateDelta(delta)
 		if err != nil {
@@ -614,29 +1238,317 @@ func (h *OperationProvider) validateChunkFile(cf *models.ChunkFile) error {
 	return nil
 }
 
-func (h *OperationProvider) readFromCAS(uri string, maxSize uint) ([]byte, error) {
-	bytes, err := h.cas.Read(uri)
+func (h *OperationProvider) readFromCAS(ctx context.Context, fileType, uri string, maxSize uint) ([]byte, error) {
+	// content is decompressed using compression algorithm of the file type so algorithm is part of cache key
+	cacheKey := contentCacheKey{uri: uri, alg: h.compressionAlgorithm(fileType)}
+
+	if content, ok := h.getCachedContent(cacheKey, maxSize); ok {
+		return content, nil
+	}
+
+	if err := h.checkContentSize(uri, maxSize); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	bytes, err := h.readFromCASWithRetry(ctx, uri)
 	if err != nil {
+		if h.isUnresolvable(ctx, err) {
+			err = &unresolvableError{err: err}
+		}
+
 		return nil, errors.Wrapf(err, "retrieve CAS content at uri[%s]", uri)
 	}
 
+	h.metrics.CASReadDuration(fileType, time.Since(start))
+	h.metrics.CASReadBytes(fileType, len(bytes))
+
 	if len(bytes) > int(maxSize) {
 		return nil, fmt.Errorf("uri[%s]: content size %d exceeded maximum size %d", uri, len(bytes), maxSize)
 	}
 
-	content, err := h.dp.Decompress(h.CompressionAlgorithm, bytes)
-	if err != nil {
-		return nil, errors.Wrapf(err, "decompress CAS uri[%s] using '%s'", uri, h.CompressionAlgorithm)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if h.verifyContentHash {
+		if err := verifyContentHash(uri, bytes); err != nil {
+			return nil, err
+		}
 	}
 
 	maxDecompressedSize := maxSize * h.MaxMemoryDecompressionFactor
+
+	content, err := h.decompress(h.compressionAlgorithm(fileType), bytes, int(maxDecompressedSize))
+	if errors.Is(err, errMaxDecompressedSizeExceeded) {
+		return nil, fmt.Errorf("uri[%s]: %s %d", uri, err.Error(), maxDecompressedSize)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "decompress CAS uri[%s] using '%s'", uri, h.compressionAlgorithm(fileType))
+	}
+
 	if len(content) > int(maxDecompressedSize) {
 		return nil, fmt.Errorf("uri[%s]: decompressed content size %d exceeded maximum decompressed content size %d", uri, len(content), maxDecompressedSize)
 	}
 
+	// content is cached only after size checks have passed
+	if h.contentCache != nil {
+		h.contentCache.Add(cacheKey, &casContent{content: content, size: len(bytes)})
+	}
+
 	return content, nil
 }
 
+// compressionAlgorithm returns protocol compression algorithm for the given file type.
+func (h *OperationProvider) compressionAlgorithm(fileType string) string {
+	if fileType == CoreProofFileType || fileType == ProvisionalProofFileType {
+		return h.GetProofFileCompressionAlgorithm()
+	}
+
+	return h.CompressionAlgorithm
+}
+
+var errMaxDecompressedSizeExceeded = errors.New("decompressed content size exceeded maximum decompressed content size")
+
+// decompress decompresses the given content. If decompression provider supports streaming, decompression
+// stops (with errMaxDecompressedSizeExceeded) as soon as decompressed content exceeds the given maximum size
+// so that memory used for decompressed content stays bounded; otherwise the caller has to check the size.
+func (h *OperationProvider) decompress(alg string, data []byte, maxSize int) ([]byte, error) {
+	if alg == protocol.NoCompression {
+		return data, nil
+	}
+
+	sdp, ok := h.dp.(streamDecompressionProvider)
+	if !ok {
+		return h.dp.Decompress(alg, data)
+	}
+
+	w := &limitedBuffer{maxSize: maxSize}
+
+	err := sdp.DecompressStream(alg, bytes.NewReader(data), w)
+	if w.exceeded {
+		return nil, errMaxDecompressedSizeExceeded
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return w.buf.Bytes(), nil
+}
+
+// limitedBuffer is a buffer that fails writes once its content would exceed maximum size.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	maxSize  int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buf.Len()+len(p) > b.maxSize {
+		b.exceeded = true
+
+		return 0, errMaxDecompressedSizeExceeded
+	}
+
+	return b.buf.Write(p)
+}
+
+// checkContentSize rejects content that exceeds maximum size if primary CAS client supports size queries.
+// Size query errors are ignored since content will be read (possibly from fallback CAS) anyway.
+func (h *OperationProvider) checkContentSize(uri string, maxSize uint) error {
+	c, ok := h.cas.(SizeDCAS)
+	if !ok {
+		return nil
+	}
+
+	size, err := c.Size(uri)
+	if err != nil {
+		h.logger.Debugf("failed to get size of CAS content at uri[%s]: %s", uri, err.Error())
+
+		return nil
+	}
+
+	if size > int(maxSize) {
+		return fmt.Errorf("uri[%s]: content size %d exceeded maximum size %d", uri, size, maxSize)
+	}
+
+	return nil
+}
+
+func (h *OperationProvider) readFromCASWithRetry(ctx context.Context, uri string) ([]byte, error) {
+	delay := h.retryPolicy.BaseDelay
+
+	for attempt := 1; ; attempt++ {
+		bytes, err := h.readFromAllCAS(ctx, uri)
+		if err == nil {
+			return bytes, nil
+		}
+
+		if attempt >= h.retryPolicy.MaxAttempts || !isRetryableCASError(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		h.logger.Debugf("failed to read CAS content at uri[%s] on attempt %d; retrying in %s: %s", uri, attempt, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// readFromAllCAS reads content from primary CAS and then from fallback CAS clients (in order)
+// until the read succeeds. Error from the last CAS client is returned if all reads fail.
+func (h *OperationProvider) readFromAllCAS(ctx context.Context, uri string) ([]byte, error) {
+	bytes, err := readFromClient(ctx, h.cas, uri)
+	if err == nil {
+		return bytes, nil
+	}
+
+	for i, client := range h.fallbackCAS {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		h.logger.Debugf("failed to read CAS content at uri[%s]; trying fallback CAS[%d]: %s", uri, i, err)
+
+		bytes, err = readFromClient(ctx, client, uri)
+		if err == nil {
+			return bytes, nil
+		}
+	}
+
+	return nil, err
+}
+
+// readFromClient returns as soon as context is done even if CAS read is still in progress.
+func readFromClient(ctx context.Context, client DCAS, uri string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if c, ok := client.(ContextDCAS); ok {
+		return c.ReadContext(ctx, uri)
+	}
+
+	// context can never be done (e.g. background context)
+	if ctx.Done() == nil {
+		return client.Read(uri)
+	}
+
+	type readResult struct {
+		bytes []byte
+		err   error
+	}
+
+	// buffered so that goroutine can exit if nobody is waiting for the result
+	resultChan := make(chan readResult, 1)
+
+	go func() {
+		bytes, err := client.Read(uri)
+		resultChan <- readResult{bytes: bytes, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		return result.bytes, result.err
+	}
+}
+
+// verifyContentHash verifies that the content address (multihash computed with the same algorithm
+// as the one used for the URI) matches the URI.
+func verifyContentHash(uri string, content []byte) error {
+	code, err := hashing.GetMultihashCode(uri)
+	if err != nil {
+		return fmt.Errorf("uri[%s]: verify content hash: %s", uri, err.Error())
+	}
+
+	mh, err := hashing.ComputeMultihash(uint(code), content)
+	if err != nil {
+		return fmt.Errorf("uri[%s]: verify content hash: %s", uri, err.Error())
+	}
+
+	address := encoder.EncodeToString(mh)
+	if address != uri {
+		return fmt.Errorf("uri[%s]: content hash mismatch: content address is %s", uri, address)
+	}
+
+	return nil
+}
+
+// isUnresolvable returns true if CAS content cannot be retrieved permanently: content was not found
+// or configured retries have been exhausted (and context is not done).
+func (h *OperationProvider) isUnresolvable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	return !isRetryableCASError(err) || h.retryPolicy.MaxAttempts > 1
+}
+
+// unresolvableError marks CAS read error as protocol.ErrUnresolvableTxn without changing error message.
+type unresolvableError struct {
+	err error
+}
+
+func (e *unresolvableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unresolvableError) Unwrap() error {
+	return e.err
+}
+
+func (e *unresolvableError) Is(target error) bool {
+	return target == protocol.ErrUnresolvableTxn
+}
+
+// isRetryableCASError returns false for errors that will not go away on retry.
+func isRetryableCASError(err error) bool {
+	return !errors.Is(err, cas.ErrContentNotFound)
+}
+
+// contentCacheKey identifies decompressed CAS content in content cache.
+type contentCacheKey struct {
+	uri string
+	alg string
+}
+
+// getCachedContent returns cached content for the given key if cached content satisfies maximum size checks.
+func (h *OperationProvider) getCachedContent(key contentCacheKey, maxSize uint) ([]byte, bool) {
+	if h.contentCache == nil {
+		return nil, false
+	}
+
+	value, ok := h.contentCache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	cached, ok := value.(*casContent)
+	if !ok {
+		return nil, false
+	}
+
+	// same content may be requested with different maximum size (e.g. different file type);
+	// in that case read from CAS again so that size errors are reported as usual
+	if cached.size > int(maxSize) || len(cached.content) > int(maxSize*h.MaxMemoryDecompressionFactor) {
+		return nil, false
+	}
+
+	h.logger.Debugf("retrieved content for uri[%s] from cache", key.uri)
+
+	return cached.content, true
+}
+
 // coreOperations contains operations in core index file.
 type coreOperations struct {
 	Create     []*model.Operation
@@ -650,7 +1562,7 @@ func (h *OperationProvider) parseCoreIndexOperations(cif *models.CoreIndexFile,
 		return &coreOperations{}, nil
 	}
 
-	logger.Debugf("parsing core index file operations for anchor string: %s", txn.AnchorString)
+	h.logger.Debugf("parsing core index file operations for anchor string: %s", txn.AnchorString)
 
 	var suffixes []string
 
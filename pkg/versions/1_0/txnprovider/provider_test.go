@@ -7,9 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package txnprovider
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -19,9 +26,11 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
 	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationapplier"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider/models"
 )
@@ -29,13 +38,14 @@ import (
 const (
 	compressionAlgorithm = "GZIP"
 	maxFileSize          = 2000 // in bytes
+	maxOperationCount    = 100
 
 	sampleCasURI = "bafkreih6ot2yfqcerzp5l2qupc77it2vdmepfhszitmswnpdtk34m4ura4"
 	longValue    = "bafkreih6ot2yfqcerzp5l2qupc77it2vdmepfhszitmswnpdtk34m4ura4bafkreih6ot2yfqcerzp5l2qupc77it2vdmepfhszitmswnpdtk34m4ura4"
 )
 
 func TestNewOperationProvider(t *testing.T) {
-	pc := mocks.NewMockProtocolClient()
+	pc := newMockProtocolClient()
 
 	handler := NewOperationProvider(
 		pc.Protocol,
@@ -46,13 +56,237 @@ func TestNewOperationProvider(t *testing.T) {
 	require.NotNil(t, handler)
 }
 
+func TestHandler_GetTxnOperationsContext(t *testing.T) {
+	pc := newMockProtocolClient()
+	parser := operationparser.New(pc.Protocol)
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	cas := mocks.NewMockCasClient(nil)
+	handler := NewOperationHandler(pc.Protocol, cas, cp, parser)
+
+	ops := getTestOperations(2, 2, 1, 1)
+
+	anchorString, _, _, err := handler.PrepareTxnFiles(ops)
+	require.NoError(t, err)
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      anchorString,
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		provider := NewOperationProvider(pc.Protocol, parser, cas, cp)
+
+		txnOps, err := provider.GetTxnOperationsContext(ctx, sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("error - empty anchor string is rejected before CAS is accessed", func(t *testing.T) {
+		countingCAS := &countingCasClient{Client: cas}
+
+		provider := NewOperationProvider(pc.Protocol, parser, countingCAS, cp)
+
+		for _, anchorString := range []string{"", "  ", "."} {
+			txnOps, err := provider.GetTxnOperationsContext(context.Background(), &txn.SidetreeTxn{
+				Namespace:    defaultNS,
+				AnchorString: anchorString,
+			})
+			require.Error(t, err)
+			require.Nil(t, txnOps)
+			require.Contains(t, err.Error(), "anchor string is empty")
+		}
+
+		require.Equal(t, 0, countingCAS.reads())
+	})
+
+	t.Run("error - cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		countingCAS := &countingCasClient{Client: cas}
+
+		provider := NewOperationProvider(pc.Protocol, parser, countingCAS, cp)
+
+		txnOps, err := provider.GetTxnOperationsContext(ctx, sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Equal(t, 0, countingCAS.reads())
+	})
+
+	t.Run("error - context cancelled during slow CAS read", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		provider := NewOperationProvider(pc.Protocol, parser, &delayedCasClient{Client: cas, delay: 5 * time.Second}, cp)
+
+		start := time.Now()
+
+		txnOps, err := provider.GetTxnOperationsContext(ctx, sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("error - context cancelled while waiting for CAS read retry", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		failingCAS := &failingCasClient{Client: cas, failures: 10, err: errors.New("connection reset")}
+
+		provider := NewOperationProvider(pc.Protocol, parser, failingCAS, cp,
+			WithCASReadRetry(RetryPolicy{MaxAttempts: 10, BaseDelay: 5 * time.Second}))
+
+		start := time.Now()
+
+		txnOps, err := provider.GetTxnOperationsContext(ctx, sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+}
+
+func TestHandler_ForEachTxnOperation(t *testing.T) {
+	pc := newMockProtocolClient()
+	parser := operationparser.New(pc.Protocol)
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	cas := mocks.NewMockCasClient(nil)
+	handler := NewOperationHandler(pc.Protocol, cas, cp, parser)
+
+	anchorString, _, _, err := handler.PrepareTxnFiles(getTestOperations(2, 3, 2, 2))
+	require.NoError(t, err)
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      anchorString,
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	provider := NewOperationProvider(pc.Protocol, parser, cas, cp)
+
+	t.Run("success - function is invoked once per operation", func(t *testing.T) {
+		expected, err := provider.GetTxnOperations(sidetreeTxn)
+		require.NoError(t, err)
+
+		var ops []*operation.AnchoredOperation
+
+		err = provider.ForEachTxnOperation(context.Background(), sidetreeTxn, func(op *operation.AnchoredOperation) error {
+			ops = append(ops, op)
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, ops, 9)
+		require.Equal(t, expected, ops)
+	})
+
+	t.Run("error - function error stops iteration", func(t *testing.T) {
+		calls := 0
+
+		err := provider.ForEachTxnOperation(context.Background(), sidetreeTxn, func(op *operation.AnchoredOperation) error {
+			calls++
+
+			if calls == 2 {
+				return errors.New("store error")
+			}
+
+			return nil
+		})
+		require.EqualError(t, err, "store error")
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("error - number of operations doesn't match (function is not invoked)", func(t *testing.T) {
+		ad, err := ParseAnchorData(anchorString)
+		require.NoError(t, err)
+
+		calls := 0
+
+		err = provider.ForEachTxnOperation(context.Background(), &txn.SidetreeTxn{
+			Namespace:    defaultNS,
+			AnchorString: "7" + delimiter + ad.CoreIndexFileURI,
+		}, func(op *operation.AnchoredOperation) error {
+			calls++
+
+			return nil
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "number of txn ops[9] doesn't match anchor string num of ops[7]")
+		require.Equal(t, 0, calls)
+	})
+}
+
+func TestHandler_ContentSize(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+	p.MaxCoreIndexFileSize = 10
+
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	casClient := mocks.NewMockCasClient(nil)
+
+	address, err := casClient.Write([]byte("content that exceeds maximum core index file size"))
+	require.NoError(t, err)
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      "1" + delimiter + address,
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	t.Run("error - oversized content is rejected without reading it", func(t *testing.T) {
+		cas := &sizeCasClient{countingCasClient: countingCasClient{Client: casClient}}
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "content size 49 exceeded maximum size 10")
+		require.Equal(t, 0, cas.reads())
+	})
+
+	t.Run("error - size error falls back to reading content", func(t *testing.T) {
+		cas := &sizeCasClient{countingCasClient: countingCasClient{Client: casClient}, sizeErr: errors.New("size error")}
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "content size 49 exceeded maximum size 10")
+		require.Equal(t, 1, cas.reads())
+	})
+
+	t.Run("error - size is not supported", func(t *testing.T) {
+		cas := &countingCasClient{Client: casClient}
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "content size 49 exceeded maximum size 10")
+		require.Equal(t, 1, cas.reads())
+	})
+}
+
 func TestHandler_GetTxnOperations(t *testing.T) {
 	const createOpsNum = 2
 	const updateOpsNum = 3
 	const deactivateOpsNum = 2
 	const recoverOpsNum = 2
 
-	pc := mocks.NewMockProtocolClient()
+	pc := newMockProtocolClient()
 	parser := operationparser.New(pc.Protocol)
 	cp := compression.New(compression.WithDefaultAlgorithms())
 
@@ -92,7 +326,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		require.NotEmpty(t, anchorString)
 		require.Equal(t, len(refs), createOpsNum+updateOpsNum+deactivateOpsNum+recoverOpsNum)
 
-		smallDeltaProofSize := mocks.GetDefaultProtocolParameters()
+		smallDeltaProofSize := newMockProtocolClient().Protocol
 		smallDeltaProofSize.MaxDeltaSize = 50
 
 		provider := NewOperationProvider(smallDeltaProofSize, operationparser.New(smallDeltaProofSize), cas, cp)
@@ -126,7 +360,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		ad.NumberOfOperations = 7
 		anchorString = ad.GetAnchorString()
 
-		provider := NewOperationProvider(mocks.NewMockProtocolClient().Protocol, operationparser.New(pc.Protocol), cas, cp)
+		provider := NewOperationProvider(newMockProtocolClient().Protocol, operationparser.New(pc.Protocol), cas, cp)
 
 		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
 			Namespace:         defaultNS,
@@ -137,11 +371,52 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 
 		require.Error(t, err)
 		require.Nil(t, txnOps)
-		require.Contains(t, err.Error(), "number of txn ops[9] doesn't match anchor string num of ops[7]")
+		require.Contains(t, err.Error(),
+			"number of txn ops[9] doesn't match anchor string num of ops[7]: core[6] provisional[3]")
+	})
+
+	t.Run("error - number of operations exceeds maximum operation count", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+		p.MaxOperationCount = 5
+
+		cas := &countingCasClient{Client: mocks.NewMockCasClient(nil)}
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      "6" + delimiter + "coreIndexURI",
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		})
+
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "number of operations[6] exceeds maximum number of operations[5]")
+		require.Equal(t, 0, cas.reads())
+	})
+
+	t.Run("error - number of operations at maximum operation count proceeds to CAS read", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+		p.MaxOperationCount = 5
+
+		cas := &countingCasClient{Client: mocks.NewMockCasClient(nil)}
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      "5" + delimiter + "coreIndexURI",
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		})
+
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "error reading core index file")
+		require.Equal(t, 1, cas.reads())
 	})
 
 	t.Run("error - read from CAS error", func(t *testing.T) {
-		protocolClient := mocks.NewMockProtocolClient()
+		protocolClient := newMockProtocolClient()
 		handler := NewOperationProvider(protocolClient.Protocol, operationparser.New(protocolClient.Protocol), mocks.NewMockCasClient(errors.New("CAS error")), cp)
 
 		txnOps, err := handler.GetTxnOperations(&txn.SidetreeTxn{
@@ -166,7 +441,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, anchorString)
 
-		invalid := mocks.NewMockProtocolClient().Protocol
+		invalid := newMockProtocolClient().Protocol
 		invalid.MultihashAlgorithms = []uint{55}
 
 		provider := NewOperationProvider(invalid, operationparser.New(invalid), cas, cp)
@@ -184,7 +459,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 	})
 
 	t.Run("error - parse anchor data error", func(t *testing.T) {
-		p := mocks.NewMockProtocolClient().Protocol
+		p := newMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(nil), cp)
 
 		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
@@ -215,7 +490,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		// core proof, core index
 		require.Len(t, artifacts, 2)
 
-		p := mocks.NewMockProtocolClient().Protocol
+		p := newMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
 		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
@@ -246,7 +521,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		// chunk, provisional proof and provisional index, core index
 		require.Len(t, artifacts, 4)
 
-		p := mocks.NewMockProtocolClient().Protocol
+		p := newMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
 		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
@@ -277,7 +552,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		// chunk, provisional index, and core index
 		require.Len(t, artifacts, 3)
 
-		p := mocks.NewMockProtocolClient().Protocol
+		p := newMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
 		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
@@ -308,7 +583,7 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 		// chunk, provisional index, core proof, core index
 		require.Len(t, artifacts, 4)
 
-		p := mocks.NewMockProtocolClient().Protocol
+		p := newMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
 		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{
@@ -323,6 +598,97 @@ func TestHandler_GetTxnOperations(t *testing.T) {
 	})
 }
 
+func TestHandler_ManifestAnchorString(t *testing.T) {
+	pc := newMockProtocolClient()
+	parser := operationparser.New(pc.Protocol)
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	cas := mocks.NewMockCasClient(nil)
+	handler := NewOperationHandler(pc.Protocol, cas, cp, parser)
+
+	ops := getTestOperations(2, 2, 1, 1)
+
+	anchorString, _, _, err := handler.PrepareTxnFiles(ops)
+	require.NoError(t, err)
+
+	anchorData, err := ParseAnchorData(anchorString)
+	require.NoError(t, err)
+
+	writeManifest := func(t *testing.T, content string) string {
+		t.Helper()
+
+		compressed, err := cp.Compress(compressionAlgorithm, []byte(content))
+		require.NoError(t, err)
+
+		address, err := cas.Write(compressed)
+		require.NoError(t, err)
+
+		return address
+	}
+
+	newTxn := func(ad *AnchorData) *txn.SidetreeTxn {
+		return &txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      ad.GetAnchorString(),
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		}
+	}
+
+	provider := NewOperationProvider(pc.Protocol, parser, cas, cp)
+
+	t.Run("success - legacy format", func(t *testing.T) {
+		txnOps, err := provider.GetTxnOperations(newTxn(anchorData))
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("success - core index file URI is resolved from manifest", func(t *testing.T) {
+		manifestURI := writeManifest(t, fmt.Sprintf(`{"coreIndexFileUri":"%s"}`, anchorData.CoreIndexFileURI))
+
+		ad, err := NewManifestAnchorData(anchorData.NumberOfOperations, manifestURI)
+		require.NoError(t, err)
+
+		txnOps, err := provider.GetTxnOperations(newTxn(ad))
+		require.NoError(t, err)
+		require.Len(t, txnOps, len(ops))
+	})
+
+	t.Run("error - manifest not found", func(t *testing.T) {
+		txnOps, err := provider.GetTxnOperations(newTxn(&AnchorData{NumberOfOperations: 1, ManifestURI: "manifestURI"}))
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "error reading manifest file")
+	})
+
+	t.Run("error - invalid manifest", func(t *testing.T) {
+		manifestURI := writeManifest(t, "invalid")
+
+		txnOps, err := provider.GetTxnOperations(newTxn(&AnchorData{NumberOfOperations: 1, ManifestURI: manifestURI}))
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "failed to parse content for manifest file")
+	})
+
+	t.Run("error - manifest is missing core index file URI", func(t *testing.T) {
+		manifestURI := writeManifest(t, "{}")
+
+		txnOps, err := provider.GetTxnOperations(newTxn(&AnchorData{NumberOfOperations: 1, ManifestURI: manifestURI}))
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "missing core index file URI")
+	})
+
+	t.Run("error - manifest core index file URI exceeds maximum length", func(t *testing.T) {
+		manifestURI := writeManifest(t, fmt.Sprintf(`{"coreIndexFileUri":"%s"}`, strings.Repeat("a", 1000)))
+
+		txnOps, err := provider.GetTxnOperations(newTxn(&AnchorData{NumberOfOperations: 1, ManifestURI: manifestURI}))
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "exceeds maximum CAS URI length")
+	})
+}
+
 func TestHandler_GetCoreIndexFile(t *testing.T) {
 	cp := compression.New(compression.WithDefaultAlgorithms())
 	p := protocol.Protocol{
@@ -344,7 +710,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, parser, cas, cp)
 
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -352,7 +718,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 	t.Run("error - core index file exceeds maximum size", func(t *testing.T) {
 		provider := NewOperationProvider(protocol.Protocol{MaxCoreIndexFileSize: 15, CompressionAlgorithm: compressionAlgorithm}, parser, cas, cp)
 
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 15")
@@ -365,7 +731,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, parser, cas, cp)
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for core index file")
@@ -390,7 +756,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, parser, cas, cp)
-		file, err := provider.getCoreIndexFile(address)
+		file, err := provider.getCoreIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to validate suffix data for create[0]")
@@ -411,7 +777,7 @@ func TestHandler_GetCoreIndexFile(t *testing.T) {
 }
 
 func TestHandler_ValidateCoreIndexFile(t *testing.T) {
-	p := mocks.NewMockProtocolClient().Protocol
+	p := newMockProtocolClient().Protocol
 
 	t.Run("success", func(t *testing.T) {
 		batchFiles, err := generateDefaultBatchFiles()
@@ -449,6 +815,47 @@ func TestHandler_ValidateCoreIndexFile(t *testing.T) {
 		require.Contains(t, err.Error(), "core proof file URI should be empty if there are no recover and/or deactivate operations")
 	})
 
+	t.Run("success - deactivate only batch without provisional index URI", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreIndex.ProvisionalIndexFileURI = ""
+		batchFiles.CoreIndex.Operations.Create = nil
+		batchFiles.CoreIndex.Operations.Recover = nil
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		require.NoError(t, err)
+	})
+
+	t.Run("error - missing provisional index URI for create operations", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreIndex.ProvisionalIndexFileURI = ""
+		batchFiles.CoreIndex.Operations.Recover = nil
+		batchFiles.CoreIndex.Operations.Deactivate = nil
+		batchFiles.CoreIndex.CoreProofFileURI = ""
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing provisional index file for create/recover operations")
+	})
+
+	t.Run("error - missing provisional index URI for recover operations", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreIndex.ProvisionalIndexFileURI = ""
+		batchFiles.CoreIndex.Operations.Create = nil
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing provisional index file for create/recover operations")
+	})
+
 	t.Run("error - invalid suffix data for create", func(t *testing.T) {
 		batchFiles, err := generateDefaultBatchFiles()
 		require.NoError(t, err)
@@ -515,7 +922,7 @@ func TestHandler_ValidateCoreIndexFile(t *testing.T) {
 	})
 
 	t.Run("error - recovery commitment length exceeds max hash length", func(t *testing.T) {
-		lowMaxHashLength := mocks.GetDefaultProtocolParameters()
+		lowMaxHashLength := newMockProtocolClient().Protocol
 		lowMaxHashLength.MaxOperationHashLength = 10
 
 		batchFiles, err := generateDefaultBatchFiles()
@@ -545,7 +952,7 @@ func TestHandler_GetProvisionalIndexFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getProvisionalIndexFile(address)
+		file, err := provider.getProvisionalIndexFile(context.Background(), address)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -555,7 +962,7 @@ func TestHandler_GetProvisionalIndexFile(t *testing.T) {
 		parser := operationparser.New(lowMaxFileSize)
 		provider := NewOperationProvider(lowMaxFileSize, parser, cas, cp)
 
-		file, err := provider.getProvisionalIndexFile(address)
+		file, err := provider.getProvisionalIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 5")
@@ -569,7 +976,7 @@ func TestHandler_GetProvisionalIndexFile(t *testing.T) {
 
 		parser := operationparser.New(p)
 		provider := NewOperationProvider(p, parser, cas, cp)
-		file, err := provider.getProvisionalIndexFile(address)
+		file, err := provider.getProvisionalIndexFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for provisional index file")
@@ -577,7 +984,7 @@ func TestHandler_GetProvisionalIndexFile(t *testing.T) {
 }
 
 func TestHandler_ValidateProvisionalIndexFile(t *testing.T) {
-	p := mocks.NewMockProtocolClient().Protocol
+	p := newMockProtocolClient().Protocol
 
 	t.Run("success", func(t *testing.T) {
 		batchFiles, err := generateDefaultBatchFiles()
@@ -694,7 +1101,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
@@ -703,7 +1110,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 		lowMaxFileSize := protocol.Protocol{MaxChunkFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
 		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
 
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -715,7 +1122,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to parse content for chunk file")
@@ -737,7 +1144,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 		address, err := cas.Write(content)
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getChunkFile(address)
+		file, err := provider.getChunkFile(context.Background(), address)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to validate delta[0]")
@@ -745,7 +1152,7 @@ func TestHandler_GetChunkFile(t *testing.T) {
 }
 
 func TestHandler_ValidateChunkFile(t *testing.T) {
-	p := mocks.NewMockProtocolClient().Protocol
+	p := newMockProtocolClient().Protocol
 
 	t.Run("success", func(t *testing.T) {
 		batchFiles, err := generateDefaultBatchFiles()
@@ -770,227 +1177,262 @@ func TestHandler_ValidateChunkFile(t *testing.T) {
 	})
 }
 
-func TestHandler_readFromCAS(t *testing.T) {
+func TestHandler_CollectCASURIs(t *testing.T) {
+	pc := newMockProtocolClient()
+	parser := operationparser.New(pc.Protocol)
 	cp := compression.New(compression.WithDefaultAlgorithms())
-	p := protocol.Protocol{
-		MaxChunkFileSize:             maxFileSize,
-		CompressionAlgorithm:         compressionAlgorithm,
-		MaxMemoryDecompressionFactor: 3,
+
+	newTxn := func(anchorString string) *txn.SidetreeTxn {
+		return &txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      anchorString,
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		}
 	}
 
-	cas := mocks.NewMockCasClient(nil)
-	content, err := cp.Compress(compressionAlgorithm, []byte("{}"))
-	require.NoError(t, err)
-	address, err := cas.Write(content)
-	require.NoError(t, err)
+	tests := []struct {
+		name string
+		ops  []*operation.QueuedOperation
+		// number of batch files
+		files int
+	}{
+		{name: "all operation types", ops: getTestOperations(2, 2, 1, 1), files: 5},
+		{name: "create operations only", ops: getTestOperations(3, 0, 0, 0), files: 3},
+		{name: "update operations only", ops: getTestOperations(0, 2, 0, 0), files: 4},
+		{name: "deactivate operations only", ops: getTestOperations(0, 0, 2, 0), files: 2},
+	}
 
-	t.Run("success", func(t *testing.T) {
-		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+	for _, tc := range tests {
+		tc := tc
 
-		file, err := provider.readFromCAS(address, maxFileSize)
-		require.NoError(t, err)
-		require.NotNil(t, file)
-	})
+		t.Run("success - "+tc.name, func(t *testing.T) {
+			cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
 
-	t.Run("error - read from CAS error", func(t *testing.T) {
-		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(errors.New("CAS error")), cp)
+			anchorString, artifacts, _, err := NewOperationHandler(pc.Protocol, cas, cp, parser).PrepareTxnFiles(tc.ops)
+			require.NoError(t, err)
 
-		file, err := provider.getChunkFile("address")
-		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), " retrieve CAS content at uri[address]: CAS error")
-	})
+			uris, err := NewOperationProvider(pc.Protocol, parser, cas, cp).CollectCASURIs(newTxn(anchorString))
+			require.NoError(t, err)
+			require.Len(t, uris, tc.files)
+			require.ElementsMatch(t, cas.writtenURIs(), uris)
 
-	t.Run("error - content exceeds maximum size", func(t *testing.T) {
-		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+			var artifactURIs []string
+			for _, artifact := range artifacts {
+				artifactURIs = append(artifactURIs, artifact.ID)
+			}
 
-		file, err := provider.readFromCAS(address, 20)
-		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "exceeded maximum size 20")
-	})
+			require.ElementsMatch(t, artifactURIs, uris)
+		})
+	}
 
-	t.Run("error - content exceeds maximum decompressed size", func(t *testing.T) {
-		p2 := protocol.Protocol{
-			CompressionAlgorithm:         compressionAlgorithm,
-			MaxMemoryDecompressionFactor: 1,
-		}
+	t.Run("success - multiple chunk files", func(t *testing.T) {
+		ops := getTestOperations(10, 0, 0, 0)
 
-		provider := NewOperationProvider(p2, operationparser.New(p2), cas, cp)
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
 
-		testContent, err := cp.Compress(compressionAlgorithm, []byte(sampleChunkFile))
-		require.NoError(t, err)
-		testAddress, err := cas.Write(testContent)
+		// determine chunk file size for the batch
+		handler := NewOperationHandler(pc.Protocol, cas, cp, parser)
+
+		parsedOps, _, err := handler.parseOperations(ops)
 		require.NoError(t, err)
 
-		file, err := provider.readFromCAS(testAddress, 247)
-		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "decompressed content size 267 exceeded maximum decompressed content size 247")
-	})
+		chunkFiles, err := handler.compressChunkFiles(models.CreateChunkFile(parsedOps).Deltas)
+		require.NoError(t, err)
+		require.Len(t, chunkFiles, 1)
 
-	t.Run("error - decompression error", func(t *testing.T) {
-		p2 := protocol.Protocol{
-			MaxChunkFileSize:             maxFileSize,
-			CompressionAlgorithm:         "alg",
-			MaxMemoryDecompressionFactor: 3,
-		}
+		p := pc.Protocol
+		p.MaxChunkFileSize = uint(len(chunkFiles[0]) - 1)
 
-		provider := NewOperationProvider(p2, operationparser.New(p2), cas, cp)
+		anchorString, _, _, err := NewOperationHandler(p, cas, cp, operationparser.New(p)).PrepareTxnFiles(ops)
+		require.NoError(t, err)
 
-		file, err := provider.readFromCAS(address, maxFileSize)
-		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "compression algorithm 'alg' not supported")
+		uris, err := NewOperationProvider(p, operationparser.New(p), cas, cp).CollectCASURIs(newTxn(anchorString))
+		require.NoError(t, err)
+		require.Greater(t, len(uris), 3)
+		require.ElementsMatch(t, cas.writtenURIs(), uris)
 	})
-}
 
-func TestHandler_GetCorePoofFile(t *testing.T) {
-	cp := compression.New(compression.WithDefaultAlgorithms())
-	p := protocol.Protocol{
-		MaxProofFileSize:             maxFileSize,
-		CompressionAlgorithm:         compressionAlgorithm,
-		MaxMemoryDecompressionFactor: 3,
-	}
+	t.Run("success - manifest URI is collected", func(t *testing.T) {
+		cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
 
-	cas := mocks.NewMockCasClient(nil)
-	content, err := cp.Compress(compressionAlgorithm, []byte("{}"))
-	require.NoError(t, err)
-	uri, err := cas.Write(content)
-	require.NoError(t, err)
+		anchorString, _, _, err := NewOperationHandler(pc.Protocol, cas, cp, parser).PrepareTxnFiles(getTestOperations(1, 0, 0, 0))
+		require.NoError(t, err)
 
-	t.Run("success", func(t *testing.T) {
-		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+		ad, err := ParseAnchorData(anchorString)
+		require.NoError(t, err)
 
-		file, err := provider.getCoreProofFile(uri)
+		manifest, err := cp.Compress(compressionAlgorithm, []byte(fmt.Sprintf(`{"coreIndexFileUri":"%s"}`, ad.CoreIndexFileURI)))
 		require.NoError(t, err)
-		require.NotNil(t, file)
-	})
 
-	t.Run("error - core proof file exceeds maximum size", func(t *testing.T) {
-		lowMaxFileSize := protocol.Protocol{MaxProofFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
-		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
+		manifestURI, err := cas.Write(manifest)
+		require.NoError(t, err)
 
-		file, err := provider.getCoreProofFile(uri)
-		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "exceeded maximum size 10")
-	})
+		manifestAnchorData, err := NewManifestAnchorData(ad.NumberOfOperations, manifestURI)
+		require.NoError(t, err)
 
-	t.Run("error - parse core proof file error (invalid JSON)", func(t *testing.T) {
-		content, err := cp.Compress(compressionAlgorithm, []byte("invalid"))
+		uris, err := NewOperationProvider(pc.Protocol, parser, cas, cp).CollectCASURIs(newTxn(manifestAnchorData.GetAnchorString()))
 		require.NoError(t, err)
-		address, err := cas.Write(content)
+		require.Equal(t, manifestURI, uris[0])
+		require.ElementsMatch(t, cas.writtenURIs(), uris)
+	})
 
-		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getCoreProofFile(address)
+	t.Run("error - invalid anchor string", func(t *testing.T) {
+		uris, err := NewOperationProvider(pc.Protocol, parser, mocks.NewMockCasClient(nil), cp).CollectCASURIs(newTxn("invalid"))
 		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "failed to parse content for core proof file")
+		require.Nil(t, uris)
+		require.Contains(t, err.Error(), "expecting [2] parts")
 	})
 
-	t.Run("error - validate core proof file (invalid signed data)", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+	t.Run("error - missing core index file", func(t *testing.T) {
+		uris, err := NewOperationProvider(pc.Protocol, parser, mocks.NewMockCasClient(nil), cp).CollectCASURIs(newTxn("1.coreIndexURI"))
+		require.Error(t, err)
+		require.Nil(t, uris)
+		require.Contains(t, err.Error(), "error reading core index file")
+	})
 
-		// invalidate signed data for first recover
-		batchFiles.CoreProof.Operations.Recover[0] = "invalid-jws"
+	t.Run("error - missing provisional index file", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
 
-		invalid, err := json.Marshal(batchFiles.CoreProof)
+		anchorString, _, _, err := NewOperationHandler(pc.Protocol, cas, cp, parser).PrepareTxnFiles(getTestOperations(1, 0, 0, 0))
 		require.NoError(t, err)
 
-		cas := mocks.NewMockCasClient(nil)
-		content, err := cp.Compress(compressionAlgorithm, invalid)
+		ad, err := ParseAnchorData(anchorString)
 		require.NoError(t, err)
-		address, err := cas.Write(content)
 
-		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getCoreProofFile(address)
+		cif := &models.CoreIndexFile{}
+		readCompressedModel(t, cp, cas, ad.CoreIndexFileURI, cif)
+
+		cas.SetReadError(cif.ProvisionalIndexFileURI, errors.New("provisional index error"))
+
+		uris, err := NewOperationProvider(pc.Protocol, parser, cas, cp).CollectCASURIs(newTxn(anchorString))
 		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "failed to validate signed data for recover[0]")
+		require.Nil(t, uris)
+		require.Contains(t, err.Error(), "provisional index error")
 	})
 }
 
-func TestHandler_ValidateCorePoofFile(t *testing.T) {
-	p := mocks.NewMockProtocolClient().Protocol
+func TestHandler_CheckCAS(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+	cp := compression.New(compression.WithDefaultAlgorithms())
 
-	t.Run("success", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+	t.Run("success - healthy CAS", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(nil), cp)
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateCoreProofFile(batchFiles.CoreProof)
-		require.NoError(t, err)
+		require.NoError(t, provider.CheckCAS(context.Background()))
 	})
 
-	t.Run("error - invalid signed data for recover", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+	t.Run("success - CAS client implements ping", func(t *testing.T) {
+		pingCAS := &pingCasClient{Client: mocks.NewMockCasClient(nil)}
 
-		// invalidate signed data for first recover
-		batchFiles.CoreProof.Operations.Recover[0] = "recover-jws"
+		provider := NewOperationProvider(p, operationparser.New(p), pingCAS, cp)
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateCoreProofFile(batchFiles.CoreProof)
+		require.NoError(t, provider.CheckCAS(context.Background()))
+		require.Equal(t, 1, pingCAS.pings)
+	})
+
+	t.Run("error - CAS error", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(errors.New("CAS error")), cp)
+
+		err := provider.CheckCAS(context.Background())
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "failed to validate signed data for recover[0]")
+		require.Contains(t, err.Error(), "CAS error")
 	})
 
-	t.Run("error - invalid signed data for deactivate", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+	t.Run("error - ping error", func(t *testing.T) {
+		pingCAS := &pingCasClient{Client: mocks.NewMockCasClient(nil), err: errors.New("ping error")}
 
-		// invalidate signed data for first deactivate
-		batchFiles.CoreProof.Operations.Deactivate[0] = "deactivate-jws"
+		provider := NewOperationProvider(p, operationparser.New(p), pingCAS, cp)
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateCoreProofFile(batchFiles.CoreProof)
+		require.EqualError(t, provider.CheckCAS(context.Background()), "ping error")
+	})
+
+	t.Run("error - read-only CAS client", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), &readOnlyCasClient{}, cp)
+
+		err := provider.CheckCAS(context.Background())
+		require.EqualError(t, err, "CAS client doesn't support health check")
+	})
+}
+
+func TestHandler_UnresolvableTxn(t *testing.T) {
+	pc := newMockProtocolClient()
+	parser := operationparser.New(pc.Protocol)
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	cas := mocks.NewMockCasClient(nil)
+	handler := NewOperationHandler(pc.Protocol, cas, cp, parser)
+
+	anchorString, _, _, err := handler.PrepareTxnFiles(getTestOperations(1, 1, 1, 1))
+	require.NoError(t, err)
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      anchorString,
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	t.Run("error - missing core index file is unresolvable", func(t *testing.T) {
+		provider := NewOperationProvider(pc.Protocol, parser, cas, cp)
+
+		txnOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: "1.coreIndexURI"})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "failed to validate signed data for deactivate[0]")
+		require.Nil(t, txnOps)
+		require.True(t, errors.Is(err, protocol.ErrUnresolvableTxn))
+		require.Contains(t, err.Error(), "error reading core index file: retrieve CAS content at uri[coreIndexURI]: content not found")
 	})
 
-	t.Run("success - validate IPFS CID", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+	t.Run("error - transient CAS error is not unresolvable without retries", func(t *testing.T) {
+		failingCAS := &failingCasClient{Client: cas, failures: 1, err: errors.New("connection reset")}
 
-		// set valid IPFS CID
-		batchFiles.CoreIndex.CoreProofFileURI = sampleCasURI
+		provider := NewOperationProvider(pc.Protocol, parser, failingCAS, cp)
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
-		require.NoError(t, err)
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.False(t, errors.Is(err, protocol.ErrUnresolvableTxn))
+		require.Contains(t, err.Error(), "connection reset")
 	})
 
-	t.Run("error - core proof URI too long", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+	t.Run("error - transient CAS error is unresolvable after retries are exhausted", func(t *testing.T) {
+		failingCAS := &failingCasClient{Client: cas, failures: 2, err: errors.New("connection reset")}
 
-		batchFiles.CoreIndex.CoreProofFileURI = longValue
+		provider := NewOperationProvider(pc.Protocol, parser, failingCAS, cp,
+			WithCASReadRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "core proof URI: CAS URI length[118] exceeds maximum CAS URI length[100]")
+		require.Nil(t, txnOps)
+		require.True(t, errors.Is(err, protocol.ErrUnresolvableTxn))
+		require.Equal(t, 2, failingCAS.reads)
 	})
 
-	t.Run("error - provisional index URI too long", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+	t.Run("error - cancelled context is not unresolvable", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
 
-		batchFiles.CoreIndex.ProvisionalIndexFileURI = longValue
+		provider := NewOperationProvider(pc.Protocol, parser, cas, cp)
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		txnOps, err := provider.GetTxnOperationsContext(ctx, sidetreeTxn)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "provisional index URI: CAS URI length[118] exceeds maximum CAS URI length[100]")
+		require.Nil(t, txnOps)
+		require.False(t, errors.Is(err, protocol.ErrUnresolvableTxn))
 	})
 }
 
-func TestHandler_GetProvisionalPoofFile(t *testing.T) {
+func TestIsRetryableCASError(t *testing.T) {
+	require.False(t, isRetryableCASError(cas.ErrContentNotFound))
+	require.False(t, isRetryableCASError(fmt.Errorf("%w: address[address]", cas.ErrContentNotFound)))
+	require.False(t, isRetryableCASError(fmt.Errorf("read content: %w", fmt.Errorf("%w: address[address]", cas.ErrContentNotFound))))
+	require.True(t, isRetryableCASError(errors.New("connection reset")))
+	require.True(t, isRetryableCASError(errors.New("host not found")))
+}
+
+func TestHandler_readFromCAS(t *testing.T) {
 	cp := compression.New(compression.WithDefaultAlgorithms())
 	p := protocol.Protocol{
-		MaxProofFileSize:             maxFileSize,
+		MaxChunkFileSize:             maxFileSize,
 		CompressionAlgorithm:         compressionAlgorithm,
 		MaxMemoryDecompressionFactor: 3,
 	}
@@ -998,114 +1440,617 @@ func TestHandler_GetProvisionalPoofFile(t *testing.T) {
 	cas := mocks.NewMockCasClient(nil)
 	content, err := cp.Compress(compressionAlgorithm, []byte("{}"))
 	require.NoError(t, err)
-	uri, err := cas.Write(content)
+	address, err := cas.Write(content)
 	require.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getProvisionalProofFile(uri)
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
 
-	t.Run("error - core provisional file exceeds maximum size", func(t *testing.T) {
-		lowMaxFileSize := protocol.Protocol{MaxProofFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
-		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
+	t.Run("success - custom compression algorithm", func(t *testing.T) {
+		const customAlg = "CUSTOM"
 
-		file, err := provider.getProvisionalProofFile(uri)
-		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "exceeded maximum size 10")
-	})
+		customCP := compression.New(compression.WithDefaultAlgorithms(), compression.WithCompressor(customAlg, &noopCompressor{}))
 
-	t.Run("error - parse provisional proof file error (invalid JSON)", func(t *testing.T) {
-		content, err := cp.Compress(compressionAlgorithm, []byte("invalid"))
+		p2 := p
+		p2.CompressionAlgorithm = customAlg
+
+		customContent, err := customCP.Compress(customAlg, []byte(sampleChunkFile))
+		require.NoError(t, err)
+		customAddress, err := cas.Write(customContent)
 		require.NoError(t, err)
-		address, err := cas.Write(content)
 
-		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getProvisionalProofFile(address)
-		require.Error(t, err)
-		require.Nil(t, file)
-		require.Contains(t, err.Error(), "failed to parse content for provisional proof file")
-	})
+		provider := NewOperationProvider(p2, operationparser.New(p2), cas, customCP)
 
-	t.Run("error - validate provisional proof file (invalid signed data)", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, customAddress, maxFileSize)
 		require.NoError(t, err)
+		require.Equal(t, sampleChunkFile, string(file))
+	})
 
-		// invalidate signed data for first update
-		batchFiles.ProvisionalProof.Operations.Update[0] = "invalid-jws"
+	t.Run("success - context-aware CAS", func(t *testing.T) {
+		contextCAS := &contextCasClient{Client: cas}
 
-		invalid, err := json.Marshal(batchFiles.ProvisionalProof)
-		require.NoError(t, err)
+		provider := NewOperationProvider(p, operationparser.New(p), contextCAS, cp)
 
-		cas := mocks.NewMockCasClient(nil)
-		content, err := cp.Compress(compressionAlgorithm, invalid)
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
 		require.NoError(t, err)
-		address, err := cas.Write(content)
+		require.Equal(t, "{}", string(file))
+		require.Equal(t, 1, contextCAS.callCount())
+	})
 
-		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
-		file, err := provider.getProvisionalProofFile(address)
+	t.Run("error - context-aware CAS read is cancelled", func(t *testing.T) {
+		contextCAS := &contextCasClient{Client: cas, blocking: true}
+		fallbackCAS := &countingCasClient{Client: cas}
+
+		provider := NewOperationProvider(p, operationparser.New(p), contextCAS, cp, WithFallbackCAS(fallbackCAS))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		file, err := provider.readFromCAS(ctx, ChunkFileType, address, maxFileSize)
 		require.Error(t, err)
 		require.Nil(t, file)
-		require.Contains(t, err.Error(), "failed to validate signed data for update[0]")
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Equal(t, 1, contextCAS.callCount())
+		require.Equal(t, 0, fallbackCAS.reads())
 	})
-}
 
-func TestHandler_ValidateProvisionalPoofFile(t *testing.T) {
-	p := mocks.NewMockProtocolClient().Protocol
+	t.Run("success - content read from fallback CAS", func(t *testing.T) {
+		primary := &failingCasClient{Client: cas, failures: 1, err: errors.New("primary error")}
+		secondary := &countingCasClient{Client: cas}
 
-	t.Run("success", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
+		provider := NewOperationProvider(p, operationparser.New(p), primary, cp, WithFallbackCAS(secondary))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
 		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+		require.Equal(t, 1, primary.reads)
+		require.Equal(t, 1, secondary.reads())
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateProvisionalProofFile(batchFiles.ProvisionalProof)
+		// primary CAS is tried first
+		file, err = provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
 		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+		require.Equal(t, 2, primary.reads)
+		require.Equal(t, 1, secondary.reads())
 	})
 
-	t.Run("error - invalid signed data for update", func(t *testing.T) {
-		batchFiles, err := generateDefaultBatchFiles()
+	t.Run("success - content not found in primary CAS", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(nil), cp, WithFallbackCAS(cas))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
 		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+	})
 
-		// invalidate signed data for first update
-		batchFiles.ProvisionalProof.Operations.Update[0] = "jws"
+	t.Run("error - all CAS clients fail", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(nil), cp,
+			WithFallbackCAS(
+				&failingCasClient{Client: cas, failures: 1, err: errors.New("secondary error")},
+				&failingCasClient{Client: cas, failures: 1, err: errors.New("tertiary error")},
+			))
 
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
-		err = provider.validateProvisionalProofFile(batchFiles.ProvisionalProof)
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "failed to validate signed data for update[0]")
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "tertiary error")
 	})
-}
 
-func TestHandler_GetBatchFiles(t *testing.T) {
-	cp := compression.New(compression.WithDefaultAlgorithms())
-	cas := mocks.NewMockCasClient(nil)
+	t.Run("error - content from fallback CAS exceeds maximum size", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(nil), cp, WithFallbackCAS(cas))
 
-	updateOp, err := generateOperation(1, operation.TypeUpdate)
-	require.NoError(t, err)
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, 10)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "exceeded maximum size 10")
+	})
 
-	recoverOp, err := generateOperation(2, operation.TypeRecover)
-	require.NoError(t, err)
+	t.Run("success - content cache", func(t *testing.T) {
+		countingCAS := &countingCasClient{Client: cas}
 
-	ppf := &models.ProvisionalProofFile{
-		Operations: models.ProvisionalProofOperations{
-			Update: []string{updateOp.SignedData},
-		},
-	}
+		provider := NewOperationProvider(p, operationparser.New(p), countingCAS, cp, WithContentCache(10))
 
-	ppfURI, err := writeToCAS(ppf, cas)
-	require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+			require.NoError(t, err)
+			require.Equal(t, "{}", string(file))
+		}
 
-	cf := &models.ChunkFile{Deltas: []*model.DeltaModel{recoverOp.Delta, updateOp.Delta}}
+		require.Equal(t, 1, countingCAS.reads())
+	})
 
-	chunkURI, err := writeToCAS(cf, cas)
-	require.NoError(t, err)
+	t.Run("success - content cache is keyed by compression algorithm", func(t *testing.T) {
+		countingCAS := &countingCasClient{Client: cas}
 
-	pif := &models.ProvisionalIndexFile{
-		Chunks:                  []models.Chunk{{ChunkFileURI: chunkURI}},
+		pp := p
+		pp.ProofFileCompressionAlgorithm = protocol.NoCompression
+
+		provider := NewOperationProvider(pp, operationparser.New(pp), countingCAS, cp, WithContentCache(10))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+
+		// proof files are not compressed so the same URI read as proof file returns content as is
+		file, err = provider.readFromCAS(context.Background(), CoreProofFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, content, file)
+
+		file, err = provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+
+		file, err = provider.readFromCAS(context.Background(), CoreProofFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, content, file)
+
+		require.Equal(t, 2, countingCAS.reads())
+	})
+
+	t.Run("success - content cache disabled", func(t *testing.T) {
+		countingCAS := &countingCasClient{Client: cas}
+
+		provider := NewOperationProvider(p, operationparser.New(p), countingCAS, cp, WithContentCache(0))
+
+		for i := 0; i < 3; i++ {
+			file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+			require.NoError(t, err)
+			require.NotNil(t, file)
+		}
+
+		require.Equal(t, 3, countingCAS.reads())
+	})
+
+	t.Run("error - cached content exceeds maximum size", func(t *testing.T) {
+		countingCAS := &countingCasClient{Client: cas}
+
+		provider := NewOperationProvider(p, operationparser.New(p), countingCAS, cp, WithContentCache(10))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.NotNil(t, file)
+
+		file, err = provider.readFromCAS(context.Background(), ChunkFileType, address, 20)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "exceeded maximum size 20")
+
+		require.Equal(t, 2, countingCAS.reads())
+	})
+
+	t.Run("error - content that exceeds maximum size is not cached", func(t *testing.T) {
+		countingCAS := &countingCasClient{Client: cas}
+
+		provider := NewOperationProvider(p, operationparser.New(p), countingCAS, cp, WithContentCache(10))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, 20)
+		require.Error(t, err)
+		require.Nil(t, file)
+
+		file, err = provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.NotNil(t, file)
+
+		require.Equal(t, 2, countingCAS.reads())
+	})
+
+	t.Run("success - transient CAS errors are retried", func(t *testing.T) {
+		failingCAS := &failingCasClient{Client: cas, failures: 2, err: errors.New("connection reset")}
+
+		provider := NewOperationProvider(p, operationparser.New(p), failingCAS, cp,
+			WithCASReadRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+		require.Equal(t, 3, failingCAS.reads)
+	})
+
+	t.Run("error - retry attempts exhausted", func(t *testing.T) {
+		failingCAS := &failingCasClient{Client: cas, failures: 5, err: errors.New("connection reset")}
+
+		provider := NewOperationProvider(p, operationparser.New(p), failingCAS, cp,
+			WithCASReadRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "retrieve CAS content at uri["+address+"]: connection reset")
+		require.Equal(t, 3, failingCAS.reads)
+	})
+
+	t.Run("success - error message containing 'not found' is retried", func(t *testing.T) {
+		failingCAS := &failingCasClient{Client: cas, failures: 2, err: errors.New("dial tcp: lookup ipfs: host not found")}
+
+		provider := NewOperationProvider(p, operationparser.New(p), failingCAS, cp,
+			WithCASReadRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+		require.Equal(t, 3, failingCAS.reads)
+	})
+
+	t.Run("error - not found is not retried", func(t *testing.T) {
+		failingCAS := &failingCasClient{Client: cas}
+
+		provider := NewOperationProvider(p, operationparser.New(p), failingCAS, cp,
+			WithCASReadRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, "invalid", maxFileSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "not found")
+		require.Equal(t, 1, failingCAS.reads)
+	})
+
+	t.Run("error - content exceeds maximum size is not retried", func(t *testing.T) {
+		failingCAS := &failingCasClient{Client: cas}
+
+		provider := NewOperationProvider(p, operationparser.New(p), failingCAS, cp,
+			WithCASReadRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, 20)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "exceeded maximum size 20")
+		require.Equal(t, 1, failingCAS.reads)
+	})
+
+	t.Run("error - read from CAS error", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), mocks.NewMockCasClient(errors.New("CAS error")), cp)
+
+		file, err := provider.getChunkFile(context.Background(), "address")
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), " retrieve CAS content at uri[address]: CAS error")
+	})
+
+	t.Run("error - content exceeds maximum size", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, 20)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "exceeded maximum size 20")
+	})
+
+	t.Run("error - content exceeds maximum decompressed size", func(t *testing.T) {
+		p2 := protocol.Protocol{
+			CompressionAlgorithm:         compressionAlgorithm,
+			MaxMemoryDecompressionFactor: 1,
+		}
+
+		provider := NewOperationProvider(p2, operationparser.New(p2), cas, cp)
+
+		testContent, err := cp.Compress(compressionAlgorithm, []byte(sampleChunkFile))
+		require.NoError(t, err)
+		testAddress, err := cas.Write(testContent)
+		require.NoError(t, err)
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, testAddress, 247)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "decompressed content size exceeded maximum decompressed content size 247")
+
+		// decompression provider without streaming support decompresses whole content first
+		provider = NewOperationProvider(p2, operationparser.New(p2), cas, &bufferedDecompressionProvider{dp: cp})
+
+		file, err = provider.readFromCAS(context.Background(), ChunkFileType, testAddress, 247)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "decompressed content size 267 exceeded maximum decompressed content size 247")
+	})
+
+	t.Run("error - decompression stops once maximum decompressed size is exceeded", func(t *testing.T) {
+		p2 := protocol.Protocol{
+			CompressionAlgorithm:         compressionAlgorithm,
+			MaxMemoryDecompressionFactor: 10,
+		}
+
+		// highly compressible content (decompressed size is far above compressed size times decompression factor)
+		content := bytes.Repeat([]byte(`{"deltas":[]}`), 1000000)
+
+		testContent, err := cp.Compress(compressionAlgorithm, content)
+		require.NoError(t, err)
+		testAddress, err := cas.Write(testContent)
+		require.NoError(t, err)
+
+		dp := &countingStreamDecompressionProvider{dp: cp}
+
+		provider := NewOperationProvider(p2, operationparser.New(p2), cas, dp)
+
+		maxSize := uint(len(testContent))
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, testAddress, maxSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), fmt.Sprintf("maximum decompressed content size %d", 10*maxSize))
+		require.LessOrEqual(t, dp.written, int(10*maxSize))
+		require.Less(t, dp.written, len(content))
+	})
+
+	t.Run("error - decompression error", func(t *testing.T) {
+		p2 := protocol.Protocol{
+			MaxChunkFileSize:             maxFileSize,
+			CompressionAlgorithm:         "alg",
+			MaxMemoryDecompressionFactor: 3,
+		}
+
+		provider := NewOperationProvider(p2, operationparser.New(p2), cas, cp)
+
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "compression algorithm 'alg' not supported")
+	})
+}
+
+func TestHandler_GetCorePoofFile(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := protocol.Protocol{
+		MaxProofFileSize:             maxFileSize,
+		CompressionAlgorithm:         compressionAlgorithm,
+		MaxMemoryDecompressionFactor: 3,
+	}
+
+	cas := mocks.NewMockCasClient(nil)
+	content, err := cp.Compress(compressionAlgorithm, []byte("{}"))
+	require.NoError(t, err)
+	uri, err := cas.Write(content)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		file, err := provider.getCoreProofFile(context.Background(), uri)
+		require.NoError(t, err)
+		require.NotNil(t, file)
+	})
+
+	t.Run("error - core proof file exceeds maximum size", func(t *testing.T) {
+		lowMaxFileSize := protocol.Protocol{MaxProofFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
+		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
+
+		file, err := provider.getCoreProofFile(context.Background(), uri)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "exceeded maximum size 10")
+	})
+
+	t.Run("error - parse core proof file error (invalid JSON)", func(t *testing.T) {
+		content, err := cp.Compress(compressionAlgorithm, []byte("invalid"))
+		require.NoError(t, err)
+		address, err := cas.Write(content)
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+		file, err := provider.getCoreProofFile(context.Background(), address)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "failed to parse content for core proof file")
+	})
+
+	t.Run("error - validate core proof file (invalid signed data)", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		// invalidate signed data for first recover
+		batchFiles.CoreProof.Operations.Recover[0] = "invalid-jws"
+
+		invalid, err := json.Marshal(batchFiles.CoreProof)
+		require.NoError(t, err)
+
+		cas := mocks.NewMockCasClient(nil)
+		content, err := cp.Compress(compressionAlgorithm, invalid)
+		require.NoError(t, err)
+		address, err := cas.Write(content)
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+		file, err := provider.getCoreProofFile(context.Background(), address)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "failed to validate signed data for recover[0]")
+	})
+}
+
+func TestHandler_ValidateCorePoofFile(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+
+	t.Run("success", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreProofFile(batchFiles.CoreProof)
+		require.NoError(t, err)
+	})
+
+	t.Run("error - invalid signed data for recover", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		// invalidate signed data for first recover
+		batchFiles.CoreProof.Operations.Recover[0] = "recover-jws"
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreProofFile(batchFiles.CoreProof)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to validate signed data for recover[0]")
+	})
+
+	t.Run("error - invalid signed data for deactivate", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		// invalidate signed data for first deactivate
+		batchFiles.CoreProof.Operations.Deactivate[0] = "deactivate-jws"
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreProofFile(batchFiles.CoreProof)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to validate signed data for deactivate[0]")
+	})
+
+	t.Run("success - validate IPFS CID", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		// set valid IPFS CID
+		batchFiles.CoreIndex.CoreProofFileURI = sampleCasURI
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		require.NoError(t, err)
+	})
+
+	t.Run("error - core proof URI too long", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreIndex.CoreProofFileURI = longValue
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "core proof URI: CAS URI length[118] exceeds maximum CAS URI length[100]")
+	})
+
+	t.Run("error - provisional index URI too long", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreIndex.ProvisionalIndexFileURI = longValue
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateCoreIndexFile(batchFiles.CoreIndex)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "provisional index URI: CAS URI length[118] exceeds maximum CAS URI length[100]")
+	})
+}
+
+func TestHandler_GetProvisionalPoofFile(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := protocol.Protocol{
+		MaxProofFileSize:             maxFileSize,
+		CompressionAlgorithm:         compressionAlgorithm,
+		MaxMemoryDecompressionFactor: 3,
+	}
+
+	cas := mocks.NewMockCasClient(nil)
+	content, err := cp.Compress(compressionAlgorithm, []byte("{}"))
+	require.NoError(t, err)
+	uri, err := cas.Write(content)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		file, err := provider.getProvisionalProofFile(context.Background(), uri)
+		require.NoError(t, err)
+		require.NotNil(t, file)
+	})
+
+	t.Run("error - core provisional file exceeds maximum size", func(t *testing.T) {
+		lowMaxFileSize := protocol.Protocol{MaxProofFileSize: 10, CompressionAlgorithm: compressionAlgorithm}
+		provider := NewOperationProvider(lowMaxFileSize, operationparser.New(p), cas, cp)
+
+		file, err := provider.getProvisionalProofFile(context.Background(), uri)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "exceeded maximum size 10")
+	})
+
+	t.Run("error - parse provisional proof file error (invalid JSON)", func(t *testing.T) {
+		content, err := cp.Compress(compressionAlgorithm, []byte("invalid"))
+		require.NoError(t, err)
+		address, err := cas.Write(content)
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+		file, err := provider.getProvisionalProofFile(context.Background(), address)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "failed to parse content for provisional proof file")
+	})
+
+	t.Run("error - validate provisional proof file (invalid signed data)", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		// invalidate signed data for first update
+		batchFiles.ProvisionalProof.Operations.Update[0] = "invalid-jws"
+
+		invalid, err := json.Marshal(batchFiles.ProvisionalProof)
+		require.NoError(t, err)
+
+		cas := mocks.NewMockCasClient(nil)
+		content, err := cp.Compress(compressionAlgorithm, invalid)
+		require.NoError(t, err)
+		address, err := cas.Write(content)
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+		file, err := provider.getProvisionalProofFile(context.Background(), address)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "failed to validate signed data for update[0]")
+	})
+}
+
+func TestHandler_ValidateProvisionalPoofFile(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+
+	t.Run("success", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateProvisionalProofFile(batchFiles.ProvisionalProof)
+		require.NoError(t, err)
+	})
+
+	t.Run("error - invalid signed data for update", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		// invalidate signed data for first update
+		batchFiles.ProvisionalProof.Operations.Update[0] = "jws"
+
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+		err = provider.validateProvisionalProofFile(batchFiles.ProvisionalProof)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to validate signed data for update[0]")
+	})
+}
+
+func TestHandler_GetBatchFiles(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	cas := mocks.NewMockCasClient(nil)
+
+	updateOp, err := generateOperation(1, operation.TypeUpdate)
+	require.NoError(t, err)
+
+	recoverOp, err := generateOperation(2, operation.TypeRecover)
+	require.NoError(t, err)
+
+	ppf := &models.ProvisionalProofFile{
+		Operations: models.ProvisionalProofOperations{
+			Update: []string{updateOp.SignedData},
+		},
+	}
+
+	ppfURI, err := writeToCAS(ppf, cas)
+	require.NoError(t, err)
+
+	cf := &models.ChunkFile{Deltas: []*model.DeltaModel{recoverOp.Delta, updateOp.Delta}}
+
+	chunkURI, err := writeToCAS(cf, cas)
+	require.NoError(t, err)
+
+	pif := &models.ProvisionalIndexFile{
+		Chunks:                  []models.Chunk{{ChunkFileURI: chunkURI}},
 		ProvisionalProofFileURI: ppfURI,
 		Operations: &models.ProvisionalOperations{
 			Update: []models.OperationReference{
@@ -1146,18 +2091,115 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 		p := newMockProtocolClient().Protocol
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.NoError(t, err)
 		require.NotNil(t, file)
 	})
 
+	t.Run("success - batch files are retrieved concurrently", func(t *testing.T) {
+		const delay = 100 * time.Millisecond
+
+		p := newMockProtocolClient().Protocol
+
+		expected, err := NewOperationProvider(p, operationparser.New(p), cas, cp).getBatchFiles(context.Background(), af)
+		require.NoError(t, err)
+
+		provider := NewOperationProvider(p, operationparser.New(p), &delayedCasClient{Client: cas, delay: delay}, cp)
+
+		start := time.Now()
+
+		file, err := provider.getBatchFiles(context.Background(), af)
+		require.NoError(t, err)
+		require.Equal(t, expected, file)
+
+		// sequential retrieval of core proof, provisional index, provisional proof and chunk files takes four delays
+		require.Less(t, int64(time.Since(start)), int64(3*delay))
+	})
+
+	t.Run("error - concurrent CAS read error", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+
+		casWithErr := &delayedCasClient{Client: mocks.NewMockCasClient(errors.New("CAS error")), delay: time.Millisecond}
+
+		provider := NewOperationProvider(p, operationparser.New(p), casWithErr, cp)
+
+		file, err := provider.getBatchFiles(context.Background(), af)
+		require.Error(t, err)
+		require.Nil(t, file)
+
+		// error of the read that fails first is returned
+		require.True(t,
+			strings.Contains(err.Error(), fmt.Sprintf("retrieve CAS content at uri[%s]: CAS error", cpfURI)) ||
+				strings.Contains(err.Error(), fmt.Sprintf("retrieve CAS content at uri[%s]: CAS error", pifURI)),
+			err.Error())
+	})
+
+	t.Run("error - pending core proof file read is cancelled on provisional file read error", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+
+		casClient := &failingURICasClient{Client: cas, failURI: pifURI, timeout: 5 * time.Second}
+
+		provider := NewOperationProvider(p, operationparser.New(p), casClient, cp)
+
+		start := time.Now()
+
+		file, err := provider.getBatchFiles(context.Background(), af)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), fmt.Sprintf("retrieve CAS content at uri[%s]: CAS error", pifURI))
+
+		// core proof file read would otherwise block until timeout
+		require.Less(t, int64(time.Since(start)), int64(casClient.timeout))
+	})
+
+	t.Run("error - pending chunk file read is cancelled on provisional proof file read error", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+
+		// provisional index file has to be read before provisional proof and chunk files are read concurrently
+		casClient := &failingURICasClient{Client: cas, failURI: ppfURI, passURIs: []string{pifURI}, timeout: 5 * time.Second}
+
+		provider := NewOperationProvider(p, operationparser.New(p), casClient, cp)
+
+		start := time.Now()
+
+		files, err := provider.getProvisionalFiles(context.Background(), pifURI)
+		require.Error(t, err)
+		require.Nil(t, files)
+		require.Contains(t, err.Error(), fmt.Sprintf("retrieve CAS content at uri[%s]: CAS error", ppfURI))
+
+		// chunk file read would otherwise block until timeout
+		require.Less(t, int64(time.Since(start)), int64(casClient.timeout))
+	})
+
+	t.Run("error - chunk file contains fewer deltas than implied by provisional index file", func(t *testing.T) {
+		p := newMockProtocolClient().Protocol
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		shortChunkURI, err := writeToCAS(&models.ChunkFile{Deltas: []*model.DeltaModel{recoverOp.Delta}}, cas)
+		require.NoError(t, err)
+
+		shortPIF := *pif
+		shortPIF.Chunks = []models.Chunk{{ChunkFileURI: shortChunkURI}}
+
+		shortPIFURI, err := writeToCAS(&shortPIF, cas)
+		require.NoError(t, err)
+
+		shortAF := *af
+		shortAF.ProvisionalIndexFileURI = shortPIFURI
+
+		file, err := provider.getBatchFiles(context.Background(), &shortAF)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "chunk delta count mismatch")
+	})
+
 	t.Run("error - retrieve provisional index file", func(t *testing.T) {
 		p := newMockProtocolClient().Protocol
 		p.MaxProvisionalIndexFileSize = 10
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -1169,7 +2211,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 7")
@@ -1205,7 +2247,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 			CoreProofFileURI:        "",
 		}
 
-		file, err := provider.getBatchFiles(af2)
+		file, err := provider.getBatchFiles(context.Background(), af2)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "failed to unmarshal provisional proof file: invalid character")
@@ -1217,7 +2259,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 
 		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
 
-		file, err := provider.getBatchFiles(af)
+		file, err := provider.getBatchFiles(context.Background(), af)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "exceeded maximum size 10")
@@ -1249,7 +2291,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 			ProvisionalIndexFileURI: pif2URI,
 		}
 
-		file, err := provider.getBatchFiles(cif)
+		file, err := provider.getBatchFiles(context.Background(), cif)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "missing provisional proof file URI")
@@ -1288,7 +2330,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 			},
 		}
 
-		file, err := provider.getBatchFiles(cif)
+		file, err := provider.getBatchFiles(context.Background(), cif)
 		require.Error(t, err)
 		require.Nil(t, file)
 		require.Contains(t, err.Error(), "number of recover ops[1] in core index doesn't match number of recover ops[0] in core proof")
@@ -1301,7 +2343,7 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 		missingChunkURI, err := writeToCAS(&models.ProvisionalIndexFile{}, cas)
 		require.NoError(t, err)
 
-		file, err := provider.getBatchFiles(&models.CoreIndexFile{
+		file, err := provider.getBatchFiles(context.Background(), &models.CoreIndexFile{
 			ProvisionalIndexFileURI: missingChunkURI,
 		})
 		require.Error(t, err)
@@ -1310,6 +2352,146 @@ func TestHandler_GetBatchFiles(t *testing.T) {
 	})
 }
 
+func TestHandler_MultipleChunkFiles(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := newMockProtocolClient().Protocol
+
+	createOp1, err := generateOperation(1, operation.TypeCreate)
+	require.NoError(t, err)
+
+	createOp2, err := generateOperation(2, operation.TypeCreate)
+	require.NoError(t, err)
+
+	recoverOp, err := generateOperation(3, operation.TypeRecover)
+	require.NoError(t, err)
+
+	updateOp, err := generateOperation(4, operation.TypeUpdate)
+	require.NoError(t, err)
+
+	require.NotEqual(t, createOp1.Delta, createOp2.Delta)
+	require.NotEqual(t, createOp2.Delta, recoverOp.Delta)
+	require.NotEqual(t, recoverOp.Delta, updateOp.Delta)
+
+	// deltas are ordered create, recover, update; second chunk file starts in the middle of that order
+	chunks := []*models.ChunkFile{
+		{Deltas: []*model.DeltaModel{createOp1.Delta, createOp2.Delta}},
+		{Deltas: []*model.DeltaModel{recoverOp.Delta, updateOp.Delta}},
+	}
+
+	writeBatch := func(cas cas.Client, chunks []*models.ChunkFile) string {
+		var chunkRefs []models.Chunk
+
+		for _, chunk := range chunks {
+			chunkURI, err := writeToCAS(chunk, cas)
+			require.NoError(t, err)
+
+			chunkRefs = append(chunkRefs, models.Chunk{ChunkFileURI: chunkURI})
+		}
+
+		ppfURI, err := writeToCAS(&models.ProvisionalProofFile{
+			Operations: models.ProvisionalProofOperations{Update: []string{updateOp.SignedData}},
+		}, cas)
+		require.NoError(t, err)
+
+		pifURI, err := writeToCAS(&models.ProvisionalIndexFile{
+			Chunks:                  chunkRefs,
+			ProvisionalProofFileURI: ppfURI,
+			Operations: &models.ProvisionalOperations{
+				Update: []models.OperationReference{{DidSuffix: updateOp.UniqueSuffix, RevealValue: updateOp.RevealValue}},
+			},
+		}, cas)
+		require.NoError(t, err)
+
+		cpfURI, err := writeToCAS(&models.CoreProofFile{
+			Operations: models.CoreProofOperations{Recover: []string{recoverOp.SignedData}},
+		}, cas)
+		require.NoError(t, err)
+
+		cifURI, err := writeToCAS(&models.CoreIndexFile{
+			ProvisionalIndexFileURI: pifURI,
+			CoreProofFileURI:        cpfURI,
+			Operations: &models.CoreOperations{
+				Create: []models.CreateReference{{SuffixData: createOp1.SuffixData}, {SuffixData: createOp2.SuffixData}},
+				Recover: []models.OperationReference{
+					{DidSuffix: recoverOp.UniqueSuffix, RevealValue: recoverOp.RevealValue},
+				},
+			},
+		}, cas)
+		require.NoError(t, err)
+
+		return "4." + cifURI
+	}
+
+	t.Run("success - deltas from all chunk files are mapped to operations in order", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		ops, _, err := provider.getTxnOperations(context.Background(),
+			&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: writeBatch(cas, chunks)})
+		require.NoError(t, err)
+		require.Len(t, ops, 4)
+
+		expected := map[string]*model.Operation{
+			createOp1.UniqueSuffix: createOp1,
+			createOp2.UniqueSuffix: createOp2,
+			recoverOp.UniqueSuffix: recoverOp,
+			updateOp.UniqueSuffix:  updateOp,
+		}
+		require.Len(t, expected, 4)
+
+		for _, op := range ops {
+			expectedOp, ok := expected[op.UniqueSuffix]
+			require.True(t, ok)
+			require.Equal(t, expectedOp.Type, op.Type)
+			require.Equal(t, expectedOp.Delta, op.Delta)
+		}
+
+		anchoredOps, err := provider.GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: writeBatch(cas, chunks)})
+		require.NoError(t, err)
+		require.Len(t, anchoredOps, 4)
+	})
+
+	t.Run("error - missing deltas in second chunk file", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		anchorString := writeBatch(cas, []*models.ChunkFile{
+			chunks[0],
+			{Deltas: []*model.DeltaModel{recoverOp.Delta}},
+		})
+
+		ops, err := provider.GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), "doesn't match number of deltas[3]")
+	})
+
+	t.Run("success - deltas are mapped in chunk file order", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		// delta commitments are verified when operations are applied; assembly maps deltas by position only
+		anchorString := writeBatch(cas, []*models.ChunkFile{chunks[1], chunks[0]})
+
+		ops, _, err := provider.getTxnOperations(context.Background(), &txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+		require.NoError(t, err)
+		require.Len(t, ops, 4)
+
+		deltas := make(map[string]*model.DeltaModel)
+		for _, op := range ops {
+			deltas[op.UniqueSuffix] = op.Delta
+		}
+
+		require.Equal(t, recoverOp.Delta, deltas[createOp1.UniqueSuffix])
+		require.Equal(t, updateOp.Delta, deltas[createOp2.UniqueSuffix])
+		require.Equal(t, createOp1.Delta, deltas[recoverOp.UniqueSuffix])
+		require.Equal(t, createOp2.Delta, deltas[updateOp.UniqueSuffix])
+	})
+}
+
 func TestHandler_assembleBatchOperations(t *testing.T) {
 	p := newMockProtocolClient().Protocol
 
@@ -1319,26 +2501,139 @@ func TestHandler_assembleBatchOperations(t *testing.T) {
 		batchFiles, err := generateDefaultBatchFiles()
 		require.NoError(t, err)
 
-		anchoredOps, err := provider.assembleAnchoredOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		require.NoError(t, err)
+		require.Equal(t, 4, len(ops))
+	})
+
+	t.Run("error - recover signed data error ", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreProof.Operations.Recover[0] = ""
+
+		ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), "failed to validate signed data for recover[0]: missing signed data")
+	})
+
+	t.Run("error - core/provisional index, chunk file operation number mismatch", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+
+		createOp, err := generateOperation(1, operation.TypeCreate)
+		require.NoError(t, err)
+
+		updateOp, err := generateOperation(2, operation.TypeUpdate)
+		require.NoError(t, err)
+
+		deactivateOp, err := generateOperation(3, operation.TypeDeactivate)
 		require.NoError(t, err)
-		require.Equal(t, 4, len(anchoredOps))
+
+		cif := &models.CoreIndexFile{
+			ProvisionalIndexFileURI: "hash",
+			Operations: &models.CoreOperations{
+				Create: []models.CreateReference{{SuffixData: createOp.SuffixData}},
+				Deactivate: []models.OperationReference{
+					{DidSuffix: deactivateOp.UniqueSuffix, RevealValue: deactivateOp.RevealValue},
+				},
+			},
+		}
+
+		pif := &models.ProvisionalIndexFile{
+			Chunks: []models.Chunk{},
+			Operations: &models.ProvisionalOperations{
+				Update: []models.OperationReference{{DidSuffix: updateOp.UniqueSuffix, RevealValue: updateOp.RevealValue}},
+			},
+		}
+
+		// don't add update operation delta to chunk file in order to cause error
+		cf := &models.ChunkFile{Deltas: []*model.DeltaModel{createOp.Delta}}
+
+		cpf := &models.CoreProofFile{
+			Operations: models.CoreProofOperations{
+				Deactivate: []string{deactivateOp.SignedData},
+			},
+		}
+
+		ppf := &models.ProvisionalProofFile{
+			Operations: models.ProvisionalProofOperations{
+				Update: []string{updateOp.SignedData},
+			},
+		}
+
+		batchFiles := &batchFiles{
+			CoreIndex:        cif,
+			CoreProof:        cpf,
+			ProvisionalIndex: pif,
+			ProvisionalProof: ppf,
+			Chunk:            cf,
+		}
+
+		ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(),
+			"number of create+recover+update operations[2] doesn't match number of deltas[1]")
 	})
 
-	t.Run("error - recover signed data error ", func(t *testing.T) {
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+	t.Run("error - duplicate operations found in core/provisional index files", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+
+		createOp, err := generateOperation(1, operation.TypeCreate)
+		require.NoError(t, err)
+
+		updateOp, err := generateOperation(2, operation.TypeUpdate)
+		require.NoError(t, err)
+
+		deactivateOp, err := generateOperation(3, operation.TypeDeactivate)
+		require.NoError(t, err)
+
+		cif := &models.CoreIndexFile{
+			ProvisionalIndexFileURI: "hash",
+			Operations: &models.CoreOperations{
+				Create: []models.CreateReference{{SuffixData: createOp.SuffixData}},
+				Deactivate: []models.OperationReference{
+					{DidSuffix: "test-suffix", RevealValue: deactivateOp.RevealValue},
+				},
+			},
+		}
+
+		pif := &models.ProvisionalIndexFile{
+			Chunks: []models.Chunk{},
+			Operations: &models.ProvisionalOperations{
+				Update: []models.OperationReference{
+					{DidSuffix: "test-suffix", RevealValue: updateOp.RevealValue},
+				},
+			},
+		}
+
+		cf := &models.ChunkFile{Deltas: []*model.DeltaModel{createOp.Delta}}
 
-		batchFiles, err := generateDefaultBatchFiles()
-		require.NoError(t, err)
+		cpf := &models.CoreProofFile{
+			Operations: models.CoreProofOperations{
+				Deactivate: []string{deactivateOp.SignedData, deactivateOp.SignedData},
+			},
+		}
 
-		batchFiles.CoreProof.Operations.Recover[0] = ""
+		batchFiles := &batchFiles{
+			CoreIndex:        cif,
+			CoreProof:        cpf,
+			ProvisionalIndex: pif,
+			Chunk:            cf,
+		}
 
-		anchoredOps, err := provider.assembleAnchoredOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
 		require.Error(t, err)
-		require.Nil(t, anchoredOps)
-		require.Contains(t, err.Error(), "failed to validate signed data for recover[0]: missing signed data")
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(),
+			"check for duplicate suffixes in core/provisional index files: duplicate values found [test-suffix]: "+
+				"suffix[test-suffix] has deactivate operation in core index file and update operation in provisional index file")
 	})
 
-	t.Run("error - core/provisional index, chunk file operation number mismatch", func(t *testing.T) {
+	t.Run("error - DID is created in core index file and updated in provisional index file", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
 
 		createOp, err := generateOperation(1, operation.TypeCreate)
@@ -1347,57 +2642,77 @@ func TestHandler_assembleBatchOperations(t *testing.T) {
 		updateOp, err := generateOperation(2, operation.TypeUpdate)
 		require.NoError(t, err)
 
-		deactivateOp, err := generateOperation(3, operation.TypeDeactivate)
+		createSuffix, err := model.GetUniqueSuffix(createOp.SuffixData, p.MultihashAlgorithms)
 		require.NoError(t, err)
 
 		cif := &models.CoreIndexFile{
 			ProvisionalIndexFileURI: "hash",
 			Operations: &models.CoreOperations{
 				Create: []models.CreateReference{{SuffixData: createOp.SuffixData}},
-				Deactivate: []models.OperationReference{
-					{DidSuffix: deactivateOp.UniqueSuffix, RevealValue: deactivateOp.RevealValue},
-				},
 			},
 		}
 
 		pif := &models.ProvisionalIndexFile{
 			Chunks: []models.Chunk{},
 			Operations: &models.ProvisionalOperations{
-				Update: []models.OperationReference{{DidSuffix: updateOp.UniqueSuffix, RevealValue: updateOp.RevealValue}},
+				Update: []models.OperationReference{
+					{DidSuffix: createSuffix, RevealValue: updateOp.RevealValue},
+				},
 			},
 		}
 
-		// don't add update operation delta to chunk file in order to cause error
-		cf := &models.ChunkFile{Deltas: []*model.DeltaModel{createOp.Delta}}
-
-		cpf := &models.CoreProofFile{
-			Operations: models.CoreProofOperations{
-				Deactivate: []string{deactivateOp.SignedData},
+		batchFiles := &batchFiles{
+			CoreIndex:        cif,
+			CoreProof:        &models.CoreProofFile{},
+			ProvisionalIndex: pif,
+			ProvisionalProof: &models.ProvisionalProofFile{
+				Operations: models.ProvisionalProofOperations{Update: []string{updateOp.SignedData}},
 			},
+			Chunk: &models.ChunkFile{Deltas: []*model.DeltaModel{createOp.Delta, updateOp.Delta}},
 		}
 
-		ppf := &models.ProvisionalProofFile{
-			Operations: models.ProvisionalProofOperations{
-				Update: []string{updateOp.SignedData},
+		ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(), fmt.Sprintf("check for duplicate suffixes in core/provisional index files: "+
+			"duplicate values found [%s]: suffix[%s] has create operation in core index file and update operation in provisional index file",
+			createSuffix, createSuffix))
+	})
+
+	t.Run("error - duplicate operations found in provisional index file", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+
+		updateOp, err := generateOperation(2, operation.TypeUpdate)
+		require.NoError(t, err)
+
+		pif := &models.ProvisionalIndexFile{
+			Chunks: []models.Chunk{},
+			Operations: &models.ProvisionalOperations{
+				Update: []models.OperationReference{
+					{DidSuffix: "test-suffix", RevealValue: updateOp.RevealValue},
+					{DidSuffix: "test-suffix", RevealValue: updateOp.RevealValue},
+				},
 			},
 		}
 
 		batchFiles := &batchFiles{
-			CoreIndex:        cif,
-			CoreProof:        cpf,
+			CoreIndex:        &models.CoreIndexFile{ProvisionalIndexFileURI: "hash"},
+			CoreProof:        &models.CoreProofFile{},
 			ProvisionalIndex: pif,
-			ProvisionalProof: ppf,
-			Chunk:            cf,
+			ProvisionalProof: &models.ProvisionalProofFile{
+				Operations: models.ProvisionalProofOperations{Update: []string{updateOp.SignedData, updateOp.SignedData}},
+			},
+			Chunk: &models.ChunkFile{Deltas: []*model.DeltaModel{updateOp.Delta, updateOp.Delta}},
 		}
 
-		anchoredOps, err := provider.assembleAnchoredOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
 		require.Error(t, err)
-		require.Nil(t, anchoredOps)
+		require.Nil(t, ops)
 		require.Contains(t, err.Error(),
-			"number of create+recover+update operations[2] doesn't match number of deltas[1]")
+			"check for duplicate suffixes in provisional index file: duplicate values found [test-suffix]")
 	})
 
-	t.Run("error - duplicate operations found in core/provisional index files", func(t *testing.T) {
+	t.Run("error - duplicate operations found in core index file", func(t *testing.T) {
 		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
 
 		createOp, err := generateOperation(1, operation.TypeCreate)
@@ -1414,7 +2729,8 @@ func TestHandler_assembleBatchOperations(t *testing.T) {
 			Operations: &models.CoreOperations{
 				Create: []models.CreateReference{{SuffixData: createOp.SuffixData}},
 				Deactivate: []models.OperationReference{
-					{DidSuffix: "test-suffix", RevealValue: deactivateOp.RevealValue},
+					{DidSuffix: deactivateOp.UniqueSuffix, RevealValue: deactivateOp.RevealValue},
+					{DidSuffix: deactivateOp.UniqueSuffix, RevealValue: deactivateOp.RevealValue},
 				},
 			},
 		}
@@ -1423,7 +2739,7 @@ func TestHandler_assembleBatchOperations(t *testing.T) {
 			Chunks: []models.Chunk{},
 			Operations: &models.ProvisionalOperations{
 				Update: []models.OperationReference{
-					{DidSuffix: "test-suffix", RevealValue: updateOp.RevealValue},
+					{DidSuffix: updateOp.UniqueSuffix, RevealValue: updateOp.RevealValue},
 				},
 			},
 		}
@@ -1443,65 +2759,417 @@ func TestHandler_assembleBatchOperations(t *testing.T) {
 			Chunk:            cf,
 		}
 
-		anchoredOps, err := provider.assembleAnchoredOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
-		require.Error(t, err)
-		require.Nil(t, anchoredOps)
-		require.Contains(t, err.Error(),
-			"check for duplicate suffixes in core/provisional index files: duplicate values found [test-suffix]")
-	})
+		ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.Contains(t, err.Error(),
+			"check for duplicate suffixes in core index files: duplicate values found [deactivate-3]")
+	})
+}
+
+func TestHandler_TamperedDelta(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+	parser := operationparser.New(p)
+
+	batchFiles, err := generateDefaultBatchFiles()
+	require.NoError(t, err)
+
+	// swap create and update deltas in chunk file
+	deltas := batchFiles.Chunk.Deltas
+	deltas[0], deltas[2] = deltas[2], deltas[0]
+
+	provider := NewOperationProvider(p, parser, nil, nil)
+
+	// delta hash is not verified during assembly since operations with tampered delta still have to be
+	// persisted: create and recover operations advance recovery commitment even if delta doesn't match
+	ops, _, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+	require.NoError(t, err)
+
+	anchoredOps, err := createAnchoredOperations(ops)
+	require.NoError(t, err)
+	require.Len(t, anchoredOps, 4)
+
+	applier := operationapplier.New(p, parser, doccomposer.New())
+
+	t.Run("create - delta mismatch is caught and delta is not applied", func(t *testing.T) {
+		require.Equal(t, operation.TypeCreate, anchoredOps[0].Type)
+
+		rm, err := applier.Apply(anchoredOps[0], &protocol.ResolutionModel{})
+		require.NoError(t, err)
+		require.Empty(t, rm.Doc)
+		require.Empty(t, rm.UpdateCommitment)
+		require.NotEmpty(t, rm.RecoveryCommitment)
+	})
+
+	t.Run("update - delta mismatch is caught and operation is rejected", func(t *testing.T) {
+		require.Equal(t, operation.TypeUpdate, anchoredOps[2].Type)
+
+		rm, err := applier.Apply(anchoredOps[2], &protocol.ResolutionModel{Doc: make(document.Document)})
+		require.Error(t, err)
+		require.Nil(t, rm)
+		require.Contains(t, err.Error(), "update delta doesn't match delta hash")
+	})
+}
+
+func TestHandler_SkipUnparseableOperations(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+
+	t.Run("success - batch with one unparseable operation", func(t *testing.T) {
+		cas := mocks.NewMockCasClient(nil)
+
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		// corrupt signed data for update operation
+		batchFiles.ProvisionalProof.Operations.Update[0] = "invalid"
+
+		coreIndexURI, err := writeBatchFilesToCAS(batchFiles, cas)
+		require.NoError(t, err)
+
+		sidetreeTxn := &txn.SidetreeTxn{
+			Namespace:         defaultNS,
+			AnchorString:      (&AnchorData{NumberOfOperations: 4, CoreIndexFileURI: coreIndexURI}).GetAnchorString(),
+			TransactionNumber: 1,
+			TransactionTime:   1,
+		}
+
+		strict := NewOperationProvider(p, operationparser.New(p), cas, compression.New(compression.WithDefaultAlgorithms()))
+
+		txnOps, err := strict.GetTxnOperations(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+		require.Contains(t, err.Error(), "failed to validate signed data for update[0]")
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas,
+			compression.New(compression.WithDefaultAlgorithms()), WithSkipUnparseableOperations(true))
+
+		txnOps, skipped, err := provider.GetTxnOperationsWithSkipCount(context.Background(), sidetreeTxn)
+		require.NoError(t, err)
+		require.Equal(t, 1, skipped)
+		require.Len(t, txnOps, 3)
+
+		for _, op := range txnOps {
+			require.NotEqual(t, operation.TypeUpdate, op.Type)
+		}
+
+		txnOps, err = provider.GetTxnOperations(sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, 3)
+	})
+
+	t.Run("success - skip operations with invalid recover signed data and delta", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil, WithSkipUnparseableOperations(true))
+
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreProof.Operations.Recover[0] = ""
+		batchFiles.Chunk.Deltas[0] = &model.DeltaModel{}
+
+		ops, skipped, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		require.NoError(t, err)
+		require.Equal(t, 2, skipped)
+		require.Len(t, ops, 2)
+		require.Equal(t, operation.TypeUpdate, ops[0].Type)
+		require.Equal(t, operation.TypeDeactivate, ops[1].Type)
+	})
+
+	t.Run("success - skip deactivate operation with invalid signed data", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil, WithSkipUnparseableOperations(true))
+
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreProof.Operations.Deactivate[0] = "invalid"
+
+		ops, skipped, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		require.NoError(t, err)
+		require.Equal(t, 1, skipped)
+		require.Len(t, ops, 3)
+	})
+
+	t.Run("success - batch file validation doesn't fail on unparseable operations", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), nil, nil, WithSkipUnparseableOperations(true))
+
+		require.NoError(t, provider.validateCoreProofFile(&models.CoreProofFile{
+			Operations: models.CoreProofOperations{Recover: []string{"invalid"}},
+		}))
+		require.NoError(t, provider.validateProvisionalProofFile(&models.ProvisionalProofFile{
+			Operations: models.ProvisionalProofOperations{Update: []string{"invalid"}},
+		}))
+		require.NoError(t, provider.validateChunkFile(&models.ChunkFile{Deltas: []*model.DeltaModel{{}}}))
+		require.NoError(t, provider.validateCoreIndexOperations(&models.CoreOperations{
+			Create: []models.CreateReference{{}},
+		}))
+	})
+}
+
+func TestHandler_Namespace(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := newMockProtocolClient().Protocol
+
+	batchFiles, err := generateDefaultBatchFiles()
+	require.NoError(t, err)
+
+	cas := &recordingCasClient{MockCasClient: mocks.NewMockCasClient(nil)}
+
+	coreIndexURI, err := writeBatchFilesToCAS(batchFiles, cas)
+	require.NoError(t, err)
+
+	anchorString := "4." + coreIndexURI
+
+	t.Run("success - matching namespace", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp, WithNamespace(defaultNS))
+
+		ops, err := provider.GetTxnOperations(&txn.SidetreeTxn{Namespace: defaultNS, AnchorString: anchorString})
+		require.NoError(t, err)
+		require.Len(t, ops, 4)
+	})
+
+	t.Run("success - namespace is not validated by default", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		ops, err := provider.GetTxnOperations(&txn.SidetreeTxn{Namespace: "did:other", AnchorString: anchorString})
+		require.NoError(t, err)
+		require.Len(t, ops, 4)
+	})
+
+	t.Run("error - mismatched namespace", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp, WithNamespace(defaultNS))
+
+		readsBefore := len(cas.readURIs())
+
+		ops, err := provider.GetTxnOperations(&txn.SidetreeTxn{Namespace: "did:other", AnchorString: anchorString})
+		require.Error(t, err)
+		require.Nil(t, ops)
+		require.EqualError(t, err, "transaction namespace[did:other] doesn't match operation provider namespace[did:sidetree]")
+
+		err = provider.ForEachTxnOperation(context.Background(), &txn.SidetreeTxn{AnchorString: anchorString},
+			func(*operation.AnchoredOperation) error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "transaction namespace[] doesn't match")
+
+		// batch files are not read for rejected transactions
+		require.Equal(t, readsBefore, len(cas.readURIs()))
+	})
+}
+
+func TestHandler_Logger(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+
+	l := &capturingLogger{}
+
+	provider := NewOperationProvider(p, operationparser.New(p), nil, nil,
+		WithSkipUnparseableOperations(true), WithLogger(l))
+
+	batchFiles, err := generateDefaultBatchFiles()
+	require.NoError(t, err)
+
+	batchFiles.CoreProof.Operations.Deactivate[0] = "invalid"
+
+	ops, skipped, err := provider.assembleValidOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+	require.NoError(t, err)
+	require.Equal(t, 1, skipped)
+	require.Len(t, ops, 3)
+
+	require.NotEmpty(t, l.debugMessages())
+	require.Len(t, l.warnMessages(), 1)
+	require.Contains(t, l.warnMessages()[0], "skipping unparseable deactivate operation for suffix")
+}
+
+func TestHandler_LenientAssembly(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+
+	cas := mocks.NewMockCasClient(nil)
+
+	batchFiles, err := generateDefaultBatchFiles()
+	require.NoError(t, err)
+
+	// corrupt suffix data for create operation
+	batchFiles.CoreIndex.Operations.Create[0].SuffixData = &model.SuffixDataModel{}
+
+	coreIndexURI, err := writeBatchFilesToCAS(batchFiles, cas)
+	require.NoError(t, err)
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      (&AnchorData{NumberOfOperations: 4, CoreIndexFileURI: coreIndexURI}).GetAnchorString(),
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	t.Run("error - strict assembly is default", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, compression.New(compression.WithDefaultAlgorithms()))
+
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, txnOps)
+	})
+
+	t.Run("success - valid operations are returned", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas,
+			compression.New(compression.WithDefaultAlgorithms()), WithLenientAssembly())
+
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, 3)
+
+		require.Equal(t, operation.TypeRecover, txnOps[0].Type)
+		require.Equal(t, operation.TypeUpdate, txnOps[1].Type)
+		require.Equal(t, operation.TypeDeactivate, txnOps[2].Type)
+	})
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	p := newMockProtocolClient().Protocol
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	cas := mocks.NewMockCasClient(nil)
+
+	batchFiles, err := generateDefaultBatchFiles()
+	require.NoError(t, err)
+
+	coreIndexURI, err := writeBatchFilesToCAS(batchFiles, cas)
+	require.NoError(t, err)
+
+	sidetreeTxn := &txn.SidetreeTxn{
+		Namespace:         defaultNS,
+		AnchorString:      (&AnchorData{NumberOfOperations: 4, CoreIndexFileURI: coreIndexURI}).GetAnchorString(),
+		TransactionNumber: 1,
+		TransactionTime:   1,
+	}
+
+	readFile := func(uri string) ([]byte, int) {
+		compressed, e := cas.Read(uri)
+		require.NoError(t, e)
+
+		content, e := cp.Decompress(p.CompressionAlgorithm, compressed)
+		require.NoError(t, e)
+
+		return content, len(compressed)
+	}
+
+	cifContent, cifSize := readFile(coreIndexURI)
+	cif, err := models.ParseCoreIndexFile(cifContent)
+	require.NoError(t, err)
+
+	pifContent, pifSize := readFile(cif.ProvisionalIndexFileURI)
+	pif, err := models.ParseProvisionalIndexFile(pifContent)
+	require.NoError(t, err)
+
+	_, cpfSize := readFile(cif.CoreProofFileURI)
+	_, ppfSize := readFile(pif.ProvisionalProofFileURI)
+	_, chunkSize := readFile(pif.Chunks[0].ChunkFileURI)
+
+	t.Run("success", func(t *testing.T) {
+		metrics := newMockMetrics()
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp, WithMetrics(metrics))
+
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, 4)
+
+		require.Equal(t, map[string]int{
+			CoreIndexFileType:        cifSize,
+			CoreProofFileType:        cpfSize,
+			ProvisionalIndexFileType: pifSize,
+			ProvisionalProofFileType: ppfSize,
+			ChunkFileType:            chunkSize,
+		}, metrics.getBytes())
+
+		durations := metrics.getDurations()
+		require.Len(t, durations, 5)
+		require.Contains(t, durations, CoreIndexFileType)
+		require.Contains(t, durations, ChunkFileType)
+	})
+
+	t.Run("success - content from cache is not reported", func(t *testing.T) {
+		metrics := newMockMetrics()
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp, WithMetrics(metrics), WithContentCache(10))
+
+		_, err := provider.readFromCAS(context.Background(), CoreIndexFileType, coreIndexURI, maxFileSize)
+		require.NoError(t, err)
+
+		_, err = provider.readFromCAS(context.Background(), CoreIndexFileType, coreIndexURI, maxFileSize)
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]int{CoreIndexFileType: cifSize}, metrics.getBytes())
+	})
+
+	t.Run("success - no metrics for failed read", func(t *testing.T) {
+		metrics := newMockMetrics()
+
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp, WithMetrics(metrics))
+
+		_, err := provider.readFromCAS(context.Background(), ChunkFileType, "invalid", maxFileSize)
+		require.Error(t, err)
+
+		require.Empty(t, metrics.getBytes())
+		require.Empty(t, metrics.getDurations())
+	})
+
+	t.Run("success - default no-op metrics", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp)
+
+		txnOps, err := provider.GetTxnOperations(sidetreeTxn)
+		require.NoError(t, err)
+		require.Len(t, txnOps, 4)
+	})
+}
+
+func TestHandler_ContentHashVerification(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+	p := protocol.Protocol{
+		MaxChunkFileSize:             maxFileSize,
+		CompressionAlgorithm:         compressionAlgorithm,
+		MaxMemoryDecompressionFactor: 3,
+	}
+
+	cas := mocks.NewMockCasClient(nil)
+	content, err := cp.Compress(compressionAlgorithm, []byte("{}"))
+	require.NoError(t, err)
+	address, err := cas.Write(content)
+	require.NoError(t, err)
 
-	t.Run("error - duplicate operations found in core index file", func(t *testing.T) {
-		provider := NewOperationProvider(p, operationparser.New(p), nil, nil)
+	otherContent, err := cp.Compress(compressionAlgorithm, []byte(`{"other":"content"}`))
+	require.NoError(t, err)
 
-		createOp, err := generateOperation(1, operation.TypeCreate)
-		require.NoError(t, err)
+	tamperedCAS := &tamperingCasClient{content: otherContent}
 
-		updateOp, err := generateOperation(2, operation.TypeUpdate)
-		require.NoError(t, err)
+	t.Run("success - content matches uri", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), cas, cp, WithContentHashVerification())
 
-		deactivateOp, err := generateOperation(3, operation.TypeDeactivate)
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
 		require.NoError(t, err)
+		require.Equal(t, "{}", string(file))
+	})
 
-		cif := &models.CoreIndexFile{
-			ProvisionalIndexFileURI: "hash",
-			Operations: &models.CoreOperations{
-				Create: []models.CreateReference{{SuffixData: createOp.SuffixData}},
-				Deactivate: []models.OperationReference{
-					{DidSuffix: deactivateOp.UniqueSuffix, RevealValue: deactivateOp.RevealValue},
-					{DidSuffix: deactivateOp.UniqueSuffix, RevealValue: deactivateOp.RevealValue},
-				},
-			},
-		}
+	t.Run("success - verification is disabled by default", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), tamperedCAS, cp)
 
-		pif := &models.ProvisionalIndexFile{
-			Chunks: []models.Chunk{},
-			Operations: &models.ProvisionalOperations{
-				Update: []models.OperationReference{
-					{DidSuffix: updateOp.UniqueSuffix, RevealValue: updateOp.RevealValue},
-				},
-			},
-		}
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.NoError(t, err)
+		require.Equal(t, `{"other":"content"}`, string(file))
+	})
 
-		cf := &models.ChunkFile{Deltas: []*model.DeltaModel{createOp.Delta}}
+	t.Run("error - content doesn't match uri", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), tamperedCAS, cp, WithContentHashVerification())
 
-		cpf := &models.CoreProofFile{
-			Operations: models.CoreProofOperations{
-				Deactivate: []string{deactivateOp.SignedData, deactivateOp.SignedData},
-			},
-		}
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, address, maxFileSize)
+		require.Error(t, err)
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), fmt.Sprintf("uri[%s]: content hash mismatch", address))
+	})
 
-		batchFiles := &batchFiles{
-			CoreIndex:        cif,
-			CoreProof:        cpf,
-			ProvisionalIndex: pif,
-			Chunk:            cf,
-		}
+	t.Run("error - uri is not a multihash", func(t *testing.T) {
+		provider := NewOperationProvider(p, operationparser.New(p), tamperedCAS, cp, WithContentHashVerification())
 
-		anchoredOps, err := provider.assembleAnchoredOperations(batchFiles, &txn.SidetreeTxn{Namespace: defaultNS})
+		file, err := provider.readFromCAS(context.Background(), ChunkFileType, "uri", maxFileSize)
 		require.Error(t, err)
-		require.Nil(t, anchoredOps)
-		require.Contains(t, err.Error(),
-			"check for duplicate suffixes in core index files: duplicate values found [deactivate-3]")
+		require.Nil(t, file)
+		require.Contains(t, err.Error(), "uri[uri]: verify content hash")
 	})
 }
 
@@ -1557,6 +3225,75 @@ func TestValidateBatchFileCounts(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "number of create+recover+update operations[3] doesn't match number of deltas[0]")
 	})
+
+	t.Run("error - chunk file contains fewer deltas than implied by index files", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.Chunk.Deltas = batchFiles.Chunk.Deltas[:len(batchFiles.Chunk.Deltas)-1]
+
+		err = validateBatchFileCounts(batchFiles)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "chunk delta count mismatch: number of create+recover+update operations[3] doesn't match number of deltas[2]")
+	})
+}
+
+func TestValidateOperationCount(t *testing.T) {
+	// default batch files: create, recover and deactivate in core index file; update in provisional index file
+	const expected = 4
+
+	t.Run("success", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		require.NoError(t, validateOperationCount(expected, batchFiles))
+	})
+
+	t.Run("success - core index file only", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreIndex.Operations.Create = nil
+		batchFiles.CoreIndex.Operations.Recover = nil
+		batchFiles.ProvisionalIndex = nil
+		batchFiles.Chunk = nil
+
+		require.NoError(t, validateOperationCount(1, batchFiles))
+	})
+
+	t.Run("error - anchor string mismatch", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		err = validateOperationCount(expected+1, batchFiles)
+		require.Error(t, err)
+		require.Contains(t, err.Error(),
+			"number of txn ops[4] doesn't match anchor string num of ops[5]: core[3] provisional[1]")
+	})
+
+	t.Run("error - core index file mismatch", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.CoreIndex.Operations.Deactivate = nil
+
+		err = validateOperationCount(expected, batchFiles)
+		require.Error(t, err)
+		require.Contains(t, err.Error(),
+			"number of txn ops[3] doesn't match anchor string num of ops[4]: core[2] provisional[1]")
+	})
+
+	t.Run("error - provisional index file mismatch", func(t *testing.T) {
+		batchFiles, err := generateDefaultBatchFiles()
+		require.NoError(t, err)
+
+		batchFiles.ProvisionalIndex.Operations.Update = nil
+
+		err = validateOperationCount(expected, batchFiles)
+		require.Error(t, err)
+		require.Contains(t, err.Error(),
+			"number of txn ops[3] doesn't match anchor string num of ops[4]: core[3] provisional[0]")
+	})
 }
 
 func generateDefaultBatchFiles() (*batchFiles, error) {
@@ -1637,6 +3374,38 @@ func generateDefaultBatchFiles() (*batchFiles, error) {
 	}, nil
 }
 
+// writeBatchFilesToCAS writes batch files to CAS (replacing file URIs with actual CAS URIs)
+// and returns core index file URI.
+func writeBatchFilesToCAS(bf *batchFiles, cas cas.Client) (string, error) {
+	chunkURI, err := writeToCAS(bf.Chunk, cas)
+	if err != nil {
+		return "", err
+	}
+
+	ppfURI, err := writeToCAS(bf.ProvisionalProof, cas)
+	if err != nil {
+		return "", err
+	}
+
+	bf.ProvisionalIndex.Chunks = []models.Chunk{{ChunkFileURI: chunkURI}}
+	bf.ProvisionalIndex.ProvisionalProofFileURI = ppfURI
+
+	pifURI, err := writeToCAS(bf.ProvisionalIndex, cas)
+	if err != nil {
+		return "", err
+	}
+
+	cpfURI, err := writeToCAS(bf.CoreProof, cas)
+	if err != nil {
+		return "", err
+	}
+
+	bf.CoreIndex.ProvisionalIndexFileURI = pifURI
+	bf.CoreIndex.CoreProofFileURI = cpfURI
+
+	return writeToCAS(bf.CoreIndex, cas)
+}
+
 func writeToCAS(value interface{}, cas cas.Client) (string, error) {
 	bytes, err := canonicalizer.MarshalCanonical(value)
 	if err != nil {
@@ -1655,14 +3424,314 @@ func writeToCAS(value interface{}, cas cas.Client) (string, error) {
 
 func newMockProtocolClient() *mocks.MockProtocolClient {
 	pc := mocks.NewMockProtocolClient()
+
+	// test batches contain more operations than default mock protocol allows
+	pc.Protocol.MaxOperationCount = maxOperationCount
+
 	parser := operationparser.New(pc.Protocol)
 	dc := doccomposer.New()
 
 	pv := pc.CurrentVersion
+	pv.ProtocolReturns(pc.Protocol)
 	pv.OperationParserReturns(parser)
 	pv.DocumentComposerReturns(dc)
 
 	return pc
 }
 
+type delayedCasClient struct {
+	cas.Client
+	delay time.Duration
+}
+
+func (c *delayedCasClient) Read(address string) ([]byte, error) {
+	time.Sleep(c.delay)
+
+	return c.Client.Read(address)
+}
+
+// failingURICasClient fails reads of failURI immediately and serves reads of passURIs immediately;
+// reads of other URIs block until context is done (or timeout).
+type failingURICasClient struct {
+	cas.Client
+	failURI  string
+	passURIs []string
+	timeout  time.Duration
+}
+
+func (c *failingURICasClient) Read(address string) ([]byte, error) {
+	return c.ReadContext(context.Background(), address)
+}
+
+func (c *failingURICasClient) ReadContext(ctx context.Context, address string) ([]byte, error) {
+	if address == c.failURI {
+		return nil, errors.New("CAS error")
+	}
+
+	content, err := c.Client.Read(address)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uri := range c.passURIs {
+		if address == uri {
+			return content, nil
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(c.timeout):
+		return content, nil
+	}
+}
+
+type countingCasClient struct {
+	cas.Client
+	mutex sync.Mutex
+	count int
+}
+
+func (c *countingCasClient) Read(address string) ([]byte, error) {
+	c.mutex.Lock()
+	c.count++
+	c.mutex.Unlock()
+
+	return c.Client.Read(address)
+}
+
+type sizeCasClient struct {
+	countingCasClient
+	sizeErr error
+}
+
+func (c *sizeCasClient) Size(address string) (int, error) {
+	if c.sizeErr != nil {
+		return 0, c.sizeErr
+	}
+
+	content, err := c.Client.Read(address)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(content), nil
+}
+
+func (c *countingCasClient) reads() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.count
+}
+
+type mockMetrics struct {
+	mutex     sync.Mutex
+	bytes     map[string]int
+	durations map[string]time.Duration
+}
+
+func newMockMetrics() *mockMetrics {
+	return &mockMetrics{
+		bytes:     make(map[string]int),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+func (m *mockMetrics) CASReadDuration(fileType string, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.durations[fileType] += duration
+}
+
+func (m *mockMetrics) CASReadBytes(fileType string, size int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.bytes[fileType] += size
+}
+
+func (m *mockMetrics) getBytes() map[string]int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.bytes
+}
+
+func (m *mockMetrics) getDurations() map[string]time.Duration {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.durations
+}
+
+type pingCasClient struct {
+	cas.Client
+	err   error
+	pings int
+}
+
+func (c *pingCasClient) Ping(context.Context) error {
+	c.pings++
+
+	return c.err
+}
+
+type readOnlyCasClient struct{}
+
+func (c *readOnlyCasClient) Read(string) ([]byte, error) {
+	return nil, cas.ErrContentNotFound
+}
+
+type failingCasClient struct {
+	cas.Client
+	failures int
+	err      error
+	reads    int
+}
+
+func (c *failingCasClient) Read(address string) ([]byte, error) {
+	c.reads++
+
+	if c.reads <= c.failures {
+		return nil, c.err
+	}
+
+	return c.Client.Read(address)
+}
+
+// contextCasClient is context-aware CAS client; if blocking is set, context-aware reads and writes
+// block until context is done.
+type contextCasClient struct {
+	cas.Client
+	blocking bool
+	mutex    sync.Mutex
+	calls    int
+}
+
+func (c *contextCasClient) ReadContext(ctx context.Context, address string) ([]byte, error) {
+	if err := c.call(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.Client.Read(address)
+}
+
+func (c *contextCasClient) WriteContext(ctx context.Context, content []byte) (string, error) {
+	if err := c.call(ctx); err != nil {
+		return "", err
+	}
+
+	return c.Client.Write(content)
+}
+
+func (c *contextCasClient) call(ctx context.Context) error {
+	c.mutex.Lock()
+	c.calls++
+	c.mutex.Unlock()
+
+	if c.blocking {
+		<-ctx.Done()
+	}
+
+	return ctx.Err()
+}
+
+func (c *contextCasClient) callCount() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.calls
+}
+
+// tamperingCasClient returns the same content for any address.
+type tamperingCasClient struct {
+	content []byte
+}
+
+func (c *tamperingCasClient) Read(string) ([]byte, error) {
+	return c.content, nil
+}
+
+type noopCompressor struct{}
+
+func (c *noopCompressor) Compress(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func (c *noopCompressor) Decompress(data []byte) ([]byte, error) {
+	return data, nil
+}
+
 const sampleChunkFile = `{"chunks":[{"chunkFileUri":"EiDkiD-FuKC5mcsY4m0pd3OMTP7FAfo690gzN7-6JxcN1g"}],"operations":{"update":[{"didSuffix":"update-1","revealValue":"EiAdqFJ-x5QhwPq62DB9EfenKloqntykHJkZrwI6uxkoVQ"}]},"provisionalProofFileUri":"EiDdEHTL3VmFZO5hXoth8vTKnXgvfvW4lLJXyMjqs7ezUA"}`
+
+type capturingLogger struct {
+	mutex sync.Mutex
+	debug []string
+	warn  []string
+}
+
+func (l *capturingLogger) Debugf(msg string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.debug = append(l.debug, fmt.Sprintf(msg, args...))
+}
+
+func (l *capturingLogger) Warnf(msg string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.warn = append(l.warn, fmt.Sprintf(msg, args...))
+}
+
+func (l *capturingLogger) debugMessages() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.debug
+}
+
+func (l *capturingLogger) warnMessages() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.warn
+}
+
+// bufferedDecompressionProvider exposes decompression provider without streaming support.
+type bufferedDecompressionProvider struct {
+	dp decompressionProvider
+}
+
+func (p *bufferedDecompressionProvider) Decompress(alg string, data []byte) ([]byte, error) {
+	return p.dp.Decompress(alg, data)
+}
+
+// countingStreamDecompressionProvider counts decompressed bytes accepted by the writer.
+type countingStreamDecompressionProvider struct {
+	dp      *compression.Registry
+	written int
+}
+
+func (p *countingStreamDecompressionProvider) Decompress(alg string, data []byte) ([]byte, error) {
+	return p.dp.Decompress(alg, data)
+}
+
+func (p *countingStreamDecompressionProvider) DecompressStream(alg string, reader io.Reader, writer io.Writer) error {
+	return p.dp.DecompressStream(alg, reader, &countingWriter{w: writer, n: &p.written})
+}
+
+type countingWriter struct {
+	w io.Writer
+	n *int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += n
+
+	return n, err
+}
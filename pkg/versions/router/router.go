@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package router
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+)
+
+// ProtocolVersionRouter selects registered protocol version based on transaction time. Each protocol version
+// applies from its genesis time (inclusive) until the genesis time of the next registered version, which allows
+// a single node to process transaction history that spans protocol upgrades.
+type ProtocolVersionRouter struct {
+	versions []protocol.Version
+}
+
+// New creates protocol version router for the given protocol versions (in any order).
+func New(versions ...protocol.Version) (*ProtocolVersionRouter, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("missing protocol versions")
+	}
+
+	sorted := make([]protocol.Version, len(versions))
+	copy(sorted, versions)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Protocol().GenesisTime < sorted[j].Protocol().GenesisTime
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Protocol().GenesisTime == sorted[i-1].Protocol().GenesisTime {
+			return nil, fmt.Errorf("protocol versions[%s, %s] have the same genesis time[%d]",
+				sorted[i-1].Version(), sorted[i].Version(), sorted[i].Protocol().GenesisTime)
+		}
+	}
+
+	return &ProtocolVersionRouter{versions: sorted}, nil
+}
+
+// Current returns latest registered protocol version.
+func (r *ProtocolVersionRouter) Current() (protocol.Version, error) {
+	return r.versions[len(r.versions)-1], nil
+}
+
+// Get returns protocol version that applies at the given transaction time.
+func (r *ProtocolVersionRouter) Get(transactionTime uint64) (protocol.Version, error) {
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		if transactionTime >= r.versions[i].Protocol().GenesisTime {
+			return r.versions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("protocol parameters are not defined for transaction time: %d", transactionTime)
+}
+
+// ForTxn returns protocol version that applies to the given sidetree transaction.
+func (r *ProtocolVersionRouter) ForTxn(sidetreeTxn *txn.SidetreeTxn) (protocol.Version, error) {
+	if sidetreeTxn == nil {
+		return nil, fmt.Errorf("missing sidetree transaction")
+	}
+
+	return r.Get(sidetreeTxn.TransactionTime)
+}
+
+// OperationParser returns operation parser of the protocol version that applies to the given sidetree transaction.
+func (r *ProtocolVersionRouter) OperationParser(sidetreeTxn *txn.SidetreeTxn) (protocol.OperationParser, error) {
+	v, err := r.ForTxn(sidetreeTxn)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.OperationParser(), nil
+}
+
+// OperationProvider returns operation provider of the protocol version that applies to the given sidetree transaction.
+func (r *ProtocolVersionRouter) OperationProvider(sidetreeTxn *txn.SidetreeTxn) (protocol.OperationProvider, error) {
+	v, err := r.ForTxn(sidetreeTxn)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.OperationProvider(), nil
+}
+
+// DocumentComposer returns document composer of the protocol version that applies to the given sidetree transaction.
+func (r *ProtocolVersionRouter) DocumentComposer(sidetreeTxn *txn.SidetreeTxn) (protocol.DocumentComposer, error) {
+	v, err := r.ForTxn(sidetreeTxn)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.DocumentComposer(), nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+const upgradeTime = 500
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var pc protocol.Client
+		pc, err := New(newVersion("1.0", 0))
+		require.NoError(t, err)
+		require.NotNil(t, pc)
+	})
+
+	t.Run("error - missing protocol versions", func(t *testing.T) {
+		r, err := New()
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "missing protocol versions")
+	})
+
+	t.Run("error - same genesis time", func(t *testing.T) {
+		r, err := New(newVersion("1.0", 0), newVersion("1.1", 0))
+		require.Error(t, err)
+		require.Nil(t, r)
+		require.Contains(t, err.Error(), "protocol versions[1.0, 1.1] have the same genesis time[0]")
+	})
+}
+
+func TestProtocolVersionRouter(t *testing.T) {
+	v1 := newVersion("1.0", 0)
+	v2 := newVersion("1.1", upgradeTime)
+
+	// versions are registered out of order on purpose
+	r, err := New(v2, v1)
+	require.NoError(t, err)
+
+	t.Run("success - current", func(t *testing.T) {
+		current, err := r.Current()
+		require.NoError(t, err)
+		require.Same(t, v2, current)
+	})
+
+	t.Run("success - transaction before upgrade", func(t *testing.T) {
+		sidetreeTxn := &txn.SidetreeTxn{TransactionTime: upgradeTime - 1}
+
+		v, err := r.ForTxn(sidetreeTxn)
+		require.NoError(t, err)
+		require.Equal(t, "1.0", v.Version())
+
+		parser, err := r.OperationParser(sidetreeTxn)
+		require.NoError(t, err)
+		require.Same(t, v1.OperationParser(), parser)
+
+		provider, err := r.OperationProvider(sidetreeTxn)
+		require.NoError(t, err)
+		require.Same(t, v1.OperationProvider(), provider)
+
+		composer, err := r.DocumentComposer(sidetreeTxn)
+		require.NoError(t, err)
+		require.Same(t, v1.DocumentComposer(), composer)
+	})
+
+	t.Run("success - transaction at and after upgrade", func(t *testing.T) {
+		for _, transactionTime := range []uint64{upgradeTime, upgradeTime + 1} {
+			sidetreeTxn := &txn.SidetreeTxn{TransactionTime: transactionTime}
+
+			v, err := r.ForTxn(sidetreeTxn)
+			require.NoError(t, err)
+			require.Equal(t, "1.1", v.Version())
+
+			parser, err := r.OperationParser(sidetreeTxn)
+			require.NoError(t, err)
+			require.Same(t, v2.OperationParser(), parser)
+
+			provider, err := r.OperationProvider(sidetreeTxn)
+			require.NoError(t, err)
+			require.Same(t, v2.OperationProvider(), provider)
+
+			composer, err := r.DocumentComposer(sidetreeTxn)
+			require.NoError(t, err)
+			require.Same(t, v2.DocumentComposer(), composer)
+		}
+	})
+
+	t.Run("error - transaction before first genesis time", func(t *testing.T) {
+		rr, err := New(newVersion("1.1", upgradeTime))
+		require.NoError(t, err)
+
+		sidetreeTxn := &txn.SidetreeTxn{TransactionTime: upgradeTime - 1}
+
+		v, err := rr.ForTxn(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "protocol parameters are not defined for transaction time: 499")
+
+		parser, err := rr.OperationParser(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, parser)
+
+		provider, err := rr.OperationProvider(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, provider)
+
+		composer, err := rr.DocumentComposer(sidetreeTxn)
+		require.Error(t, err)
+		require.Nil(t, composer)
+	})
+
+	t.Run("error - missing transaction", func(t *testing.T) {
+		v, err := r.ForTxn(nil)
+		require.Error(t, err)
+		require.Nil(t, v)
+		require.Contains(t, err.Error(), "missing sidetree transaction")
+	})
+}
+
+func newVersion(version string, genesisTime uint64) *mocks.ProtocolVersion {
+	p := mocks.GetDefaultProtocolParameters()
+	p.GenesisTime = genesisTime
+
+	v := mocks.GetProtocolVersion(p)
+	v.VersionReturns(version)
+
+	// distinct instances so that routing can be verified
+	v.OperationParserReturns(&mocks.OperationParser{})
+	v.OperationProviderReturns(&mocks.OperationProvider{})
+	v.DocumentComposerReturns(&mocks.DocumentComposer{})
+
+	return v
+}